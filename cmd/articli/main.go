@@ -1,21 +1,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime/debug"
+	"syscall"
 
+	"github.com/k8scat/articli/pkg/cmd/auth"
+	configcmd "github.com/k8scat/articli/pkg/cmd/config"
 	"github.com/k8scat/articli/pkg/cmd/csdn"
 	"github.com/k8scat/articli/pkg/cmd/github"
 	"github.com/k8scat/articli/pkg/cmd/gitlab"
+	"github.com/k8scat/articli/pkg/cmd/image"
+	"github.com/k8scat/articli/pkg/cmd/journal"
+	"github.com/k8scat/articli/pkg/cmd/lint"
 	"github.com/k8scat/articli/pkg/cmd/oschina"
+	"github.com/k8scat/articli/pkg/cmd/publish"
+	"github.com/k8scat/articli/pkg/cmd/schedule"
+	"github.com/k8scat/articli/pkg/cmd/state"
+	synccmd "github.com/k8scat/articli/pkg/cmd/sync"
 
 	"github.com/juju/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/k8scat/articli/internal/config"
 	"github.com/k8scat/articli/pkg/cmd/juejin"
+	"github.com/k8scat/articli/pkg/table"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
 var (
@@ -23,8 +38,14 @@ var (
 	commit  = "none"
 	date    = "unknown"
 
-	cfgFile string
-	cfg     *config.Config
+	cfgFile  string
+	cfg      *config.Config
+	profile  string
+	output   string
+	template string
+
+	debugHTTP bool
+	recordDir string
 
 	rootCmd = &cobra.Command{
 		Use:   "acli",
@@ -44,6 +65,41 @@ var (
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "An alternative config file")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Use the accounts of the named profile instead of the current ones")
+	rootCmd.PersistentFlags().StringVar(&output, "output", string(table.FormatTable), "Output format of lists, one of table, json, yaml and csv")
+	rootCmd.PersistentFlags().StringVar(&template, "template", "", "Format each row of lists with a go template, e.g. '{{.id}}'")
+
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug", false, "Log the requests and responses of the platforms to stderr")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save the requests and responses of the platforms in the directory as test fixtures")
+
+	cobra.OnInitialize(initOutput, initTransport, initProfile)
+}
+
+// initOutput runs after the flags are parsed
+func initOutput() {
+	if err := table.SetOutput(output, template); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// initTransport runs after the flags are parsed and before the platform clients are created
+func initTransport() {
+	if debugHTTP {
+		transport.DebugOutput = os.Stderr
+	}
+	if recordDir != "" {
+		if err := os.MkdirAll(recordDir, 0755); err != nil {
+			fmt.Printf("create record dir failed: %s\n", err)
+			os.Exit(1)
+		}
+		transport.RecordDir = recordDir
+	}
+}
+
+// initProfile runs after the flags are parsed and before the platform clients are created
+func initProfile() {
+	cfg.Profile = profile
 }
 
 func initConfig() {
@@ -53,9 +109,31 @@ func initConfig() {
 
 	var err error
 	cfg, err = config.ParseConfig(cfgFile)
-	if err != nil {
+	if os.IsNotExist(errors.Cause(err)) {
 		cfg = new(config.Config)
+		return
 	}
+	// A config which can not be parsed is never overwritten by an empty one
+	if err != nil {
+		fmt.Printf("parse config %s failed: %s\n", cfgFile, err)
+		os.Exit(1)
+	}
+}
+
+// signalContext is canceled on the first interrupt, so that the running requests are canceled
+// and the finished results are still reported, the second interrupt exits immediately.
+func signalContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ch
+		fmt.Fprintln(os.Stderr, "interrupted, canceling the running requests, press Ctrl-C again to exit")
+		cancel()
+		<-ch
+		os.Exit(130)
+	}()
+	return ctx
 }
 
 func main() {
@@ -68,13 +146,22 @@ func main() {
 	initConfig()
 
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(auth.NewAuthCmd(cfgFile, cfg))
+	rootCmd.AddCommand(configcmd.NewConfigCmd(cfg))
 	rootCmd.AddCommand(juejin.NewJuejinCmd(cfgFile, cfg))
 	rootCmd.AddCommand(github.NewGithubCmd(cfgFile, cfg))
 	rootCmd.AddCommand(oschina.NewOSChinaCmd(cfgFile, cfg))
 	rootCmd.AddCommand(csdn.NewCSDNCmd(cfgFile, cfg))
 	rootCmd.AddCommand(gitlab.NewGitlabCmd(cfgFile, cfg))
+	rootCmd.AddCommand(publish.NewPublishCmd(cfg))
+	rootCmd.AddCommand(synccmd.NewSyncCmd(cfg))
+	rootCmd.AddCommand(image.NewImageCmd())
+	rootCmd.AddCommand(schedule.NewScheduleCmd(cfg))
+	rootCmd.AddCommand(lint.NewLintCmd(cfg))
+	rootCmd.AddCommand(state.NewStateCmd())
+	rootCmd.AddCommand(journal.NewJournalCmd(cfg))
 
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(signalContext()); err != nil {
 		log.Fatalf("execute command failed: %+v", errors.Trace(err))
 	}
 }
@@ -3,17 +3,52 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 
 	"github.com/juju/errors"
 	"gopkg.in/yaml.v3"
+
+	"github.com/k8scat/articli/pkg/utils"
 )
 
+// DefaultProfile is the name of the accounts in Config.Platforms
+const DefaultProfile = "default"
+
 type Config struct {
+	// Platforms holds the accounts of the default profile and the settings shared by all the profiles,
+	// so the config of a single account is the default profile without any migration
 	Platforms Platforms `yaml:"platforms,omitempty"`
+	// Profiles are the named accounts besides the default ones, e.g. a company account.
+	// The credentials are never inherited, the other settings not set in a profile are inherited from Platforms.
+	Profiles map[string]*Platforms `yaml:"profiles,omitempty"`
+	// Current is the profile used by each platform, the default profile is used if not set
+	Current   map[string]string `yaml:"current,omitempty"`
+	ImageHost ImageHost         `yaml:"image_host,omitempty"`
+	// CredentialStore is where the secrets of the accounts are saved instead of the config file
+	CredentialStore CredentialStore `yaml:"credential_store,omitempty"`
+
+	// Profile is selected by --profile or pinned in an article, it overrides Current of all the platforms
+	Profile string `yaml:"-"`
+
+	// creds loads the secrets from the credential store on the first use, nil if they are saved in the config file
+	creds *credentials
+}
+
+// ImageHost is where the local images in articles are uploaded to
+type ImageHost struct {
+	// Type is one of platform, github and gitlab, defaults to platform,
+	// which uploads images to the article platform itself
+	Type string `yaml:"type,omitempty"`
+	// Repo is owner/repo for github, or project id for gitlab
+	Repo   string `yaml:"repo,omitempty"`
+	Branch string `yaml:"branch,omitempty"`
+	Dir    string `yaml:"dir,omitempty"`
 }
 
 type Platforms struct {
@@ -24,27 +59,85 @@ type Platforms struct {
 	Gitlab  Gitlab  `yaml:"gitlab,omitempty"`
 }
 
+// HTTP overrides the http settings of a platform client
+type HTTP struct {
+	// BaseURL is the base url of the platform api, e.g. the api of a GitHub Enterprise Server or a local mock,
+	// it is the url of the home page for oschina
+	BaseURL   string        `yaml:"base_url,omitempty"`
+	UserAgent string        `yaml:"user_agent,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+	// Proxy is the url of the http proxy, the proxy in the environment variables is used if empty
+	Proxy string `yaml:"proxy,omitempty"`
+	// RateLimit is the requests per second, the default limit of the platform is used if 0,
+	// and the requests are not limited if negative
+	RateLimit float64 `yaml:"rate_limit,omitempty"`
+}
+
+// merge overrides the settings which are set in o
+func (h *HTTP) merge(o HTTP) {
+	if o.BaseURL != "" {
+		h.BaseURL = o.BaseURL
+	}
+	if o.UserAgent != "" {
+		h.UserAgent = o.UserAgent
+	}
+	if o.Timeout != 0 {
+		h.Timeout = o.Timeout
+	}
+	if o.Proxy != "" {
+		h.Proxy = o.Proxy
+	}
+	if o.RateLimit != 0 {
+		h.RateLimit = o.RateLimit
+	}
+}
+
+// Validate checks the proxy url
+func (h *HTTP) Validate() error {
+	if h.Proxy == "" {
+		return nil
+	}
+	u, err := url.Parse(h.Proxy)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.Errorf("invalid proxy: %s", h.Proxy)
+	}
+	return nil
+}
+
 type Juejin struct {
 	Cookie string `yaml:"cookie,omitempty"`
+	// ImageXURL is the base url of the imagex api which hosts the uploaded images
+	ImageXURL string `yaml:"imagex_url,omitempty"`
+	// TagCacheTTL is how long the cached tags and categories are used before they are listed again,
+	// the default ttl is used if 0, and they are not cached in the config dir if negative
+	TagCacheTTL time.Duration `yaml:"tag_cache_ttl,omitempty"`
+	// TagAliases maps the tag names in articles to the names in juejin, e.g. golang: Go
+	TagAliases map[string]string `yaml:"tag_aliases,omitempty"`
+	HTTP       `yaml:",inline"`
 }
 
 type OSChina struct {
 	Cookie string `yaml:"cookie,omitempty"`
+	HTTP   `yaml:",inline"`
 }
 
 type Github struct {
 	Token string `yaml:"token,omitempty"`
+	HTTP  `yaml:",inline"`
 }
 
 type Gitlab struct {
-	BaseURL string `yaml:"base_url,omitempty"`
-	Token   string `yaml:"token,omitempty"`
+	Token string `yaml:"token,omitempty"`
+	HTTP  `yaml:",inline"`
 }
 
 type CSDN struct {
 	Cookie    string `yaml:"cookie,omitempty"`
 	APIKey    string `yaml:"api_key,omitempty"`
 	APISecret string `yaml:"api_secret,omitempty"`
+	// ImageURL is the base url of the image service which signs the image uploads
+	ImageURL string `yaml:"image_url,omitempty"`
+	HTTP     `yaml:",inline"`
 }
 
 func ParseConfig(cfgFile string) (*Config, error) {
@@ -53,17 +146,268 @@ func ParseConfig(cfgFile string) (*Config, error) {
 		return nil, errors.Trace(err)
 	}
 	cfg := new(Config)
-	err = yaml.Unmarshal(b, &cfg)
-	return cfg, errors.Trace(err)
+	if err = yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg.creds = newCredentials(cfg.CredentialStore)
+	return cfg, nil
+}
+
+// Validate checks the profile names and the http settings of all the platforms
+func (c *Config) Validate() error {
+	if err := c.Platforms.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for name, p := range c.Profiles {
+		if name == "" || name == DefaultProfile {
+			return errors.Errorf("invalid profile name %q", name)
+		}
+		if p == nil {
+			continue
+		}
+		if err := p.validate(); err != nil {
+			return errors.Annotatef(err, "profile %s", name)
+		}
+	}
+	return nil
+}
+
+func (p *Platforms) validate() error {
+	settings := []struct {
+		name string
+		http *HTTP
+	}{
+		{"juejin", &p.Juejin.HTTP},
+		{"oschina", &p.OSChina.HTTP},
+		{"github", &p.Github.HTTP},
+		{"csdn", &p.CSDN.HTTP},
+		{"gitlab", &p.Gitlab.HTTP},
+	}
+	for _, s := range settings {
+		if err := s.http.Validate(); err != nil {
+			return errors.Annotate(err, s.name)
+		}
+	}
+	return nil
 }
 
+// HasAccount reports whether the credential of the named platform is set
+func (p *Platforms) HasAccount(platform string) bool {
+	switch platform {
+	case "juejin":
+		return p.Juejin.Cookie != ""
+	case "oschina":
+		return p.OSChina.Cookie != ""
+	case "csdn":
+		return p.CSDN.Cookie != ""
+	case "github":
+		return p.Github.Token != ""
+	case "gitlab":
+		return p.Gitlab.Token != ""
+	default:
+		return false
+	}
+}
+
+// WithProfile returns a copy of c which uses the named profile for all the platforms,
+// the secrets loaded from the credential store by the copy are not loaded into c
+func (c *Config) WithProfile(name string) *Config {
+	if c.creds == nil {
+		cc := *c
+		cc.Profile = name
+		return &cc
+	}
+	c.creds.mu.Lock()
+	defer c.creds.mu.Unlock()
+	cc := *c
+	cc.Profile = name
+	cc.Profiles = make(map[string]*Platforms, len(c.Profiles))
+	for k, p := range c.Profiles {
+		if p != nil {
+			pp := *p
+			p = &pp
+		}
+		cc.Profiles[k] = p
+	}
+	cc.creds = c.creds.fork()
+	return &cc
+}
+
+// ProfileOf returns the name of the profile used by the platform
+func (c *Config) ProfileOf(platform string) string {
+	if c.Profile != "" {
+		return c.Profile
+	}
+	if name := c.Current[platform]; name != "" {
+		return name
+	}
+	return DefaultProfile
+}
+
+// Account returns the accounts of the named profile to be updated, the profile is created if not found
+func (c *Config) Account(name string) *Platforms {
+	if name == "" || name == DefaultProfile {
+		return &c.Platforms
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Platforms)
+	}
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		p = new(Platforms)
+		c.Profiles[name] = p
+	}
+	return p
+}
+
+// ProfileNames returns the sorted names of the profiles which have an account of the platform
+func (c *Config) ProfileNames(platform string) []string {
+	names := make([]string, 0, len(c.Profiles)+1)
+	for name, p := range c.Profiles {
+		if p != nil && p.HasAccount(platform) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if c.Platforms.HasAccount(platform) {
+		names = append([]string{DefaultProfile}, names...)
+	}
+	return names
+}
+
+// Switch makes the named profile the current one of the platform
+func (c *Config) Switch(platform, name string) error {
+	if name == DefaultProfile {
+		delete(c.Current, platform)
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok || p == nil || !p.HasAccount(platform) {
+		return errors.NotFoundf("%s account of profile %q", platform, name)
+	}
+	if c.Current == nil {
+		c.Current = make(map[string]string)
+	}
+	c.Current[platform] = name
+	return nil
+}
+
+// account returns the accounts of the profile used by the platform, nil for the default profile
+func (c *Config) account(name string) *Platforms {
+	if name == DefaultProfile {
+		return nil
+	}
+	if p, ok := c.Profiles[name]; ok && p != nil {
+		return p
+	}
+	return new(Platforms)
+}
+
+// Juejin returns the settings of juejin with the account of the profile in use
+func (c *Config) Juejin() Juejin {
+	name := c.ProfileOf("juejin")
+	defer c.loadSecrets(name, "juejin")()
+	j := c.Platforms.Juejin
+	if p := c.account(name); p != nil {
+		j.Cookie = p.Juejin.Cookie
+		if p.Juejin.ImageXURL != "" {
+			j.ImageXURL = p.Juejin.ImageXURL
+		}
+		if p.Juejin.TagCacheTTL != 0 {
+			j.TagCacheTTL = p.Juejin.TagCacheTTL
+		}
+		if p.Juejin.TagAliases != nil {
+			j.TagAliases = p.Juejin.TagAliases
+		}
+		j.HTTP.merge(p.Juejin.HTTP)
+	}
+	j.Cookie = getenv(name, "juejin", "cookie", j.Cookie)
+	return j
+}
+
+// JuejinTagAliases returns the tag aliases of juejin in the profile in use,
+// which does not load the secrets from the credential store unlike Juejin
+func (c *Config) JuejinTagAliases() map[string]string {
+	if p := c.account(c.ProfileOf("juejin")); p != nil && p.Juejin.TagAliases != nil {
+		return p.Juejin.TagAliases
+	}
+	return c.Platforms.Juejin.TagAliases
+}
+
+// OSChina returns the settings of oschina with the account of the profile in use
+func (c *Config) OSChina() OSChina {
+	name := c.ProfileOf("oschina")
+	defer c.loadSecrets(name, "oschina")()
+	o := c.Platforms.OSChina
+	if p := c.account(name); p != nil {
+		o.Cookie = p.OSChina.Cookie
+		o.HTTP.merge(p.OSChina.HTTP)
+	}
+	o.Cookie = getenv(name, "oschina", "cookie", o.Cookie)
+	return o
+}
+
+// CSDN returns the settings of csdn with the account of the profile in use
+func (c *Config) CSDN() CSDN {
+	name := c.ProfileOf("csdn")
+	defer c.loadSecrets(name, "csdn")()
+	s := c.Platforms.CSDN
+	if p := c.account(name); p != nil {
+		s.Cookie, s.APIKey, s.APISecret = p.CSDN.Cookie, p.CSDN.APIKey, p.CSDN.APISecret
+		if p.CSDN.ImageURL != "" {
+			s.ImageURL = p.CSDN.ImageURL
+		}
+		s.HTTP.merge(p.CSDN.HTTP)
+	}
+	s.Cookie = getenv(name, "csdn", "cookie", s.Cookie)
+	s.APISecret = getenv(name, "csdn", "api_secret", s.APISecret)
+	return s
+}
+
+// Github returns the settings of github with the account of the profile in use
+func (c *Config) Github() Github {
+	name := c.ProfileOf("github")
+	defer c.loadSecrets(name, "github")()
+	g := c.Platforms.Github
+	if p := c.account(name); p != nil {
+		g.Token = p.Github.Token
+		g.HTTP.merge(p.Github.HTTP)
+	}
+	g.Token = getenv(name, "github", "token", g.Token)
+	return g
+}
+
+// Gitlab returns the settings of gitlab with the account of the profile in use,
+// the base url is a part of the account as the profiles may use different servers
+func (c *Config) Gitlab() Gitlab {
+	name := c.ProfileOf("gitlab")
+	defer c.loadSecrets(name, "gitlab")()
+	g := c.Platforms.Gitlab
+	if p := c.account(name); p != nil {
+		g.Token = p.Gitlab.Token
+		g.HTTP.merge(p.Gitlab.HTTP)
+	}
+	g.Token = getenv(name, "gitlab", "token", g.Token)
+	return g
+}
+
+// SaveConfig writes cfg to cfgFile which is only readable by the owner,
+// the secrets are saved in the credential store instead if it is set
 func SaveConfig(cfgFile string, cfg *Config) error {
+	if cfg.creds != nil {
+		if err := cfg.creds.save(cfg.secrets()); err != nil {
+			return errors.Trace(err)
+		}
+		defer cfg.hideSecrets()()
+	}
 	b, err := yaml.Marshal(cfg)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = ioutil.WriteFile(cfgFile, b, 0644)
-	return errors.Trace(err)
+	return errors.Trace(utils.WriteFileAtomic(cfgFile, b, 0600))
 }
 
 func GetConfigDir() string {
@@ -80,3 +424,17 @@ func GetConfigDir() string {
 	}
 	return cfgDir
 }
+
+// GetCookie returns the cookie of the named article platform in the profile in use
+func (c *Config) GetCookie(platform string) string {
+	switch platform {
+	case "juejin":
+		return c.Juejin().Cookie
+	case "oschina":
+		return c.OSChina().Cookie
+	case "csdn":
+		return c.CSDN().Cookie
+	default:
+		return ""
+	}
+}
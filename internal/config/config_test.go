@@ -0,0 +1,260 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/internal/credential"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "config.yml")
+	content := `platforms:
+  github:
+    token: token
+    base_url: https://github.example.com/api/v3
+    user_agent: articli
+    timeout: 30s
+    proxy: http://127.0.0.1:7890
+  gitlab:
+    base_url: https://gitlab.com
+`
+	if err := ioutil.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseConfig(cfgFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "token", cfg.Platforms.Github.Token)
+	assert.Equal(t, "https://github.example.com/api/v3", cfg.Platforms.Github.BaseURL)
+	assert.Equal(t, "articli", cfg.Platforms.Github.UserAgent)
+	assert.Equal(t, 30*time.Second, cfg.Platforms.Github.Timeout)
+	assert.Equal(t, "https://gitlab.com", cfg.Platforms.Gitlab.BaseURL)
+
+	assert.Nil(t, SaveConfig(cfgFile, cfg))
+	saved, err := ParseConfig(cfgFile)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg, saved)
+
+	content = `platforms:
+  juejin:
+    proxy: 127.0.0.1
+`
+	if err := ioutil.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseConfig(cfgFile)
+	assert.NotNil(t, err)
+}
+
+func TestPlatformOptions(t *testing.T) {
+	cfg := &Config{
+		Platforms: Platforms{
+			Juejin: Juejin{
+				Cookie:    "cookie",
+				ImageXURL: "http://127.0.0.1:8080",
+				HTTP:      HTTP{Timeout: time.Second, Proxy: "http://127.0.0.1:7890", RateLimit: 2},
+			},
+			Github: Github{Token: "token"},
+		},
+		Profiles: map[string]*Platforms{
+			"company": {Juejin: Juejin{Cookie: "company"}},
+		},
+	}
+	opts := cfg.PlatformOptions("juejin")
+	assert.Equal(t, "cookie", opts.Cookie)
+	assert.Equal(t, "http://127.0.0.1:8080", opts.ImageURL)
+	assert.Equal(t, time.Second, opts.Timeout)
+	assert.Equal(t, 2.0, opts.RateLimit)
+	assert.Equal(t, GetConfigDir(), opts.CacheDir)
+	if assert.NotNil(t, opts.Proxy) {
+		assert.Equal(t, "127.0.0.1:7890", opts.Proxy.Host)
+	}
+	assert.Equal(t, "token", cfg.PlatformOptions("github").Token)
+	assert.Nil(t, cfg.PlatformOptions("unknown"))
+
+	// The other settings are inherited by the profile, but the credentials are not
+	opts = cfg.WithProfile("company").PlatformOptions("juejin")
+	assert.Equal(t, "company", opts.Cookie)
+	assert.Equal(t, time.Second, opts.Timeout)
+	assert.Equal(t, "", cfg.WithProfile("company").PlatformOptions("github").Token)
+
+	cfg.Platforms.Juejin.TagCacheTTL = -1
+	assert.Equal(t, "", cfg.PlatformOptions("juejin").CacheDir)
+}
+
+func TestProfiles(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "config.yml")
+	// The config of a single account is the default profile
+	content := `platforms:
+  juejin:
+    cookie: personal
+    proxy: http://127.0.0.1:7890
+  gitlab:
+    token: personal
+    base_url: https://gitlab.com
+`
+	if err := ioutil.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseConfig(cfgFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, DefaultProfile, cfg.ProfileOf("juejin"))
+	assert.Equal(t, "personal", cfg.Juejin().Cookie)
+	assert.Equal(t, []string{DefaultProfile}, cfg.ProfileNames("juejin"))
+	assert.Empty(t, cfg.ProfileNames("csdn"))
+
+	cfg.Account("company").Juejin.Cookie = "company"
+	cfg.Account("company").Gitlab = Gitlab{Token: "company", HTTP: HTTP{BaseURL: "https://gitlab.example.com"}}
+	assert.Equal(t, []string{DefaultProfile, "company"}, cfg.ProfileNames("juejin"))
+	assert.Equal(t, "personal", cfg.Juejin().Cookie)
+
+	// The credentials are never inherited, the other settings are
+	cfg.Platforms.CSDN = CSDN{APIKey: "personal", APISecret: "personal", ImageURL: "https://example.com"}
+	company := cfg.WithProfile("company")
+	assert.Equal(t, CSDN{ImageURL: "https://example.com"}, company.CSDN())
+	assert.Equal(t, "company", company.Juejin().Cookie)
+	assert.Equal(t, "http://127.0.0.1:7890", company.Juejin().Proxy)
+	assert.Equal(t, "https://gitlab.example.com", company.Gitlab().BaseURL)
+	assert.Equal(t, "", company.CSDN().Cookie)
+	assert.Equal(t, "", cfg.WithProfile("unknown").Juejin().Cookie)
+
+	assert.NotNil(t, cfg.Switch("csdn", "company"))
+	assert.Nil(t, cfg.Switch("juejin", "company"))
+	assert.Equal(t, "company", cfg.Juejin().Cookie)
+	assert.Equal(t, "personal", cfg.Gitlab().Token)
+
+	assert.Nil(t, SaveConfig(cfgFile, cfg))
+	saved, err := ParseConfig(cfgFile)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg, saved)
+
+	assert.Nil(t, saved.Switch("juejin", DefaultProfile))
+	assert.Equal(t, "personal", saved.Juejin().Cookie)
+
+	content = `profiles:
+  default:
+    juejin:
+      cookie: cookie
+`
+	if err := ioutil.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseConfig(cfgFile)
+	assert.NotNil(t, err)
+}
+
+func TestCredentialStore(t *testing.T) {
+	defer func(n int) { credential.Iterations = n }(credential.Iterations)
+	credential.Iterations = 1000
+
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.yml")
+	content := `platforms:
+  juejin:
+    cookie: personal
+  github:
+    token: ghp_personal
+profiles:
+  company:
+    juejin:
+      cookie: company
+`
+	if err := ioutil.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseConfig(cfgFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, cfg.CountSecrets())
+
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, []byte("passphrase\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := CredentialStore{Type: CredentialStoreFile, File: filepath.Join(dir, "credentials.enc"), KeyFile: keyFile}
+	assert.Nil(t, cfg.SetCredentialStore(store))
+	assert.Nil(t, SaveConfig(cfgFile, cfg))
+	assert.Equal(t, "personal", cfg.Juejin().Cookie)
+
+	b, err := ioutil.ReadFile(cfgFile)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "personal")
+	assert.NotContains(t, string(b), "ghp_personal")
+	info, err := os.Stat(cfgFile)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	saved, err := ParseConfig(cfgFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "personal", saved.Juejin().Cookie)
+	assert.Equal(t, "ghp_personal", saved.Github().Token)
+	assert.Equal(t, "company", saved.WithProfile("company").Juejin().Cookie)
+
+	// The removed secrets are erased from the store
+	saved.Platforms.Github.Token = ""
+	assert.Nil(t, SaveConfig(cfgFile, saved))
+	saved, err = ParseConfig(cfgFile)
+	assert.Nil(t, err)
+	// The secrets are loaded when they are used
+	assert.Equal(t, 0, saved.CountSecrets())
+	assert.Equal(t, "", saved.Github().Token)
+	assert.Equal(t, "personal", saved.Juejin().Cookie)
+	assert.Equal(t, 1, saved.CountSecrets())
+
+	if err := ioutil.WriteFile(keyFile, []byte("wrong"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	saved, err = ParseConfig(cfgFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// The store is not opened for the secrets overridden by the environment variables
+	os.Setenv("ACLI_JUEJIN_COOKIE", "ci")
+	defer os.Unsetenv("ACLI_JUEJIN_COOKIE")
+	assert.Equal(t, "ci", saved.Juejin().Cookie)
+	assert.False(t, saved.creds.warned)
+	assert.Equal(t, "", saved.Github().Token)
+	assert.True(t, saved.creds.warned)
+	assert.NotNil(t, SaveConfig(cfgFile, saved))
+}
+
+func TestEnvOverride(t *testing.T) {
+	cfg := &Config{}
+	cfg.Platforms.Juejin.Cookie = "personal"
+	cfg.Account("company").Juejin.Cookie = "company"
+
+	os.Setenv("ACLI_JUEJIN_COOKIE", "ci")
+	os.Setenv("ACLI_COMPANY_GITHUB_TOKEN", "ci_token")
+	defer os.Unsetenv("ACLI_JUEJIN_COOKIE")
+	defer os.Unsetenv("ACLI_COMPANY_GITHUB_TOKEN")
+
+	assert.Equal(t, "ci", cfg.Juejin().Cookie)
+	assert.Equal(t, "company", cfg.WithProfile("company").Juejin().Cookie)
+	assert.Equal(t, "", cfg.Github().Token)
+	assert.Equal(t, "ci_token", cfg.WithProfile("company").Github().Token)
+	// The overrides are never saved
+	assert.Equal(t, "personal", cfg.Platforms.Juejin.Cookie)
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	cfg := &Config{}
+	cfg.Platforms.Github.Token = "ghp_secret"
+	cfg.Platforms.Github.BaseURL = "https://github.example.com/api/v3"
+
+	b, err := cfg.MarshalRedacted()
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "ghp_secret")
+	assert.Contains(t, string(b), "token: REDACTED")
+	assert.Contains(t, string(b), "https://github.example.com/api/v3")
+	assert.Equal(t, "ghp_secret", cfg.Platforms.Github.Token)
+}
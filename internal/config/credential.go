@@ -0,0 +1,326 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/k8scat/articli/internal/credential"
+)
+
+const (
+	CredentialStoreConfig = "config"
+	CredentialStoreFile   = "file"
+	CredentialStoreHelper = "helper"
+
+	// PassphraseEnv is the passphrase of the encrypted credential file if there is no key file
+	PassphraseEnv = "ACLI_CREDENTIAL_PASSPHRASE"
+)
+
+// CredentialStore is where the secrets of the accounts are saved, they are saved in the config file if Type is empty
+type CredentialStore struct {
+	// Type is one of config, file and helper
+	Type string `yaml:"type,omitempty"`
+	// File is the encrypted file, defaults to credentials.enc in the config dir
+	File string `yaml:"file,omitempty"`
+	// KeyFile contains the passphrase of the encrypted file, PassphraseEnv is used if empty
+	KeyFile string `yaml:"key_file,omitempty"`
+	// Helper is the name of the credential helper acli-credential-<name> in PATH, or the path of an executable
+	Helper string `yaml:"helper,omitempty"`
+}
+
+// Open returns the store, nil if the secrets are saved in the config file
+func (s CredentialStore) Open() (credential.Store, error) {
+	switch s.Type {
+	case "", CredentialStoreConfig:
+		return nil, nil
+	case CredentialStoreFile:
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		file := s.File
+		if file == "" {
+			file = filepath.Join(GetConfigDir(), "credentials.enc")
+		}
+		f, err := credential.OpenFile(file, passphrase)
+		return f, errors.Trace(err)
+	case CredentialStoreHelper:
+		if s.Helper == "" {
+			return nil, errors.New("credential_store.helper is required")
+		}
+		return credential.NewHelper(s.Helper), nil
+	default:
+		return nil, errors.NotSupportedf("credential store type %q", s.Type)
+	}
+}
+
+func (s CredentialStore) passphrase() ([]byte, error) {
+	if s.KeyFile != "" {
+		b, err := ioutil.ReadFile(s.KeyFile)
+		if err != nil {
+			return nil, errors.Annotate(err, "read key file")
+		}
+		return []byte(strings.TrimRight(string(b), "\r\n")), nil
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	return nil, errors.Errorf("credential_store.key_file or %s is required to open the encrypted credential file", PassphraseEnv)
+}
+
+// SetCredentialStore makes s the store of the secrets, all the secrets are moved to it on the next SaveConfig
+func (c *Config) SetCredentialStore(s CredentialStore) error {
+	// The secrets in the current store are loaded first, so that they are moved too
+	if c.creds != nil {
+		c.creds.mu.Lock()
+		err := c.creds.load(c.secrets())
+		c.creds.mu.Unlock()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	store, err := s.Open()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.CredentialStore = s
+	c.creds = nil
+	if store != nil {
+		c.creds = &credentials{
+			open:   func() (credential.Store, error) { return store, nil },
+			stored: make(map[credential.Key]string),
+			loaded: make(map[credential.Key]bool),
+		}
+	}
+	return nil
+}
+
+// credentials are the secrets of a config in the credential store, which is opened on the first use of a secret
+type credentials struct {
+	// open opens the store once, it is shared by the copies of the config
+	open func() (credential.Store, error)
+
+	mu sync.Mutex
+	// stored are the secrets in store, so that only the changed ones are saved
+	stored map[credential.Key]string
+	// loaded are the secrets looked up in store, a secret is not looked up again even if it is not found
+	loaded map[credential.Key]bool
+	// warned reports whether a failed lookup is printed, which is only printed once
+	warned bool
+}
+
+// newCredentials returns the credentials in the store s, nil if the secrets are saved in the config file
+func newCredentials(s CredentialStore) *credentials {
+	if s.Type == "" || s.Type == CredentialStoreConfig {
+		return nil
+	}
+	var (
+		once  sync.Once
+		store credential.Store
+		err   error
+	)
+	return &credentials{
+		open: func() (credential.Store, error) {
+			once.Do(func() {
+				store, err = s.Open()
+				err = errors.Annotate(err, "open credential store")
+			})
+			return store, err
+		},
+		stored: make(map[credential.Key]string),
+		loaded: make(map[credential.Key]bool),
+	}
+}
+
+// fork returns a copy of cr for a copy of the config, which shares the store with cr, cr.mu must be held
+func (cr *credentials) fork() *credentials {
+	f := &credentials{
+		open:   cr.open,
+		stored: make(map[credential.Key]string, len(cr.stored)),
+		loaded: make(map[credential.Key]bool, len(cr.loaded)),
+		warned: cr.warned,
+	}
+	for k, v := range cr.stored {
+		f.stored[k] = v
+	}
+	for k, v := range cr.loaded {
+		f.loaded[k] = v
+	}
+	return f
+}
+
+// load fills the empty secrets which are not looked up yet from the store, cr.mu must be held
+func (cr *credentials) load(secrets []secret) error {
+	for _, s := range secrets {
+		if *s.value != "" || cr.loaded[s.key] {
+			continue
+		}
+		store, err := cr.open()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cr.loaded[s.key] = true
+		v, err := store.Get(s.key)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Annotatef(err, "get %s", s.key)
+		}
+		*s.value = v
+		cr.stored[s.key] = v
+	}
+	return nil
+}
+
+// save saves the changed secrets in the store, and erases the removed ones.
+// The secrets never looked up are kept in the store though they are empty in the config.
+func (cr *credentials) save(secrets []secret) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	store, err := cr.open()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, s := range secrets {
+		old, ok := cr.stored[s.key]
+		switch {
+		case *s.value == "" && ok:
+			if err := store.Erase(s.key); err != nil {
+				return errors.Annotatef(err, "erase %s", s.key)
+			}
+			delete(cr.stored, s.key)
+		case *s.value != "" && (!ok || *s.value != old):
+			if err := store.Store(s.key, *s.value); err != nil {
+				return errors.Annotatef(err, "store %s", s.key)
+			}
+			cr.stored[s.key] = *s.value
+			cr.loaded[s.key] = true
+		}
+	}
+	return nil
+}
+
+// loadSecrets loads the secrets of the platform in the profile from the credential store unless they are set
+// in the config file or overridden by the environment variables, the store is not opened if there is none to load.
+// A failed lookup is printed as a warning and the secret is left empty. The returned unlock is called after
+// the secrets are read.
+func (c *Config) loadSecrets(profile, platform string) (unlock func()) {
+	if c.creds == nil {
+		return func() {}
+	}
+	var secrets []secret
+	if p := c.profileAccount(profile); p != nil {
+		for _, s := range p.secrets(profile) {
+			if s.key.Platform == platform && credential.Getenv(s.key) == "" {
+				secrets = append(secrets, s)
+			}
+		}
+	}
+	c.creds.mu.Lock()
+	if err := c.creds.load(secrets); err != nil && !c.creds.warned {
+		c.creds.warned = true
+		fmt.Fprintf(os.Stderr, "warning: load secrets failed: %s\n", err)
+	}
+	return c.creds.mu.Unlock
+}
+
+// secret is a credential of an account in the config
+type secret struct {
+	key   credential.Key
+	value *string
+}
+
+func (p *Platforms) secrets(profile string) []secret {
+	key := func(platform, name string) credential.Key {
+		return credential.Key{Profile: profile, Platform: platform, Name: name}
+	}
+	return []secret{
+		{key("juejin", "cookie"), &p.Juejin.Cookie},
+		{key("oschina", "cookie"), &p.OSChina.Cookie},
+		{key("csdn", "cookie"), &p.CSDN.Cookie},
+		{key("csdn", "api_secret"), &p.CSDN.APISecret},
+		{key("github", "token"), &p.Github.Token},
+		{key("gitlab", "token"), &p.Gitlab.Token},
+	}
+}
+
+// CountSecrets returns the number of the secrets set in all the profiles
+func (c *Config) CountSecrets() int {
+	n := 0
+	for _, s := range c.secrets() {
+		if *s.value != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *Config) secrets() []secret {
+	secrets := c.Platforms.secrets(DefaultProfile)
+	names := make([]string, 0, len(c.Profiles))
+	for name, p := range c.Profiles {
+		if p != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		secrets = append(secrets, c.Profiles[name].secrets(name)...)
+	}
+	return secrets
+}
+
+// profileAccount returns the accounts of the named profile, nil if the profile does not exist
+func (c *Config) profileAccount(name string) *Platforms {
+	if name == DefaultProfile {
+		return &c.Platforms
+	}
+	return c.Profiles[name]
+}
+
+// hideSecrets clears the secrets so that they are not written to the config file,
+// and returns the function to restore them
+func (c *Config) hideSecrets() func() {
+	return c.replaceSecrets("")
+}
+
+// replaceSecrets replaces the secrets which are set with value, and returns the function to restore them
+func (c *Config) replaceSecrets(value string) func() {
+	secrets := c.secrets()
+	values := make([]string, len(secrets))
+	for i, s := range secrets {
+		values[i] = *s.value
+		if *s.value != "" {
+			*s.value = value
+		}
+	}
+	return func() {
+		for i, s := range secrets {
+			*s.value = values[i]
+		}
+	}
+}
+
+// MarshalRedacted returns the yaml of the config whose secrets are replaced by REDACTED
+func (c *Config) MarshalRedacted() ([]byte, error) {
+	defer c.replaceSecrets("REDACTED")()
+	b, err := yaml.Marshal(c)
+	return b, errors.Trace(err)
+}
+
+// getenv returns the secret in the environment variable which overrides value, e.g. ACLI_JUEJIN_COOKIE
+func getenv(profile, platform, name, value string) string {
+	if v := credential.Getenv(credential.Key{Profile: profile, Platform: platform, Name: name}); v != "" {
+		return v
+	}
+	return value
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"net/url"
+
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+// options returns the client options of the http settings, the proxy is validated by Validate
+func (h HTTP) options() *platform.Options {
+	opts := &platform.Options{
+		BaseURL:   h.BaseURL,
+		UserAgent: h.UserAgent,
+		Timeout:   h.Timeout,
+		RateLimit: h.RateLimit,
+	}
+	if h.Proxy != "" {
+		if proxy, err := url.Parse(h.Proxy); err == nil {
+			opts.Proxy = proxy
+		}
+	}
+	return opts
+}
+
+// Options returns the client options of juejin, the tags are cached in the config dir unless TagCacheTTL is negative
+func (j Juejin) Options() *platform.Options {
+	opts := j.HTTP.options()
+	opts.Cookie = j.Cookie
+	opts.ImageURL = j.ImageXURL
+	opts.TagAliases = j.TagAliases
+	if j.TagCacheTTL >= 0 {
+		opts.CacheDir = GetConfigDir()
+		opts.CacheTTL = j.TagCacheTTL
+	}
+	return opts
+}
+
+// Options returns the client options of oschina
+func (o OSChina) Options() *platform.Options {
+	opts := o.HTTP.options()
+	opts.Cookie = o.Cookie
+	return opts
+}
+
+// Options returns the client options of csdn
+func (c CSDN) Options() *platform.Options {
+	opts := c.HTTP.options()
+	opts.Cookie = c.Cookie
+	opts.ImageURL = c.ImageURL
+	return opts
+}
+
+// Options returns the client options of github
+func (g Github) Options() *platform.Options {
+	opts := g.HTTP.options()
+	opts.Token = g.Token
+	return opts
+}
+
+// Options returns the client options of gitlab
+func (g Gitlab) Options() *platform.Options {
+	opts := g.HTTP.options()
+	opts.Token = g.Token
+	return opts
+}
+
+// PlatformOptions returns the client options of the named platform with the account of the profile in use,
+// nil is returned if the platform is unknown
+func (c *Config) PlatformOptions(name string) *platform.Options {
+	switch name {
+	case "juejin":
+		return c.Juejin().Options()
+	case "oschina":
+		return c.OSChina().Options()
+	case "csdn":
+		return c.CSDN().Options()
+	case "github":
+		return c.Github().Options()
+	case "gitlab":
+		return c.Gitlab().Options()
+	default:
+		return nil
+	}
+}
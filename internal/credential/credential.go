@@ -0,0 +1,53 @@
+// Package credential keeps the secrets of the platform accounts out of the config file,
+// in an encrypted file or an external credential helper.
+package credential
+
+import (
+	"os"
+	"strings"
+)
+
+// Key identifies a secret of an account, e.g. the cookie of juejin in the default profile
+type Key struct {
+	Profile  string
+	Platform string
+	Name     string
+}
+
+func (k Key) String() string {
+	return k.Profile + "/" + k.Platform + "/" + k.Name
+}
+
+// Store saves the secrets, Get returns a not found error if the secret is not stored
+type Store interface {
+	Get(key Key) (string, error)
+	Store(key Key, secret string) error
+	Erase(key Key) error
+}
+
+// DefaultProfile has no prefix in the environment variables
+const DefaultProfile = "default"
+
+// EnvName returns the environment variable which overrides the secret of key,
+// e.g. ACLI_JUEJIN_COOKIE for the default profile and ACLI_COMPANY_JUEJIN_COOKIE for the company profile
+func EnvName(key Key) string {
+	parts := []string{"ACLI", key.Platform, key.Name}
+	if key.Profile != "" && key.Profile != DefaultProfile {
+		parts = []string{"ACLI", key.Profile, key.Platform, key.Name}
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, strings.Join(parts, "_"))
+}
+
+// Getenv returns the secret of key in the environment, empty if not set
+func Getenv(key Key) string {
+	return os.Getenv(EnvName(key))
+}
@@ -0,0 +1,118 @@
+package credential
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "ACLI_JUEJIN_COOKIE", EnvName(Key{Profile: DefaultProfile, Platform: "juejin", Name: "cookie"}))
+	assert.Equal(t, "ACLI_CSDN_API_SECRET", EnvName(Key{Platform: "csdn", Name: "api_secret"}))
+	assert.Equal(t, "ACLI_MY_COMPANY_GITHUB_TOKEN", EnvName(Key{Profile: "my-company", Platform: "github", Name: "token"}))
+}
+
+func testStore(t *testing.T, s Store) {
+	key := Key{Profile: DefaultProfile, Platform: "juejin", Name: "cookie"}
+	_, err := s.Get(key)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	assert.Nil(t, s.Store(key, "sessionid=1"))
+	secret, err := s.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, "sessionid=1", secret)
+
+	assert.Nil(t, s.Erase(key))
+	_, err = s.Get(key)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestFile(t *testing.T) {
+	defer func(n int) { Iterations = n }(Iterations)
+	Iterations = 1000
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	f, err := OpenFile(path, []byte("passphrase"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	testStore(t, f)
+
+	key := Key{Profile: "company", Platform: "github", Name: "token"}
+	assert.Nil(t, f.Store(key, "ghp_secret"))
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "ghp_secret")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	f, err = OpenFile(path, []byte("passphrase"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	secret, err := f.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, "ghp_secret", secret)
+
+	_, err = OpenFile(path, []byte("wrong"))
+	assert.NotNil(t, err)
+	_, err = OpenFile(path, nil)
+	assert.NotNil(t, err)
+}
+
+// helperScript stores the secrets as files in a directory
+const helperScript = `#!/bin/sh
+dir=$(dirname "$0")/secrets
+mkdir -p "$dir"
+while IFS='=' read -r k v; do
+  [ -z "$k" ] && break
+  eval "$k=\"\$v\""
+done
+file="$dir/$profile.$platform.$name"
+case "$1" in
+  get) [ -f "$file" ] && echo "secret=$(cat "$file")" ;;
+  store) printf '%s' "$secret" > "$file" ;;
+  erase) rm -f "$file" ;;
+esac
+exit 0
+`
+
+func TestHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the helper is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), HelperPrefix+"test")
+	if err := ioutil.WriteFile(path, []byte(helperScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	h := NewHelper(path)
+	assert.Equal(t, path, h.Path)
+	testStore(t, h)
+
+	assert.Equal(t, HelperPrefix+"pass", NewHelper("pass").Path)
+	_, err := NewHelper(filepath.Join(t.TempDir(), "missing")).Get(Key{})
+	assert.NotNil(t, err)
+}
+
+func TestHelperTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the helper is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), HelperPrefix+"hung")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(timeout time.Duration) { HelperTimeout = timeout }(HelperTimeout)
+	HelperTimeout = 100 * time.Millisecond
+
+	_, err := NewHelper(path).Get(Key{})
+	assert.True(t, errors.IsTimeout(err))
+}
@@ -0,0 +1,165 @@
+package credential
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+const (
+	fileVersion = 1
+	kdfPBKDF2   = "pbkdf2-sha256"
+	keyLen      = 32
+	saltLen     = 16
+)
+
+// Iterations of PBKDF2 for the new files, the existing files keep their own iterations
+var Iterations = 600000
+
+// fileData is the content of the encrypted file, only Data is encrypted
+type fileData struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// File stores the secrets in a file encrypted by AES-GCM with a key derived from a passphrase
+type File struct {
+	path       string
+	key        []byte
+	salt       []byte
+	iterations int
+
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// OpenFile decrypts the file with passphrase, the file is created on the first Store if not found
+func OpenFile(path string, passphrase []byte) (*File, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+	f := &File{
+		path:    path,
+		secrets: make(map[string]string),
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		f.iterations = Iterations
+		f.salt = make([]byte, saltLen)
+		if _, err = rand.Read(f.salt); err != nil {
+			return nil, errors.Trace(err)
+		}
+		f.key = pbkdf2.Key(passphrase, f.salt, f.iterations, keyLen, sha256.New)
+		return f, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var data fileData
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Annotatef(err, "invalid credential file %s", path)
+	}
+	if data.Version != fileVersion || data.KDF != kdfPBKDF2 || data.Iterations <= 0 {
+		return nil, errors.NotSupportedf("credential file version %d with kdf %s", data.Version, data.KDF)
+	}
+	f.iterations = data.Iterations
+	f.salt = data.Salt
+	f.key = pbkdf2.Key(passphrase, f.salt, f.iterations, keyLen, sha256.New)
+
+	gcm, err := newGCM(f.key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plain, err := gcm.Open(nil, data.Nonce, data.Data, nil)
+	if err != nil {
+		return nil, errors.Errorf("decrypt %s failed, the passphrase is wrong or the file is corrupted", path)
+	}
+	if err = json.Unmarshal(plain, &f.secrets); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
+
+func (f *File) Get(key Key) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secret, ok := f.secrets[key.String()]
+	if !ok {
+		return "", errors.NotFoundf("secret %s", key)
+	}
+	return secret, nil
+}
+
+func (f *File) Store(key Key, secret string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[key.String()] = secret
+	return errors.Trace(f.write())
+}
+
+func (f *File) Erase(key Key) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.secrets[key.String()]; !ok {
+		return nil
+	}
+	delete(f.secrets, key.String())
+	return errors.Trace(f.write())
+}
+
+// write encrypts all the secrets with a new nonce, the file is only readable by the owner
+func (f *File) write() error {
+	plain, err := json.Marshal(f.secrets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	gcm, err := newGCM(f.key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return errors.Trace(err)
+	}
+	b, err := json.MarshalIndent(&fileData{
+		Version:    fileVersion,
+		KDF:        kdfPBKDF2,
+		Iterations: f.iterations,
+		Salt:       f.salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.WriteFileAtomic(f.path, b, 0600))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, errors.Trace(err)
+}
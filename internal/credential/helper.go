@@ -0,0 +1,86 @@
+package credential
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// HelperPrefix is the prefix of the credential helpers in PATH, e.g. acli-credential-pass
+const HelperPrefix = "acli-credential-"
+
+// HelperTimeout is how long a helper may run before it is killed, e.g. when it waits for an input never given
+var HelperTimeout = 30 * time.Second
+
+// Helper runs an external credential helper like git does, e.g. acli-credential-pass get.
+// The attributes of the key are written to the stdin of the helper as key=value lines,
+// secret is added for store, and get prints secret=<secret> to stdout, nothing if not found.
+type Helper struct {
+	Path string
+}
+
+// NewHelper returns the helper acli-credential-<name> in PATH, or the executable if name is a path
+func NewHelper(name string) *Helper {
+	if strings.ContainsAny(name, `/\`) {
+		return &Helper{Path: name}
+	}
+	return &Helper{Path: HelperPrefix + name}
+}
+
+func (h *Helper) Get(key Key) (string, error) {
+	out, err := h.run("get", key, "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		if secret := strings.TrimPrefix(s.Text(), "secret="); secret != s.Text() {
+			return secret, nil
+		}
+	}
+	return "", errors.NotFoundf("secret %s", key)
+}
+
+func (h *Helper) Store(key Key, secret string) error {
+	if strings.ContainsAny(secret, "\r\n") {
+		return errors.NotValidf("secret %s with line breaks", key)
+	}
+	_, err := h.run("store", key, secret)
+	return errors.Trace(err)
+}
+
+func (h *Helper) Erase(key Key) error {
+	_, err := h.run("erase", key, "")
+	return errors.Trace(err)
+}
+
+func (h *Helper) run(action string, key Key, secret string) ([]byte, error) {
+	var in bytes.Buffer
+	fmt.Fprintf(&in, "profile=%s\nplatform=%s\nname=%s\n", key.Profile, key.Platform, key.Name)
+	if secret != "" {
+		fmt.Fprintf(&in, "secret=%s\n", secret)
+	}
+	in.WriteString("\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), HelperTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Path, action)
+	cmd.Stdin = &in
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Timeoutf("%s %s after %s", h.Path, action, HelperTimeout)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "%s %s: %s", h.Path, action, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
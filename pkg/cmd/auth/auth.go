@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+)
+
+var (
+	cfgFile string
+	cfg     *config.Config
+
+	authCmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the credentials of all the platforms",
+	}
+)
+
+func init() {
+	authCmd.AddCommand(migrateCmd)
+}
+
+func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
+	cfgFile = cf
+	cfg = c
+	return authCmd
+}
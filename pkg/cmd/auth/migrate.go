@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+)
+
+var (
+	storeType string
+	storeFile string
+	keyFile   string
+	helper    string
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Move the cookies and tokens in the config file to a credential store",
+		Long: `Move the cookies and tokens in the config file to a credential store.
+The store in the config is used if --store is not set, use --store config to move the secrets back to the config file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := cfg.CredentialStore
+			if cmd.Flags().Changed("store") {
+				store = config.CredentialStore{
+					Type:    storeType,
+					File:    storeFile,
+					KeyFile: keyFile,
+					Helper:  helper,
+				}
+			}
+			if store.Type == "" {
+				fmt.Println("please set --store or credential_store in config")
+				os.Exit(1)
+				return nil
+			}
+
+			if err := cfg.SetCredentialStore(store); err != nil {
+				return errors.Trace(err)
+			}
+			if err := config.SaveConfig(cfgFile, cfg); err != nil {
+				return errors.Errorf("save config failed: %+v", errors.Trace(err))
+			}
+
+			gr := color.New(color.FgGreen)
+			gr.Print("✓ ")
+			fmt.Printf("Moved %d secrets to the %s credential store\n", cfg.CountSecrets(), store.Type)
+			return nil
+		},
+	}
+)
+
+func init() {
+	migrateCmd.Flags().StringVar(&storeType, "store", "", "Type of the credential store, one of file, helper and config")
+	migrateCmd.Flags().StringVar(&storeFile, "file", "", "Path of the encrypted file, defaults to credentials.enc in the config dir")
+	migrateCmd.Flags().StringVar(&keyFile, "key-file", "", "File containing the passphrase of the encrypted file, "+config.PassphraseEnv+" is used if not set")
+	migrateCmd.Flags().StringVar(&helper, "helper", "", "Name of the credential helper acli-credential-<name>, or the path of an executable")
+}
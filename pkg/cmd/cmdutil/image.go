@@ -0,0 +1,18 @@
+package cmdutil
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/image"
+)
+
+// NewMarkRewriter returns the rewriter which uploads the local images in articles to the image host in cfg,
+// the uploaded images are remembered in the default image cache
+func NewMarkRewriter(cfg *config.Config) (*image.MarkRewriter, error) {
+	cache, err := image.LoadCache(image.DefaultCacheFile())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &image.MarkRewriter{Config: cfg, Cache: cache}, nil
+}
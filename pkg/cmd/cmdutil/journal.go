@@ -0,0 +1,67 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/image"
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+// RecoverJournal writes back the ids left in the journal by the interrupted saves, the recoveries are printed to stderr
+func RecoverJournal(ctx context.Context, cfg *config.Config) (*journal.Journal, error) {
+	j, err := journal.LoadJournal(journal.DefaultJournalFile())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	recoveries, err := j.Recover(ctx, func(name string, mark *markdown.Mark) (platform.Publisher, error) {
+		p, err := NewPublisher(cfg, name, mark)
+		return p, errors.Trace(err)
+	})
+	for _, r := range recoveries {
+		fmt.Fprintln(os.Stderr, r)
+	}
+	return j, errors.Trace(err)
+}
+
+// SaveMark saves mark as an article or a draft by p with a journaled entry, and writes the ids back to the markdown file.
+// Creating an article fails if an article with the same title exists unless allowDuplicate is true.
+// The local images are uploaded by rw and only the saved content links to them, they are kept as is if rw is nil.
+func SaveMark(ctx context.Context, j *journal.Journal, p platform.Publisher, mark *markdown.Mark, saveType platform.SaveType, allowDuplicate bool, rw *image.MarkRewriter) (*platform.Result, error) {
+	if saveType == platform.SaveTypeArticle && !allowDuplicate {
+		if err := platform.CheckDuplicate(ctx, p, mark); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	m := mark
+	if rw != nil {
+		var err error
+		if m, err = rw.RewriteMark(ctx, p, mark); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	// The entry of a failed save is kept unless it failed before the request was sent,
+	// the article may be created though the response is lost
+	result, entry, err := j.Save(mark.File, p.Name(), saveType, platform.Title(mark, p.Name()), func() (*platform.Result, error) {
+		if saveType == platform.SaveTypeDraft {
+			return p.SaveDraft(ctx, m)
+		}
+		return p.Publish(ctx, m)
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = p.WriteBack(mark, result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = mark.WriteFile(mark.File); err != nil {
+		return nil, errors.Annotate(err, "write back failed, the ids are kept in the journal and written back next time")
+	}
+	return result, errors.Trace(j.Finish(entry.ID))
+}
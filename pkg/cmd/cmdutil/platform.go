@@ -0,0 +1,23 @@
+package cmdutil
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+// NewPublisher creates a Publisher of the named platform with the account of the profile pinned in mark,
+// the profile in cfg is used if mark pins no profile. The commands of markdown files create their clients by it,
+// so that the pinned account is used.
+func NewPublisher(cfg *config.Config, name string, mark *markdown.Mark) (platform.Publisher, error) {
+	if profile := platform.Profile(mark, name); profile != "" {
+		cfg = cfg.WithProfile(profile)
+	}
+	p, err := platform.New(name, cfg.PlatformOptions(name))
+	if err != nil {
+		return nil, errors.Annotatef(err, "please login %s with profile %s first", name, cfg.ProfileOf(name))
+	}
+	return p, nil
+}
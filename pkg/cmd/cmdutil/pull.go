@@ -0,0 +1,71 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+// PullOptions are the flags shared by the pull commands of the platforms
+type PullOptions struct {
+	All   bool
+	Dir   string
+	Force bool
+}
+
+// AddFlags adds the flags of opts to the pull command
+func (o *PullOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.All, "all", "a", false, "Pull all the articles")
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "Directory to write the markdown files")
+	cmd.Flags().BoolVarP(&o.Force, "force", "f", false, "Overwrite the existing markdown files")
+}
+
+// PullFunc returns the mark of the article with the id and its title
+type PullFunc func(ctx context.Context, id string) (mark *markdown.Mark, title string, err error)
+
+// Pull writes the articles of ids into the markdown files named by their titles and ids in the dir,
+// or all the articles listed by listAll if the all flag is set. The existing files are skipped unless
+// the force flag is set, and the state of the articles is kept in the state backend of the project.
+func (o *PullOptions) Pull(ctx context.Context, ids []string, listAll func(ctx context.Context) ([]string, error), pull PullFunc) error {
+	if o.All {
+		var err error
+		if ids, err = listAll(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := os.MkdirAll(o.Dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range ids {
+		if err := o.pullOne(ctx, id, pull); err != nil {
+			return errors.Annotatef(err, "pull article %s", id)
+		}
+	}
+	return nil
+}
+
+func (o *PullOptions) pullOne(ctx context.Context, id string, pull PullFunc) error {
+	mark, title, err := pull(ctx, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	file := filepath.Join(o.Dir, markdown.FileName(title, id))
+	if _, err = os.Stat(file); err == nil && !o.Force {
+		fmt.Printf("%s already exists, skipped\n", file)
+		return nil
+	}
+	if err = mark.SetFile(file); err != nil {
+		return errors.Trace(err)
+	}
+	if err = mark.WriteFile(file); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Println(file)
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+)
+
+var (
+	cfg *config.Config
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "View the config and the meta of articles",
+	}
+)
+
+func init() {
+	configCmd.AddCommand(showCmd)
+}
+
+func NewConfigCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return configCmd
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+var (
+	effective string
+
+	showCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Print the config whose secrets are redacted, or the meta of an article merged with the project defaults",
+		Example: `  acli config show
+  acli config show --effective posts/hello.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if effective == "" {
+				b, err := cfg.MarshalRedacted()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				fmt.Print(string(b))
+				return nil
+			}
+
+			mark, err := markdown.Parse(effective)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			b, err := yaml.Marshal(mark.Meta)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if mark.Project != "" {
+				fmt.Printf("# merged with %s\n", mark.Project)
+			}
+			fmt.Print(string(b))
+			return nil
+		},
+	}
+)
+
+func init() {
+	showCmd.Flags().StringVar(&effective, "effective", "", "Print the meta of the markdown file merged with the defaults in "+markdown.ProjectFile)
+}
@@ -17,7 +17,11 @@ var (
 		Use:   "article",
 		Short: "Manage articles",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ := csdnsdk.NewClient(cfg.Platforms.CSDN.Cookie)
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd || cmd == diffCmd {
+				return
+			}
+			client, _ = csdnsdk.NewClient(cfg.CSDN().Cookie, csdnsdk.WithOptions(cfg.CSDN().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -28,6 +32,11 @@ var (
 
 func init() {
 	articleCmd.AddCommand(createCmd)
+	articleCmd.AddCommand(listCmd)
+	articleCmd.AddCommand(viewCmd)
+	articleCmd.AddCommand(deleteCmd)
+	articleCmd.AddCommand(pullCmd)
+	articleCmd.AddCommand(diffCmd)
 }
 
 func NewArticleCmd(c *config.Config) *cobra.Command {
@@ -0,0 +1,71 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/diff"
+	"github.com/k8scat/articli/pkg/markdown"
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+)
+
+var (
+	diffFields = []string{"title", "tags", "categories", "cover_images"}
+
+	diffCmd = &cobra.Command{
+		Use:   "diff <markdownFile>",
+		Short: "Show the differences between the remote article and the markdown file",
+		Long: `Show the differences between the remote article and the markdown file.
+Exit status is 0 if there is no difference, 1 if there are differences, 2 if failed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			result, err := diffMark(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "diff failed: %s\n", err)
+				os.Exit(2)
+			}
+			if result.HasDiff() {
+				result.Print(os.Stdout)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func diffMark(ctx context.Context, markdownFile string) (*diff.Result, error) {
+	mark, err := markdown.Parse(markdownFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p, err := cmdutil.NewPublisher(cfg, csdnsdk.PlatformName, mark)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	publisher, ok := p.(*csdnsdk.Publisher)
+	if !ok {
+		return nil, errors.Errorf("unexpected publisher %T of %s", p, csdnsdk.PlatformName)
+	}
+	client := publisher.Client
+
+	articleID := mark.Meta.GetString("csdn.article_id")
+	if articleID == "" {
+		return nil, errors.New("article_id not found in csdn meta")
+	}
+	article, err := client.GetArticleContext(ctx, articleID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The prefix and suffix content are published along with the content
+	mark.Content = diff.Body(mark, csdnsdk.PlatformName)
+	result, err := diff.Compare(csdnsdk.PlatformName, mark, csdnsdk.NewMark(article), diffFields, nil)
+	return result, errors.Trace(err)
+}
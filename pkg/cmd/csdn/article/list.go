@@ -0,0 +1,81 @@
+package article
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	limit       int
+	keyword     string
+	status      string
+	articleType int
+	year        int
+	month       int
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List articles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit <= 0 {
+				fmt.Println("limit must be greater than 0")
+				os.Exit(1)
+				return nil
+			}
+			switch csdnsdk.ListArticleStatus(status) {
+			case csdnsdk.ListArticleStatusAll, csdnsdk.ListArticleStatusEnable, csdnsdk.ListArticleStatusPrivate,
+				csdnsdk.ListArticleStatusAudit, csdnsdk.ListArticleStatusDraft, csdnsdk.ListArticleStatusDeleted:
+			default:
+				fmt.Printf("invalid status: %s\n", status)
+				os.Exit(1)
+				return nil
+			}
+
+			req := &csdnsdk.ListArticlesRequest{
+				Page:        1,
+				PageSize:    pageSize,
+				Status:      csdnsdk.ListArticleStatus(status),
+				ArticleType: csdnsdk.ListArticleType(articleType),
+				Year:        year,
+				Month:       month,
+				Keyword:     keyword,
+			}
+			result, err := client.ListAllArticlesContext(cmd.Context(), req, limit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			keys := []string{"id", "title", "status", "view_count,number", "comment_count,number", "collect_count,number", "post_time"}
+			header := []string{"ID", "标题", "状态", "阅读", "评论", "收藏", "发布时间"}
+			data := make([][]string, 0, len(result))
+			for _, a := range result {
+				data = append(data, []string{
+					a.ID,
+					a.Title,
+					a.Status,
+					a.ViewCount,
+					a.CommentCount,
+					fmt.Sprint(a.CollectCount),
+					a.PostTime,
+				})
+			}
+			table.Print(keys, header, data)
+			return nil
+		},
+	}
+)
+
+func init() {
+	listCmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum number of articles to list")
+	listCmd.Flags().StringVarP(&keyword, "keyword", "k", "", "Filter keyword")
+	listCmd.Flags().StringVarP(&status, "status", "s", string(csdnsdk.ListArticleStatusAll), "Status, one of all, enable, private, audit, draft and deleted")
+	listCmd.Flags().IntVarP(&articleType, "type", "t", 0, "Article type, 0: 全部, 1: 原创, 2: 转载, 3: 翻译")
+	listCmd.Flags().IntVar(&year, "year", 0, "Filter by the year of publishing")
+	listCmd.Flags().IntVar(&month, "month", 0, "Filter by the month of publishing")
+}
@@ -0,0 +1,57 @@
+package article
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/markdown"
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+)
+
+const pageSize = 20
+
+var (
+	pullOptions cmdutil.PullOptions
+
+	pullCmd = &cobra.Command{
+		Use:   "pull [articleID]",
+		Short: "Pull articles into local markdown files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !pullOptions.All && len(args) == 0 {
+				return cmd.Help()
+			}
+			return errors.Trace(pullOptions.Pull(cmd.Context(), args, listAllArticleIDs, pull))
+		},
+	}
+)
+
+func init() {
+	pullOptions.AddFlags(pullCmd)
+}
+
+func pull(ctx context.Context, id string) (*markdown.Mark, string, error) {
+	article, err := client.GetArticleContext(ctx, id)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return csdnsdk.NewMark(article), article.Title, nil
+}
+
+func listAllArticleIDs(ctx context.Context) ([]string, error) {
+	req := &csdnsdk.ListArticlesRequest{
+		Page:     1,
+		PageSize: pageSize,
+	}
+	articles, err := client.ListAllArticlesContext(ctx, req, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	return ids, nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/profile"
 	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +16,7 @@ var (
 		Use:   "auth",
 		Short: "Manage authentication state of csdn.net",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = csdnsdk.NewClient(cfg.Platforms.CSDN.Cookie)
+			client, _ = csdnsdk.NewClient(cfg.CSDN().Cookie, csdnsdk.WithOptions(cfg.CSDN().Options()))
 		},
 	}
 )
@@ -29,5 +30,8 @@ func init() {
 func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
 	cfgFile = cf
 	cfg = c
+
+	authCmd.AddCommand(profile.NewSwitchCmd("csdn", cfgFile, cfg))
+	authCmd.AddCommand(profile.NewListCmd("csdn", cfg))
 	return authCmd
 }
@@ -68,7 +68,7 @@ var (
 				}
 			}
 
-			client, err := csdnsdk.NewClient(cookie)
+			client, err := csdnsdk.NewClient(cookie, csdnsdk.WithOptions(cfg.CSDN().Options()))
 			if err != nil {
 				fmt.Printf("error validating cookie: %s\n", err.Error())
 				os.Exit(1)
@@ -80,7 +80,8 @@ var (
 			fmt.Print("Logged in as ")
 			bo.Printf("%s\n", client.AuthInfo.Basic.Nickname)
 
-			cfg.Platforms.CSDN.Cookie = cookie
+			account := cfg.Account(cfg.ProfileOf("csdn"))
+			account.CSDN.Cookie = cookie
 			if err = config.SaveConfig(cfgFile, cfg); err != nil {
 				return errors.Errorf("save config failed: %+v", errors.Trace(err))
 			}
@@ -46,7 +46,8 @@ var (
 				break
 			}
 
-			cfg.Platforms.CSDN.Cookie = ""
+			account := cfg.Account(cfg.ProfileOf("csdn"))
+			account.CSDN.Cookie = ""
 			err := config.SaveConfig(cfgFile, cfg)
 			if err != nil {
 				return errors.Trace(err)
@@ -22,7 +22,7 @@ var (
 				os.Exit(1)
 			} else {
 				gr.Print("✓ ")
-				gr.Printf("Logged in to csdn.net as %s (%s)\n", client.AuthInfo.Basic.Nickname, cfgFile)
+				gr.Printf("Logged in to csdn.net as %s (profile %s, %s)\n", client.AuthInfo.Basic.Nickname, cfg.ProfileOf("csdn"), cfgFile)
 			}
 		},
 	}
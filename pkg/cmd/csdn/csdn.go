@@ -4,6 +4,7 @@ import (
 	"github.com/k8scat/articli/internal/config"
 	"github.com/k8scat/articli/pkg/cmd/csdn/article"
 	"github.com/k8scat/articli/pkg/cmd/csdn/auth"
+	"github.com/k8scat/articli/pkg/cmd/csdn/draft"
 	"github.com/spf13/cobra"
 )
 
@@ -23,5 +24,6 @@ func NewCSDNCmd(cf string, c *config.Config) *cobra.Command {
 
 	csdnCmd.AddCommand(auth.NewAuthCmd(cfgFile, cfg))
 	csdnCmd.AddCommand(article.NewArticleCmd(cfg))
+	csdnCmd.AddCommand(draft.NewDraftCmd(cfg))
 	return csdnCmd
 }
@@ -0,0 +1,60 @@
+package draft
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/image"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+)
+
+var (
+	skipImages bool
+
+	createCmd = &cobra.Command{
+		Use:   "create <markdownFile>",
+		Short: "Create or update a draft from a markdown file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			// The ids left by the interrupted saves are written back before the file is parsed
+			j, err := cmdutil.RecoverJournal(cmd.Context(), cfg)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			markdownFile := args[0]
+			mark, err := markdown.Parse(markdownFile)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			p, err := cmdutil.NewPublisher(cfg, csdnsdk.PlatformName, mark)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			var rw *image.MarkRewriter
+			if !skipImages {
+				if rw, err = cmdutil.NewMarkRewriter(cfg); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			result, err := cmdutil.SaveMark(cmd.Context(), j, p, mark, platform.SaveTypeDraft, false, rw)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			fmt.Println(csdnsdk.BuildDraftEditorURL(result.ArticleID))
+			return nil
+		},
+	}
+)
+
+func init() {
+	createCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in the article")
+}
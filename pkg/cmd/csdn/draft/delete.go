@@ -0,0 +1,38 @@
+package draft
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	deep bool
+
+	deleteCmd = &cobra.Command{
+		Use:   "delete <draftID>",
+		Short: "Delete specified draft",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			failedList := make([][]string, 0)
+			for _, id := range args {
+				if err := client.DeleteArticleContext(cmd.Context(), id, deep); err != nil {
+					failedList = append(failedList, []string{id, err.Error()})
+				}
+			}
+			if len(failedList) > 0 {
+				keys := []string{"id", "error"}
+				header := []string{"ID", "Error"}
+				table.Print(keys, header, failedList)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	deleteCmd.Flags().BoolVar(&deep, "deep", false, "Delete permanently instead of moving to the recycle bin")
+}
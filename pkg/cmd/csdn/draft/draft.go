@@ -0,0 +1,43 @@
+package draft
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k8scat/articli/internal/config"
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+	"github.com/spf13/cobra"
+)
+
+var (
+	client *csdnsdk.Client
+	cfg    *config.Config
+
+	draftCmd = &cobra.Command{
+		Use:   "draft",
+		Short: "Manage drafts",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd {
+				return
+			}
+			client, _ = csdnsdk.NewClient(cfg.CSDN().Cookie, csdnsdk.WithOptions(cfg.CSDN().Options()))
+			if client == nil {
+				fmt.Println("please login first")
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	draftCmd.AddCommand(listCmd)
+	draftCmd.AddCommand(editCmd)
+	draftCmd.AddCommand(createCmd)
+	draftCmd.AddCommand(deleteCmd)
+}
+
+func NewDraftCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return draftCmd
+}
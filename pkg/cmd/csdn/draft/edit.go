@@ -0,0 +1,25 @@
+package draft
+
+import (
+	"github.com/cli/browser"
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+)
+
+var (
+	editCmd = &cobra.Command{
+		Use:   "edit <draftID>",
+		Short: "Edit the draft in a web browser",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			draftID := args[0]
+			url := csdnsdk.BuildDraftEditorURL(draftID)
+			err := browser.OpenURL(url)
+			return errors.Trace(err)
+		},
+	}
+)
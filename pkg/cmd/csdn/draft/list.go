@@ -0,0 +1,60 @@
+package draft
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	keyword string
+	limit   int
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List drafts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit <= 0 {
+				fmt.Println("limit must be greater than 0")
+				os.Exit(1)
+				return nil
+			}
+
+			req := &csdnsdk.ListArticlesRequest{
+				Page:    1,
+				Status:  csdnsdk.ListArticleStatusDraft,
+				Keyword: keyword,
+			}
+			result, err := client.ListAllArticlesContext(cmd.Context(), req, limit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			keys := []string{"id", "title", "update_time"}
+			header := []string{"ID", "标题", "修改时间"}
+			data := make([][]string, 0, len(result))
+			for _, draft := range result {
+				if draft.Title == "" {
+					draft.Title = "无标题"
+				}
+				data = append(data, []string{
+					draft.ID,
+					draft.Title,
+					draft.PostTime,
+				})
+			}
+			table.Print(keys, header, data)
+			return nil
+		},
+	}
+)
+
+func init() {
+	listCmd.Flags().StringVarP(&keyword, "keyword", "k", "", "Keyword")
+	listCmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum number of drafts to list")
+}
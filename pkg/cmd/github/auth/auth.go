@@ -2,6 +2,7 @@ package auth
 
 import (
 	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/profile"
 	githubsdk "github.com/k8scat/articli/pkg/platform/github"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +16,7 @@ var (
 		Use:   "auth",
 		Short: "Manage authentication state of github.com",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = githubsdk.NewClient(cfg.Platforms.Github.Token)
+			client, _ = githubsdk.NewClient(cfg.Github().Token, githubsdk.WithOptions(cfg.Github().Options()))
 		},
 	}
 )
@@ -29,5 +30,8 @@ func init() {
 func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
 	cfgFile = cf
 	cfg = c
+
+	authCmd.AddCommand(profile.NewSwitchCmd("github", cfgFile, cfg))
+	authCmd.AddCommand(profile.NewListCmd("github", cfg))
 	return authCmd
 }
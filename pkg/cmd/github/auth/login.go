@@ -70,7 +70,7 @@ var (
 				}
 			}
 
-			client, err := githubsdk.NewClient(token)
+			client, err := githubsdk.NewClient(token, githubsdk.WithOptions(cfg.Github().Options()))
 			if err != nil {
 				fmt.Printf("error validating token: %s\n", err.Error())
 				os.Exit(1)
@@ -82,7 +82,8 @@ var (
 			fmt.Print("Logged in as ")
 			bo.Printf("%s\n", client.User.Name)
 
-			cfg.Platforms.Github.Token = token
+			account := cfg.Account(cfg.ProfileOf("github"))
+			account.Github.Token = token
 			if err = config.SaveConfig(cfgFile, cfg); err != nil {
 				return errors.Errorf("save config failed: %+v", errors.Trace(err))
 			}
@@ -46,7 +46,8 @@ var (
 				break
 			}
 
-			cfg.Platforms.Github.Token = ""
+			account := cfg.Account(cfg.ProfileOf("github"))
+			account.Github.Token = ""
 			err := config.SaveConfig(cfgFile, cfg)
 			if err != nil {
 				return errors.Trace(err)
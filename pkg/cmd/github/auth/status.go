@@ -22,7 +22,7 @@ var (
 				os.Exit(1)
 			} else {
 				gr.Print("✓ ")
-				gr.Printf("Logged in to github.com as %s (%s)\n", client.User.Name, cfgFile)
+				gr.Printf("Logged in to github.com as %s (profile %s, %s)\n", client.User.Name, cfg.ProfileOf("github"), cfgFile)
 			}
 		},
 	}
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -21,7 +22,7 @@ var (
 			}
 
 			for _, file := range args {
-				if err := deleteFile(file); err != nil {
+				if err := deleteFile(cmd.Context(), file); err != nil {
 					return errors.Trace(err)
 				}
 			}
@@ -35,10 +36,10 @@ func init() {
 	deleteCmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch to upload the file to. Default: the repository’s default branch (usually master)")
 }
 
-func deleteFile(path string) error {
+func deleteFile(ctx context.Context, path string) error {
 	path = strings.Trim(path, "/")
 
-	file, isDir, err := client.GetFile(owner, repo, path)
+	file, isDir, err := client.GetFileContext(ctx, owner, repo, path)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -56,6 +57,6 @@ func deleteFile(path string) error {
 		Message: message,
 		SHA:     file.SHA,
 	}
-	err = client.DeleteFile(owner, repo, path, req)
+	err = client.DeleteFileContext(ctx, owner, repo, path, req)
 	return errors.Trace(err)
 }
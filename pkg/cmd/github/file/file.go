@@ -28,9 +28,9 @@ var (
 				os.Exit(1)
 			}
 			if token == "" {
-				token = cfg.Platforms.Github.Token
+				token = cfg.Github().Token
 			}
-			client, _ = githubsdk.NewClient(token)
+			client, _ = githubsdk.NewClient(token, githubsdk.WithOptions(cfg.Github().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
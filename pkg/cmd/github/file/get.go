@@ -22,11 +22,12 @@ var (
 				return nil
 			}
 
-			files, err := client.GetContent(owner, repo, path, ref)
+			files, err := client.GetContentContext(cmd.Context(), owner, repo, path, ref)
 			if err != nil {
 				return errors.Trace(err)
 			}
 
+			keys := []string{"path", "type", "size", "url"}
 			header := []string{"Path", "Type", "Size", "URL"}
 			data := make([][]string, 0)
 			keyword = strings.ToLower(keyword)
@@ -43,7 +44,7 @@ var (
 			if len(data) > limit {
 				data = data[:limit]
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
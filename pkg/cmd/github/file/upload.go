@@ -50,7 +50,7 @@ var (
 
 			var sha string
 			if force {
-				file, _, err := client.GetFile(owner, repo, path)
+				file, _, err := client.GetFileContext(cmd.Context(), owner, repo, path)
 				if err != nil {
 					return errors.Trace(err)
 				}
@@ -64,7 +64,7 @@ var (
 				Message: message,
 				SHA:     sha,
 			}
-			result, err := client.UploadFile(owner, repo, path, req)
+			result, err := client.UploadFileContext(cmd.Context(), owner, repo, path, req)
 			if err != nil {
 				fmt.Printf("upload failed: %s\n", err)
 				os.Exit(1)
@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/profile"
 	gitlabsdk "github.com/k8scat/articli/pkg/platform/gitlab"
 )
 
@@ -16,7 +17,7 @@ var (
 		Use:   "auth",
 		Short: "Manage authentication state of gitlab",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = gitlabsdk.NewClient(cfg.Platforms.Gitlab.BaseURL, cfg.Platforms.Gitlab.Token)
+			client, _ = gitlabsdk.NewClient(cfg.Gitlab().BaseURL, cfg.Gitlab().Token, gitlabsdk.WithOptions(cfg.Gitlab().Options()))
 		},
 	}
 )
@@ -30,5 +31,8 @@ func init() {
 func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
 	cfgFile = cf
 	cfg = c
+
+	authCmd.AddCommand(profile.NewSwitchCmd("gitlab", cfgFile, cfg))
+	authCmd.AddCommand(profile.NewListCmd("gitlab", cfg))
 	return authCmd
 }
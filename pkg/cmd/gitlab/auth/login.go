@@ -74,7 +74,7 @@ var (
 				}
 			}
 
-			client, err := gitlabsdk.NewClient(baseURL, token)
+			client, err := gitlabsdk.NewClient(baseURL, token, gitlabsdk.WithOptions(cfg.Gitlab().Options()))
 			if err != nil {
 				fmt.Printf("error validating token: %s\n", err.Error())
 				os.Exit(1)
@@ -86,8 +86,9 @@ var (
 			fmt.Print("Logged in as ")
 			bo.Printf("%s\n", client.User.Name)
 
-			cfg.Platforms.Gitlab.Token = token
-			cfg.Platforms.Gitlab.BaseURL = baseURL
+			account := cfg.Account(cfg.ProfileOf("gitlab"))
+			account.Gitlab.Token = token
+			account.Gitlab.BaseURL = baseURL
 			if err = config.SaveConfig(cfgFile, cfg); err != nil {
 				return errors.Errorf("save config failed: %+v", errors.Trace(err))
 			}
@@ -28,7 +28,7 @@ var (
 			wo := color.New(color.FgWhite)
 
 			s := bufio.NewScanner(os.Stdin)
-			baseURL = cfg.Platforms.Gitlab.BaseURL
+			baseURL = cfg.Gitlab().BaseURL
 
 			for {
 				bo.Printf("? Are you sure you want to log out of %s account '%s'?", baseURL, client.User.Name)
@@ -49,8 +49,9 @@ var (
 				break
 			}
 
-			cfg.Platforms.Gitlab.Token = ""
-			cfg.Platforms.Gitlab.BaseURL = ""
+			account := cfg.Account(cfg.ProfileOf("gitlab"))
+			account.Gitlab.Token = ""
+			account.Gitlab.BaseURL = ""
 			err := config.SaveConfig(cfgFile, cfg)
 			if err != nil {
 				return errors.Trace(err)
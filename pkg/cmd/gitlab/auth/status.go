@@ -23,7 +23,7 @@ var (
 				os.Exit(1)
 			} else {
 				gr.Print("✓ ")
-				gr.Printf("Logged in to %s as %s (%s)\n", cfg.Platforms.Gitlab.BaseURL, client.User.Name, cfgFile)
+				gr.Printf("Logged in to %s as %s (profile %s, %s)\n", cfg.Gitlab().BaseURL, client.User.Name, cfg.ProfileOf("gitlab"), cfgFile)
 			}
 		},
 	}
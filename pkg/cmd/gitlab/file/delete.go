@@ -1,6 +1,7 @@
 package file
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -21,7 +22,7 @@ var (
 			}
 
 			for _, file := range args {
-				if err := deleteFile(file); err != nil {
+				if err := deleteFile(cmd.Context(), file); err != nil {
 					return errors.Trace(err)
 				}
 			}
@@ -35,7 +36,7 @@ func init() {
 	deleteCmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch to upload the file to. Default: the repository’s default branch (usually master)")
 }
 
-func deleteFile(path string) error {
+func deleteFile(ctx context.Context, path string) error {
 	path = strings.Trim(path, "/")
 
 	if message == "" {
@@ -51,6 +52,6 @@ func deleteFile(path string) error {
 		CommitMessage: message,
 		FilePath:      path,
 	}
-	err := client.DeleteFile(data)
+	err := client.DeleteFileContext(ctx, data)
 	return errors.Trace(err)
 }
@@ -32,9 +32,9 @@ var (
 				os.Exit(1)
 			}
 			if token == "" {
-				token = cfg.Platforms.Gitlab.Token
+				token = cfg.Gitlab().Token
 			}
-			client, _ = gitlabsdk.NewClient(baseURL, token)
+			client, _ = gitlabsdk.NewClient(baseURL, token, gitlabsdk.WithOptions(cfg.Gitlab().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -47,7 +47,7 @@ var (
 				projectID = fmt.Sprintf("%s/%s", owner, repo)
 			}
 			var err error
-			project, err = client.GetProject(projectID)
+			project, err = client.GetProjectContext(cmd.Context(), projectID)
 			return errors.Trace(err)
 		},
 	}
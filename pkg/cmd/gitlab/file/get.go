@@ -27,7 +27,7 @@ var (
 			}
 
 			files := make([]*gitlabsdk.FileNode, 0)
-			file, _ := client.GetFile(projectID, path, ref)
+			file, _ := client.GetFileContext(cmd.Context(), projectID, path, ref)
 			if file != nil {
 				files = append(files, &gitlabsdk.FileNode{
 					Path: file.FilePath,
@@ -41,7 +41,7 @@ var (
 					Page:    1,
 				}
 				for {
-					res, err := client.ListRepoTree(projectID, params)
+					res, err := client.ListRepoTreeContext(cmd.Context(), projectID, params)
 					if err != nil {
 						return errors.Trace(err)
 					}
@@ -59,6 +59,7 @@ var (
 				files = files[:limit]
 			}
 
+			keys := []string{"path", "type", "url"}
 			header := []string{"Path", "Type", "URL"}
 			data := make([][]string, 0)
 			keyword = strings.ToLower(keyword)
@@ -78,7 +79,7 @@ var (
 			if len(data) > limit {
 				data = data[:limit]
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
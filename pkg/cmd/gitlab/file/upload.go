@@ -65,7 +65,7 @@ var (
 				CommitMessage: message,
 				Content:       content,
 			}
-			result, err := client.CreateFile(data)
+			result, err := client.CreateFileContext(cmd.Context(), data)
 			if err != nil {
 				fmt.Printf("upload failed: %s\n", err)
 				os.Exit(1)
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/image"
+)
+
+var (
+	imageCache *image.Cache
+	host       string
+
+	cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of uploaded images",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			imageCache, err = image.LoadCache(image.DefaultCacheFile())
+			return errors.Trace(err)
+		},
+	}
+)
+
+func init() {
+	cacheCmd.PersistentFlags().StringVar(&host, "host", "", "Only the images uploaded to the host, e.g. juejin, github:owner/repo")
+
+	cacheCmd.AddCommand(listCmd)
+	cacheCmd.AddCommand(pruneCmd)
+	cacheCmd.AddCommand(verifyCmd)
+}
+
+func NewCacheCmd() *cobra.Command {
+	return cacheCmd
+}
+
+func filterEntries(match func(e *image.Entry) bool) []*image.Entry {
+	result := make([]*image.Entry, 0)
+	for _, e := range imageCache.Entries() {
+		if host != "" && e.Host != host {
+			continue
+		}
+		if match == nil || match(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
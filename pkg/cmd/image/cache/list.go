@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List cached images",
+		Run: func(cmd *cobra.Command, args []string) {
+			entries := filterEntries(nil)
+			keys := []string{"host", "hash", "file", "url", "created"}
+			header := []string{"Host", "Hash", "File", "URL", "Created"}
+			data := make([][]string, 0, len(entries))
+			for _, e := range entries {
+				data = append(data, []string{
+					e.Host,
+					shortHash(e.Hash),
+					e.File,
+					e.URL,
+					e.CreateTime.Format("2006-01-02 15:04"),
+				})
+			}
+			table.Print(keys, header, data)
+		},
+	}
+)
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/image"
+)
+
+var (
+	olderThan time.Duration
+	missing   bool
+	all       bool
+
+	pruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Remove entries from the image cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan <= 0 && !missing && !all {
+				fmt.Println("one of --older-than, --missing and --all is required")
+				os.Exit(1)
+				return nil
+			}
+
+			deadline := time.Now().Add(-olderThan)
+			entries := filterEntries(func(e *image.Entry) bool {
+				if all {
+					return true
+				}
+				if olderThan > 0 && e.CreateTime.Before(deadline) {
+					return true
+				}
+				if missing {
+					if _, err := os.Stat(e.File); os.IsNotExist(err) {
+						return true
+					}
+				}
+				return false
+			})
+			if err := imageCache.Remove(entries...); err != nil {
+				return errors.Trace(err)
+			}
+			fmt.Printf("Removed: %d\n", len(entries))
+			return nil
+		},
+	}
+)
+
+func init() {
+	pruneCmd.Flags().DurationVar(&olderThan, "older-than", 0, "Remove the images cached before the duration, e.g. 720h")
+	pruneCmd.Flags().BoolVar(&missing, "missing", false, "Remove the images whose local file does not exist")
+	pruneCmd.Flags().BoolVar(&all, "all", false, "Remove all the images")
+}
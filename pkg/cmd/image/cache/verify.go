@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/image"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	prune       bool
+	concurrency int
+	timeout     time.Duration
+
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Check whether the cached image urls are still reachable",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency <= 0 {
+				fmt.Println("concurrency must be greater than 0")
+				os.Exit(1)
+				return nil
+			}
+
+			entries := filterEntries(nil)
+			errs := verify(entries)
+
+			keys := []string{"host", "hash", "url", "error"}
+			header := []string{"Host", "Hash", "URL", "Error"}
+			data := make([][]string, 0)
+			broken := make([]*image.Entry, 0)
+			for i, e := range entries {
+				if errs[i] == nil {
+					continue
+				}
+				broken = append(broken, e)
+				data = append(data, []string{e.Host, shortHash(e.Hash), e.URL, errs[i].Error()})
+			}
+			if len(data) > 0 || table.IsStructured() {
+				table.Print(keys, header, data)
+			}
+			// The summary does not break the structured output
+			w := os.Stdout
+			if table.IsStructured() {
+				w = os.Stderr
+			}
+			fmt.Fprintf(w, "Reachable: %d, Unreachable: %d\n", len(entries)-len(broken), len(broken))
+
+			if prune && len(broken) > 0 {
+				if err := imageCache.Remove(broken...); err != nil {
+					return errors.Trace(err)
+				}
+				fmt.Fprintf(w, "Removed: %d\n", len(broken))
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	verifyCmd.Flags().BoolVar(&prune, "prune", false, "Remove the unreachable images from the cache")
+	verifyCmd.Flags().IntVarP(&concurrency, "concurrency", "n", 8, "Number of urls checked at the same time")
+	verifyCmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout of each request")
+}
+
+func verify(entries []*image.Entry) []error {
+	client := &http.Client{Timeout: timeout}
+	errs := make([]error, len(entries))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, e *image.Entry) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = image.Verify(client, e)
+		}(i, e)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,19 @@
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/image/cache"
+)
+
+var (
+	imageCmd = &cobra.Command{
+		Use:   "image",
+		Short: "Manage uploaded images",
+	}
+)
+
+func NewImageCmd() *cobra.Command {
+	imageCmd.AddCommand(cache.NewCacheCmd())
+	return imageCmd
+}
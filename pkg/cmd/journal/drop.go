@@ -0,0 +1,28 @@
+package journal
+
+import (
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dropCmd = &cobra.Command{
+		Use:   "drop <id>...",
+		Short: "Drop the saves which can not be recovered, e.g. the markdown file is deleted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			ids := make(map[string]bool)
+			for _, e := range j.Entries() {
+				ids[e.ID] = true
+			}
+			for _, id := range args {
+				if !ids[id] {
+					return errors.NotFoundf("journal entry %s", id)
+				}
+			}
+			return errors.Trace(j.Finish(args...))
+		},
+	}
+)
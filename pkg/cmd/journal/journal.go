@@ -0,0 +1,40 @@
+package journal
+
+import (
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/journal"
+	_ "github.com/k8scat/articli/pkg/platform/csdn"
+	_ "github.com/k8scat/articli/pkg/platform/juejin"
+	_ "github.com/k8scat/articli/pkg/platform/oschina"
+)
+
+const timeFormat = "2006-01-02 15:04:05"
+
+var (
+	cfg *config.Config
+	j   *journal.Journal
+
+	journalCmd = &cobra.Command{
+		Use:   "journal",
+		Short: "Manage the saves whose ids are not written back yet",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			j, err = journal.LoadJournal(journal.DefaultJournalFile())
+			return errors.Trace(err)
+		},
+	}
+)
+
+func init() {
+	journalCmd.AddCommand(listCmd)
+	journalCmd.AddCommand(recoverCmd)
+	journalCmd.AddCommand(dropCmd)
+}
+
+func NewJournalCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return journalCmd
+}
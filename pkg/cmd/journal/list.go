@@ -0,0 +1,33 @@
+package journal
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the saves whose ids are not written back yet",
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := []string{"id", "file", "platform", "type", "title", "status", "article_id", "draft_id", "create_time"}
+			header := []string{"ID", "File", "Platform", "Type", "Title", "Status", "Article ID", "Draft ID", "Create Time"}
+			data := make([][]string, 0)
+			for _, e := range j.Entries() {
+				data = append(data, []string{
+					e.ID,
+					e.File,
+					e.Platform,
+					string(e.SaveType),
+					e.Title,
+					string(e.Status),
+					e.ArticleID,
+					e.DraftID,
+					e.CreateTime.Format(timeFormat),
+				})
+			}
+			table.Print(keys, header, data)
+		},
+	}
+)
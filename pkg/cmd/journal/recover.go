@@ -0,0 +1,44 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+var (
+	recoverCmd = &cobra.Command{
+		Use:   "recover",
+		Short: "Write back the ids left by the interrupted saves",
+		Long: `Write back the ids left by the interrupted saves.
+The saves whose responses are lost are looked up by their titles, it runs before publish, sync and schedule run too.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recoveries, err := j.Recover(cmd.Context(), func(name string, mark *markdown.Mark) (platform.Publisher, error) {
+				p, err := cmdutil.NewPublisher(cfg, name, mark)
+				return p, errors.Trace(err)
+			})
+			failed := 0
+			for _, r := range recoveries {
+				if r.Action == journal.ActionFail {
+					failed++
+				}
+				fmt.Println(r)
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+			fmt.Printf("Recovered: %d, Failed: %d\n", len(recoveries)-failed, failed)
+			if failed > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
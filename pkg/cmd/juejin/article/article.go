@@ -17,7 +17,11 @@ var (
 		Use:   "article",
 		Short: "Manage articles",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = juejinsdk.NewClient(cfg.Platforms.Juejin.Cookie)
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd || cmd == diffCmd {
+				return
+			}
+			client, _ = juejinsdk.NewClient(cfg.Juejin().Cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -32,6 +36,8 @@ func init() {
 	articleCmd.AddCommand(viewCmd)
 	articleCmd.AddCommand(publishCmd)
 	articleCmd.AddCommand(deleteCmd)
+	articleCmd.AddCommand(pullCmd)
+	articleCmd.AddCommand(diffCmd)
 }
 
 func NewArticleCmd(c *config.Config) *cobra.Command {
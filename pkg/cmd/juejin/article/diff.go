@@ -0,0 +1,102 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/diff"
+	"github.com/k8scat/articli/pkg/markdown"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
+)
+
+var (
+	diffFields = []string{"title", "tags", "category", "cover_image"}
+
+	diffCmd = &cobra.Command{
+		Use:   "diff <markdownFile>",
+		Short: "Show the differences between the remote article and the markdown file",
+		Long: `Show the differences between the remote article and the markdown file.
+Exit status is 0 if there is no difference, 1 if there are differences, 2 if failed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			result, err := diffMark(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "diff failed: %s\n", err)
+				os.Exit(2)
+			}
+			if result.HasDiff() {
+				result.Print(os.Stdout)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func diffMark(ctx context.Context, markdownFile string) (*diff.Result, error) {
+	mark, err := markdown.Parse(markdownFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p, err := cmdutil.NewPublisher(cfg, juejinsdk.PlatformName, mark)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	publisher, ok := p.(*juejinsdk.Publisher)
+	if !ok {
+		return nil, errors.Errorf("unexpected publisher %T of %s", p, juejinsdk.PlatformName)
+	}
+	client := publisher.Client
+
+	var remote *markdown.Mark
+	if articleID := mark.Meta.GetString("juejin.article_id"); articleID != "" {
+		article, err := client.GetArticleContext(ctx, articleID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		remote = juejinsdk.NewMark(article)
+	} else if draftID := mark.Meta.GetString("juejin.draft_id"); draftID != "" {
+		draft, err := client.GetDraftContext(ctx, draftID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		remote = juejinsdk.NewDraftMark(draft)
+	} else {
+		return nil, errors.New("article_id or draft_id not found in juejin meta")
+	}
+
+	// The prefix and suffix content are published along with the content
+	mark.Content = diff.Body(mark, juejinsdk.PlatformName)
+	// The aliases and the names in other cases are published as the tags and the category they stand for
+	index, err := client.IndexContext(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result, err := diff.Compare(juejinsdk.PlatformName, mark, remote, diffFields, indexNormalizer(index))
+	return result, errors.Trace(err)
+}
+
+// indexNormalizer returns the names of the tags and the category in index, the names not found are kept
+func indexNormalizer(index *juejinsdk.Index) diff.Normalizer {
+	aliases := cfg.JuejinTagAliases()
+	return func(field, item string) string {
+		switch field {
+		case "tags":
+			if tag, err := index.FindTag(item, aliases); err == nil {
+				return tag.Tag.Name
+			}
+		case "category":
+			if category, err := index.FindCategory(item); err == nil {
+				return category.Category.Name
+			}
+		}
+		return item
+	}
+}
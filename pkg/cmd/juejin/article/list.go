@@ -29,7 +29,7 @@ var (
 			result := make([]*juejinsdk.Article, 0, limit)
 			page := 1
 			for {
-				articles, count, err := client.ListArticles(keyword, page, juejinsdk.MaxPageSize, juejinsdk.AuditStatus(status))
+				articles, count, err := client.ListArticlesContext(cmd.Context(), keyword, page, juejinsdk.MaxPageSize, juejinsdk.AuditStatus(status))
 				if err != nil {
 					return errors.Trace(err)
 				}
@@ -43,6 +43,7 @@ var (
 				result = result[:limit]
 			}
 
+			keys := []string{"id", "title", "display_count,number", "view_count,number", "digg_count,number", "comment_count,number", "collect_count,number", "create_time"}
 			header := []string{"ID", "标题", "展现", "阅读", "点赞", "评论", "收藏", "创建时间"}
 			data := make([][]string, 0, len(result))
 			for _, a := range result {
@@ -57,7 +58,7 @@ var (
 					juejinsdk.FormatTime(a.Info.CreateTime, "2006-01-02 15:04"),
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
@@ -17,7 +17,7 @@ var (
 				return cmd.Help()
 			}
 			draftID := args[0]
-			id, err := client.PublishArticle(draftID, syncToOrg)
+			id, err := client.PublishArticleContext(cmd.Context(), draftID, syncToOrg)
 			if err != nil {
 				return errors.Trace(err)
 			}
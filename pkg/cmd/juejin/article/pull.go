@@ -0,0 +1,57 @@
+package article
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/markdown"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
+)
+
+var (
+	pullOptions cmdutil.PullOptions
+
+	pullCmd = &cobra.Command{
+		Use:   "pull [articleID]",
+		Short: "Pull articles into local markdown files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !pullOptions.All && len(args) == 0 {
+				return cmd.Help()
+			}
+			return errors.Trace(pullOptions.Pull(cmd.Context(), args, listAllArticleIDs, pull))
+		},
+	}
+)
+
+func init() {
+	pullOptions.AddFlags(pullCmd)
+}
+
+func pull(ctx context.Context, id string) (*markdown.Mark, string, error) {
+	article, err := client.GetArticleContext(ctx, id)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return juejinsdk.NewMark(article), article.Info.Title, nil
+}
+
+func listAllArticleIDs(ctx context.Context) ([]string, error) {
+	ids := make([]string, 0)
+	page := 1
+	for {
+		articles, count, err := client.ListArticlesContext(ctx, "", page, juejinsdk.MaxPageSize, juejinsdk.AuditStatusAll)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range articles {
+			ids = append(ids, a.ID)
+		}
+		if len(ids) >= count || len(articles) < juejinsdk.MaxPageSize {
+			return ids, nil
+		}
+		page++
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/profile"
 	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +16,7 @@ var (
 		Use:   "auth",
 		Short: "Manage authentication state of juejin.cn",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = juejinsdk.NewClient(cfg.Platforms.Juejin.Cookie)
+			client, _ = juejinsdk.NewClient(cfg.Juejin().Cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 		},
 	}
 )
@@ -29,5 +30,8 @@ func init() {
 func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
 	cfgFile = cf
 	cfg = c
+
+	authCmd.AddCommand(profile.NewSwitchCmd("juejin", cfgFile, cfg))
+	authCmd.AddCommand(profile.NewListCmd("juejin", cfg))
 	return authCmd
 }
@@ -68,7 +68,7 @@ var (
 				}
 			}
 
-			client, err := juejinsdk.NewClient(cookie)
+			client, err := juejinsdk.NewClient(cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 			if err != nil {
 				fmt.Printf("error validating cookie: %s\n", err.Error())
 				os.Exit(1)
@@ -80,7 +80,8 @@ var (
 			fmt.Print("Logged in as ")
 			bo.Printf("%s\n", client.User.Name)
 
-			cfg.Platforms.Juejin.Cookie = cookie
+			account := cfg.Account(cfg.ProfileOf("juejin"))
+			account.Juejin.Cookie = cookie
 			if err = config.SaveConfig(cfgFile, cfg); err != nil {
 				return errors.Errorf("save config failed: %+v", errors.Trace(err))
 			}
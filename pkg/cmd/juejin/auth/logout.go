@@ -46,7 +46,8 @@ var (
 				break
 			}
 
-			cfg.Platforms.Juejin.Cookie = ""
+			account := cfg.Account(cfg.ProfileOf("juejin"))
+			account.Juejin.Cookie = ""
 			err := config.SaveConfig(cfgFile, cfg)
 			if err != nil {
 				return errors.Trace(err)
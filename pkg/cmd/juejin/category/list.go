@@ -12,11 +12,12 @@ var (
 		Use:   "list",
 		Short: "List all categories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			categories, err := client.ListCategories()
+			categories, err := client.ListCategoriesContext(cmd.Context())
 			if err != nil {
 				return errors.Trace(err)
 			}
 
+			keys := []string{"name", "hot_tags"}
 			header := []string{"名称", "热门标签"}
 			data := make([][]string, 0, len(categories))
 			for _, c := range categories {
@@ -29,7 +30,7 @@ var (
 					strings.Join(hotTags, ","),
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
@@ -3,12 +3,17 @@ package draft
 import (
 	"fmt"
 	"github.com/juju/errors"
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/image"
 	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
 	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
 	"github.com/spf13/cobra"
 )
 
 var (
+	skipImages bool
+
 	createCmd = &cobra.Command{
 		Use:   "create <markdownFile>",
 		Short: "Create or update a draft from a markdown file",
@@ -17,30 +22,37 @@ var (
 				return cmd.Help()
 			}
 
-			markdownFile := args[0]
-			mark, err := markdown.Parse(markdownFile)
+			// The ids left by the interrupted saves are written back before the file is parsed
+			j, err := cmdutil.RecoverJournal(cmd.Context(), cfg)
 			if err != nil {
 				return errors.Trace(err)
 			}
 
-			params, err := client.ParseMark(mark)
+			markdownFile := args[0]
+			mark, err := markdown.Parse(markdownFile)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			isCreate := false
-			if params.DraftID == "" {
-				isCreate = true
-			}
-
-			if err := client.SaveDraft(params); err != nil {
+			p, err := cmdutil.NewPublisher(cfg, juejinsdk.PlatformName, mark)
+			if err != nil {
 				return errors.Trace(err)
 			}
-
-			if err := juejinsdk.WriteBack(juejinsdk.SaveTypeDraft, mark, params, isCreate); err != nil {
+			var rw *image.MarkRewriter
+			if !skipImages {
+				if rw, err = cmdutil.NewMarkRewriter(cfg); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			result, err := cmdutil.SaveMark(cmd.Context(), j, p, mark, platform.SaveTypeDraft, false, rw)
+			if err != nil {
 				return errors.Trace(err)
 			}
-			fmt.Println(juejinsdk.BuildDraftEditorURL(params.DraftID))
+			fmt.Println(result.URL)
 			return nil
 		},
 	}
 )
+
+func init() {
+	createCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in the article")
+}
@@ -16,13 +16,14 @@ var (
 
 			failedList := make([][]string, 0)
 			for _, id := range args {
-				if err := client.DeleteDraft(id); err != nil {
+				if err := client.DeleteDraftContext(cmd.Context(), id); err != nil {
 					failedList = append(failedList, []string{id, err.Error()})
 				}
 			}
 			if len(failedList) > 0 {
+				keys := []string{"id", "error"}
 				header := []string{"ID", "Error"}
-				table.Print(header, failedList)
+				table.Print(keys, header, failedList)
 			}
 			return nil
 		},
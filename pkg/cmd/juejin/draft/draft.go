@@ -17,7 +17,11 @@ var (
 		Use:   "draft",
 		Short: "Manage drafts",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = juejinsdk.NewClient(cfg.Platforms.Juejin.Cookie)
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd {
+				return
+			}
+			client, _ = juejinsdk.NewClient(cfg.Juejin().Cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
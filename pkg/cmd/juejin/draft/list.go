@@ -26,7 +26,7 @@ var (
 			result := make([]*juejinsdk.Draft, 0, limit)
 			page := 1
 			for {
-				drafts, count, err := client.ListDrafts(keyword, page, juejinsdk.MaxPageSize)
+				drafts, count, err := client.ListDraftsContext(cmd.Context(), keyword, page, juejinsdk.MaxPageSize)
 				if err != nil {
 					return err
 				}
@@ -40,6 +40,7 @@ var (
 				result = result[:limit]
 			}
 
+			keys := []string{"id", "title", "create_time"}
 			header := []string{"ID", "标题", "创建时间"}
 			data := make([][]string, 0, len(result))
 			for _, draft := range result {
@@ -52,7 +53,7 @@ var (
 					juejinsdk.FormatTime(draft.CreateTime, "2006-01-02 15:04"),
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
@@ -17,7 +17,7 @@ var (
 		Use:   "image",
 		Short: "Manage images",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = juejinsdk.NewClient(cfg.Platforms.Juejin.Cookie)
+			client, _ = juejinsdk.NewClient(cfg.Juejin().Cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
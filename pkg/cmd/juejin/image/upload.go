@@ -1,14 +1,19 @@
 package image
 
 import (
+	"context"
 	"fmt"
+
 	"github.com/juju/errors"
-	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
 	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/image"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
 )
 
 var (
-	region string
+	region  string
+	noCache bool
 
 	uploadImageCmd = &cobra.Command{
 		Use:   "upload <imagePath>",
@@ -18,7 +23,16 @@ var (
 				return cmd.Help()
 			}
 			imagePath := args[0]
-			imageURL, err := client.UploadImage(region, imagePath)
+
+			var cache *image.Cache
+			if !noCache {
+				var err error
+				cache, err = image.LoadCache(image.DefaultCacheFile())
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			imageURL, err := image.Upload(cmd.Context(), &uploader{region: region}, cache, imagePath)
 			if err != nil {
 				return errors.Errorf("upload image failed: %s", errors.Trace(err))
 			}
@@ -30,4 +44,23 @@ var (
 
 func init() {
 	uploadImageCmd.Flags().StringVarP(&region, "region", "r", juejinsdk.RegionCNNorth, "region")
+	uploadImageCmd.Flags().BoolVar(&noCache, "no-cache", false, "Upload the image even if it was uploaded before")
+}
+
+// uploader caches the images of the default region with the publisher,
+// so that they are shared with acli publish and acli sync.
+type uploader struct {
+	region string
+}
+
+func (u *uploader) Host() string {
+	if u.region == juejinsdk.RegionCNNorth {
+		return juejinsdk.PlatformName
+	}
+	return fmt.Sprintf("%s:%s", juejinsdk.PlatformName, u.region)
+}
+
+func (u *uploader) Upload(ctx context.Context, path string) (string, error) {
+	imageURL, err := client.UploadImageContext(ctx, u.region, path)
+	return imageURL, errors.Trace(err)
 }
@@ -1,16 +1,12 @@
 package tag
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
-	"github.com/k8scat/articli/internal/config"
 	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
 	"github.com/k8scat/articli/pkg/table"
 
@@ -32,19 +28,21 @@ var (
 				return nil
 			}
 
-			cacheFile, exists, err := getCacheFile()
-			if err != nil {
-				return errors.Trace(err)
-			}
-
 			keyword = strings.TrimSpace(keyword)
 			result := make([]*juejinsdk.TagItem, 0, limit)
 
-			if notUseCache || !exists {
+			// The tags cached by tag sync are filtered locally, otherwise they are searched by the api
+			index, err := loadIndex()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if index != nil {
+				result = filterTags(index.Tags, keyword)
+			} else {
 				cursor := juejinsdk.StartCursor
 				for {
 					var tags []*juejinsdk.TagItem
-					tags, cursor, err = client.ListTags(keyword, cursor)
+					tags, cursor, err = client.ListTagsContext(cmd.Context(), keyword, cursor)
 					if err != nil {
 						return errors.Errorf("list tags failed: %+v", errors.Trace(err))
 					}
@@ -53,29 +51,12 @@ var (
 						break
 					}
 				}
-
-				b, err := json.Marshal(result)
-				if err != nil {
-					return errors.Trace(err)
-				}
-				err = ioutil.WriteFile(cacheFile, b, 0644)
-				if err != nil {
-					return errors.Trace(err)
-				}
-			} else {
-				b, err := ioutil.ReadFile(cacheFile)
-				if err != nil {
-					return errors.Trace(err)
-				}
-				if err = json.Unmarshal(b, &result); err != nil {
-					return errors.Errorf("invalid cache data: %s", string(b))
-				}
-				result = filterTags(result, keyword)
 			}
 			if len(result) > limit {
 				result = result[:limit]
 			}
 
+			keys := []string{"name", "article_count,number", "follower_count,number"}
 			header := []string{"名称", "文章", "关注者"}
 			data := make([][]string, 0, len(result))
 			for _, t := range result {
@@ -85,7 +66,7 @@ var (
 					strconv.Itoa(t.Tag.ConcernUserCount),
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
@@ -94,7 +75,7 @@ var (
 func init() {
 	listCmd.Flags().StringVarP(&keyword, "keyword", "k", "", "Filter keyword")
 	listCmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum number of tags to list")
-	listCmd.Flags().BoolVar(&notUseCache, "no-cache", false, "Not use cache data")
+	listCmd.Flags().BoolVar(&notUseCache, "no-cache", false, "Not use the tags cached by tag sync")
 }
 
 func filterTags(tags []*juejinsdk.TagItem, keyword string) []*juejinsdk.TagItem {
@@ -109,18 +90,21 @@ func filterTags(tags []*juejinsdk.TagItem, keyword string) []*juejinsdk.TagItem
 	return filtered
 }
 
-func getCacheFile() (cacheFile string, exists bool, err error) {
-	cfgDir := config.GetConfigDir()
+// loadIndex returns the index cached by tag sync, nil is returned if it is not used or expired
+func loadIndex() (*juejinsdk.Index, error) {
+	if notUseCache || client.IndexFile == "" {
+		return nil, nil
+	}
+	index, err := juejinsdk.LoadIndex(client.IndexFile)
 	if err != nil {
-		err = errors.Trace(err)
-		return
+		return nil, errors.Trace(err)
+	}
+	ttl := client.IndexTTL
+	if ttl == 0 {
+		ttl = juejinsdk.DefaultIndexTTL
 	}
-	cacheFile = filepath.Join(cfgDir, "tags.json")
-	var f os.FileInfo
-	f, err = os.Stat(cacheFile)
-	if err != nil || f.IsDir() {
-		exists = false
-		return
+	if index == nil || index.Expired(ttl) {
+		return nil, nil
 	}
-	return
+	return index, nil
 }
@@ -0,0 +1,29 @@
+package tag
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "List all the tags and categories and cache them in the config dir",
+		Long: `List all the tags and categories and cache them in the config dir.
+The cache is used to resolve the tag and category names in articles until it expires, see tag_cache_ttl in config.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			index, err := client.SyncIndexContext(cmd.Context())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if client.IndexFile == "" {
+				fmt.Println("tag_cache_ttl is negative, the tags are not cached")
+				return nil
+			}
+			fmt.Printf("Cached %d tags and %d categories in %s\n", len(index.Tags), len(index.Categories), client.IndexFile)
+			return nil
+		},
+	}
+)
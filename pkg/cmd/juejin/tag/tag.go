@@ -17,7 +17,7 @@ var (
 		Use:   "tag",
 		Short: "Manage tags",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = juejinsdk.NewClient(cfg.Platforms.Juejin.Cookie)
+			client, _ = juejinsdk.NewClient(cfg.Juejin().Cookie, juejinsdk.WithOptions(cfg.Juejin().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -28,6 +28,7 @@ var (
 
 func init() {
 	tagCmd.AddCommand(listCmd)
+	tagCmd.AddCommand(syncCmd)
 }
 
 func NewTagCmd(c *config.Config) *cobra.Command {
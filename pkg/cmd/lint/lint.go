@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/lint"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
+)
+
+var (
+	cfg *config.Config
+
+	platforms  []string
+	checkNames bool
+	strict     bool
+
+	lintCmd = &cobra.Command{
+		Use:   "lint <files...>",
+		Short: "Check the platform meta of markdown files without publishing them",
+		Long: `Check the platform meta of markdown files without publishing them.
+The directories are walked for markdown files, it exits with 1 if any error is found, e.g. in CI.`,
+		Example: `  acli lint posts/hello.md
+  acli lint --check-names --strict posts`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			files := make([]string, 0)
+			for _, arg := range args {
+				found, err := findMarkdownFiles(arg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				files = append(files, found...)
+			}
+
+			opts := &lint.Options{Platforms: platforms}
+			if checkNames {
+				names, err := loadNames()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				opts.Names = names
+			}
+
+			errs, warnings := 0, 0
+			for _, file := range files {
+				diags, err := lint.LintFile(file, opts)
+				if err != nil {
+					errs++
+					fmt.Printf("%s:1: %s: %s\n", file, lint.SeverityError, errors.Cause(err))
+					continue
+				}
+				for _, d := range diags {
+					if d.Severity == lint.SeverityError {
+						errs++
+					} else {
+						warnings++
+					}
+					fmt.Println(d)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Files: %d, Errors: %d, Warnings: %d\n", len(files), errs, warnings)
+
+			if errs > 0 || (strict && warnings > 0) {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	lintCmd.Flags().StringSliceVarP(&platforms, "platform", "p", nil, "Only check the specified platforms, e.g. juejin,csdn")
+	lintCmd.Flags().BoolVar(&checkNames, "check-names", false, "Check the juejin tags and category against the ones cached by acli juejin tag sync")
+	lintCmd.Flags().BoolVar(&strict, "strict", false, "Exit with 1 if any warning is found")
+}
+
+func NewLintCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return lintCmd
+}
+
+// loadNames loads the names in the juejin index cached by tag sync, the aliases of tags are known names too
+func loadNames() (map[string][]string, error) {
+	file := juejinsdk.IndexFilePath(config.GetConfigDir())
+	index, err := juejinsdk.LoadIndex(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if index == nil {
+		fmt.Fprintf(os.Stderr, "%s not found, run acli juejin tag sync to check the names\n", file)
+		return nil, nil
+	}
+	tags := index.TagNames()
+	for alias := range cfg.JuejinTagAliases() {
+		tags = append(tags, alias)
+	}
+	return map[string][]string{
+		"juejin.tags":     tags,
+		"juejin.category": index.CategoryNames(),
+	}, nil
+}
+
+func findMarkdownFiles(path string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if info.IsDir() {
+			if p != path && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// The files given explicitly are always checked
+		if p == path || strings.EqualFold(filepath.Ext(p), ".md") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, errors.Trace(err)
+}
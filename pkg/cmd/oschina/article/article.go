@@ -17,7 +17,11 @@ var (
 		Use:   "article",
 		Short: "Manage articles in oschina.net",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = oschinasdk.NewClient(cfg.Platforms.OSChina.Cookie)
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd || cmd == diffCmd {
+				return
+			}
+			client, _ = oschinasdk.NewClient(cfg.OSChina().Cookie, oschinasdk.WithOptions(cfg.OSChina().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -31,6 +35,8 @@ func init() {
 	articleCmd.AddCommand(deleteCmd)
 	articleCmd.AddCommand(listCmd)
 	articleCmd.AddCommand(publishCmd)
+	articleCmd.AddCommand(pullCmd)
+	articleCmd.AddCommand(diffCmd)
 }
 
 func NewArticleCmd(c *config.Config) *cobra.Command {
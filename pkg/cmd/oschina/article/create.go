@@ -3,12 +3,18 @@ package article
 import (
 	"fmt"
 	"github.com/juju/errors"
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/image"
 	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
 	oschinasdk "github.com/k8scat/articli/pkg/platform/oschina"
 	"github.com/spf13/cobra"
 )
 
 var (
+	allowDuplicate bool
+	skipImages     bool
+
 	createCmd = &cobra.Command{
 		Use:   "create <markdownFile>",
 		Short: "Create or update an article",
@@ -17,30 +23,38 @@ var (
 				return cmd.Help()
 			}
 
-			markdownFile := args[0]
-			mark, err := markdown.Parse(markdownFile)
+			// The ids left by the interrupted saves are written back before the file is parsed
+			j, err := cmdutil.RecoverJournal(cmd.Context(), cfg)
 			if err != nil {
 				return errors.Trace(err)
 			}
 
-			params, err := client.ParseMark(mark)
+			markdownFile := args[0]
+			mark, err := markdown.Parse(markdownFile)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			isCreate := false
-			if params.ID == "" {
-				isCreate = true
-			}
-
-			if err := client.SaveArticle(params); err != nil {
+			p, err := cmdutil.NewPublisher(cfg, oschinasdk.PlatformName, mark)
+			if err != nil {
 				return errors.Trace(err)
 			}
-
-			if err := oschinasdk.WriteBack(oschinasdk.SaveTypeArticle, mark, params, isCreate); err != nil {
+			var rw *image.MarkRewriter
+			if !skipImages {
+				if rw, err = cmdutil.NewMarkRewriter(cfg); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			result, err := cmdutil.SaveMark(cmd.Context(), j, p, mark, platform.SaveTypeArticle, allowDuplicate, rw)
+			if err != nil {
 				return errors.Trace(err)
 			}
-			fmt.Println(client.BuildArticleURL(params.ID))
+			fmt.Println(result.URL)
 			return nil
 		},
 	}
 )
+
+func init() {
+	createCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Create the article even if an article with the same title exists")
+	createCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in the article")
+}
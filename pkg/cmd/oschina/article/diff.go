@@ -0,0 +1,79 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/diff"
+	"github.com/k8scat/articli/pkg/markdown"
+	oschinasdk "github.com/k8scat/articli/pkg/platform/oschina"
+)
+
+var (
+	diffFields = []string{"title", "category", "cover_image"}
+
+	diffCmd = &cobra.Command{
+		Use:   "diff <markdownFile>",
+		Short: "Show the differences between the remote article and the markdown file",
+		Long: `Show the differences between the remote article and the markdown file.
+Exit status is 0 if there is no difference, 1 if there are differences, 2 if failed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			result, err := diffMark(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "diff failed: %s\n", err)
+				os.Exit(2)
+			}
+			if result.HasDiff() {
+				result.Print(os.Stdout)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func diffMark(ctx context.Context, markdownFile string) (*diff.Result, error) {
+	mark, err := markdown.Parse(markdownFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p, err := cmdutil.NewPublisher(cfg, oschinasdk.PlatformName, mark)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	publisher, ok := p.(*oschinasdk.Publisher)
+	if !ok {
+		return nil, errors.Errorf("unexpected publisher %T of %s", p, oschinasdk.PlatformName)
+	}
+	client := publisher.Client
+
+	var remote *markdown.Mark
+	if articleID := mark.Meta.GetString("oschina.article_id"); articleID != "" {
+		article, err := client.GetArticleDetailContext(ctx, articleID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		remote = oschinasdk.NewMark(article)
+	} else if draftID := mark.Meta.GetString("oschina.draft_id"); draftID != "" {
+		draft, err := client.GetDraftDetailContext(ctx, draftID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		remote = oschinasdk.NewMark(draft)
+	} else {
+		return nil, errors.New("article_id or draft_id not found in oschina meta")
+	}
+
+	// The prefix and suffix content are published along with the content
+	mark.Content = diff.Body(mark, oschinasdk.PlatformName)
+	result, err := diff.Compare(oschinasdk.PlatformName, mark, remote, diffFields, nil)
+	return result, errors.Trace(err)
+}
@@ -27,7 +27,7 @@ var (
 			result := make([]*oschinasdk.Article, 0, limit)
 			page := 1
 			for {
-				articles, hasNext, err := client.ListArticles(page, keyword)
+				articles, hasNext, err := client.ListArticlesContext(cmd.Context(), page, keyword)
 				if err != nil {
 					return errors.Trace(err)
 				}
@@ -41,6 +41,7 @@ var (
 				result = result[:limit]
 			}
 
+			keys := []string{"title", "url"}
 			header := []string{"标题", "链接"}
 			data := make([][]string, 0, len(result))
 			for _, a := range result {
@@ -49,7 +50,7 @@ var (
 					a.URL,
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
@@ -15,7 +15,7 @@ var (
 				return cmd.Help()
 			}
 			draftID := args[0]
-			id, err := client.PublishDraft(draftID)
+			id, err := client.PublishDraftContext(cmd.Context(), draftID)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -0,0 +1,57 @@
+package article
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/markdown"
+	oschinasdk "github.com/k8scat/articli/pkg/platform/oschina"
+)
+
+var (
+	pullOptions cmdutil.PullOptions
+
+	pullCmd = &cobra.Command{
+		Use:   "pull [articleID]",
+		Short: "Pull articles into local markdown files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !pullOptions.All && len(args) == 0 {
+				return cmd.Help()
+			}
+			return errors.Trace(pullOptions.Pull(cmd.Context(), args, listAllArticleIDs, pull))
+		},
+	}
+)
+
+func init() {
+	pullOptions.AddFlags(pullCmd)
+}
+
+func pull(ctx context.Context, id string) (*markdown.Mark, string, error) {
+	params, err := client.GetArticleDetailContext(ctx, id)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return oschinasdk.NewMark(params), params.Title, nil
+}
+
+func listAllArticleIDs(ctx context.Context) ([]string, error) {
+	ids := make([]string, 0)
+	page := 1
+	for {
+		articles, hasNext, err := client.ListArticlesContext(ctx, page, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range articles {
+			ids = append(ids, a.ID)
+		}
+		if !hasNext {
+			return ids, nil
+		}
+		page++
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/profile"
 	oschinasdk "github.com/k8scat/articli/pkg/platform/oschina"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +16,7 @@ var (
 		Use:   "auth",
 		Short: "Manage authentication state of oschina.net",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = oschinasdk.NewClient(cfg.Platforms.OSChina.Cookie)
+			client, _ = oschinasdk.NewClient(cfg.OSChina().Cookie, oschinasdk.WithOptions(cfg.OSChina().Options()))
 		},
 	}
 )
@@ -29,5 +30,8 @@ func init() {
 func NewAuthCmd(cf string, c *config.Config) *cobra.Command {
 	cfgFile = cf
 	cfg = c
+
+	authCmd.AddCommand(profile.NewSwitchCmd("oschina", cfgFile, cfg))
+	authCmd.AddCommand(profile.NewListCmd("oschina", cfg))
 	return authCmd
 }
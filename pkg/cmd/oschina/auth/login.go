@@ -68,7 +68,7 @@ var (
 				}
 			}
 
-			client, err := oschinasdk.NewClient(cookie)
+			client, err := oschinasdk.NewClient(cookie, oschinasdk.WithOptions(cfg.OSChina().Options()))
 			if err != nil {
 				fmt.Printf("error validating cookie: %s\n", err.Error())
 				os.Exit(1)
@@ -80,7 +80,8 @@ var (
 			fmt.Print("Logged in as ")
 			bo.Printf("%s\n", client.UserName)
 
-			cfg.Platforms.OSChina.Cookie = cookie
+			account := cfg.Account(cfg.ProfileOf("oschina"))
+			account.OSChina.Cookie = cookie
 			if err = config.SaveConfig(cfgFile, cfg); err != nil {
 				return errors.Errorf("save config failed: %+v", errors.Trace(err))
 			}
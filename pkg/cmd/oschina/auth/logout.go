@@ -46,7 +46,8 @@ var (
 				break
 			}
 
-			cfg.Platforms.OSChina.Cookie = ""
+			account := cfg.Account(cfg.ProfileOf("oschina"))
+			account.OSChina.Cookie = ""
 			err := config.SaveConfig(cfgFile, cfg)
 			if err != nil {
 				return errors.Trace(err)
@@ -22,7 +22,7 @@ var (
 				os.Exit(1)
 			} else {
 				gr.Print("✓ ")
-				gr.Printf("Logged in to oschina as %s (%s)\n", client.UserName, cfgFile)
+				gr.Printf("Logged in to oschina as %s (profile %s, %s)\n", client.UserName, cfg.ProfileOf("oschina"), cfgFile)
 			}
 		},
 	}
@@ -17,7 +17,7 @@ var (
 		Use:   "category",
 		Short: "Manage categories",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = oschinasdk.NewClient(cfg.Platforms.OSChina.Cookie)
+			client, _ = oschinasdk.NewClient(cfg.OSChina().Cookie, oschinasdk.WithOptions(cfg.OSChina().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
@@ -11,11 +11,12 @@ var (
 		Use:   "list",
 		Short: "List all categories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			categories, err := client.ListCategories()
+			categories, err := client.ListCategoriesContext(cmd.Context())
 			if err != nil {
 				return errors.Trace(err)
 			}
 
+			keys := []string{"name"}
 			header := []string{"名称"}
 			data := make([][]string, 0, len(categories))
 			for _, c := range categories {
@@ -23,7 +24,7 @@ var (
 					c.Name,
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
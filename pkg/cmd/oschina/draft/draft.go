@@ -17,7 +17,11 @@ var (
 		Use:   "draft",
 		Short: "Manage drafts",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			client, _ = oschinasdk.NewClient(cfg.Platforms.OSChina.Cookie)
+			// The commands of markdown files create the client with the profile pinned in the file
+			if cmd == createCmd {
+				return
+			}
+			client, _ = oschinasdk.NewClient(cfg.OSChina().Cookie, oschinasdk.WithOptions(cfg.OSChina().Options()))
 			if client == nil {
 				fmt.Println("please login first")
 				os.Exit(1)
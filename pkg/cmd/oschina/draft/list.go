@@ -26,7 +26,7 @@ var (
 			result := make([]*oschinasdk.Draft, 0, limit)
 			page := 1
 			for {
-				drafts, hasNext, err := client.ListDrafts(page)
+				drafts, hasNext, err := client.ListDraftsContext(cmd.Context(), page)
 				if err != nil {
 					return err
 				}
@@ -40,6 +40,7 @@ var (
 				result = result[:limit]
 			}
 
+			keys := []string{"id", "title"}
 			header := []string{"ID", "标题"}
 			data := make([][]string, 0, len(result))
 			for _, draft := range result {
@@ -48,7 +49,7 @@ var (
 					draft.Title,
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
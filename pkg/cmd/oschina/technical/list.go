@@ -11,11 +11,12 @@ var (
 		Use:   "list",
 		Short: "List all technical fields",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			technicals, err := client.ListTechnicalFields()
+			technicals, err := client.ListTechnicalFieldsContext(cmd.Context())
 			if err != nil {
 				return errors.Trace(err)
 			}
 
+			keys := []string{"name"}
 			header := []string{"名称"}
 			data := make([][]string, 0, len(technicals))
 			for _, t := range technicals {
@@ -23,7 +24,7 @@ var (
 					t.Name,
 				})
 			}
-			table.Print(header, data)
+			table.Print(keys, header, data)
 			return nil
 		},
 	}
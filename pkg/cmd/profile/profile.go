@@ -0,0 +1,65 @@
+// Package profile provides the commands to manage the named accounts of a platform,
+// they are added to the auth command of every platform.
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+// NewSwitchCmd returns the command which makes a profile the current one of the platform
+func NewSwitchCmd(platform, cfgFile string, cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "switch <profile>",
+		Short: fmt.Sprintf("Switch the current account of %s to a profile", platform),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			name := args[0]
+			if err := cfg.Switch(platform, name); err != nil {
+				fmt.Printf("%s, run acli --profile %s %s auth login to add it\n", err, name, platform)
+				os.Exit(1)
+				return nil
+			}
+			if err := config.SaveConfig(cfgFile, cfg); err != nil {
+				return errors.Errorf("save config failed: %+v", errors.Trace(err))
+			}
+
+			gr := color.New(color.FgGreen)
+			gr.Print("✓ ")
+			fmt.Printf("Switched %s to profile '%s'\n", platform, name)
+			return nil
+		},
+	}
+}
+
+// NewListCmd returns the command which lists the profiles having an account of the platform
+func NewListCmd(platform string, cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("List the profiles logged in to %s", platform),
+		Run: func(cmd *cobra.Command, args []string) {
+			current := cfg.ProfileOf(platform)
+			keys := []string{"profile", "current"}
+			header := []string{"Profile", "Current"}
+			data := make([][]string, 0)
+			for _, name := range cfg.ProfileNames(platform) {
+				mark := ""
+				if name == current {
+					mark = "*"
+				}
+				data = append(data, []string{name, mark})
+			}
+			table.Print(keys, header, data)
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/image"
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	_ "github.com/k8scat/articli/pkg/platform/csdn"
+	_ "github.com/k8scat/articli/pkg/platform/juejin"
+	_ "github.com/k8scat/articli/pkg/platform/oschina"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	cfg *config.Config
+
+	platforms      []string
+	skipImages     bool
+	allowDuplicate bool
+
+	publishCmd = &cobra.Command{
+		Use:   "publish <markdownFile>",
+		Short: "Create or update an article in every platform configured in the markdown file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			// The ids left by the interrupted publishes are written back before the file is parsed
+			ctx := cmd.Context()
+			j, err := cmdutil.RecoverJournal(ctx, cfg)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			markdownFile := args[0]
+			mark, err := markdown.Parse(markdownFile)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			if mark.Draft {
+				fmt.Println("the article is a draft, skipped")
+				return nil
+			}
+			targets := platform.Targets(mark, platforms)
+			if len(targets) == 0 {
+				fmt.Println("no platform meta found")
+				os.Exit(1)
+				return nil
+			}
+
+			var rw *image.MarkRewriter
+			if !skipImages {
+				if rw, err = cmdutil.NewMarkRewriter(cfg); err != nil {
+					return errors.Trace(err)
+				}
+			}
+
+			keys := []string{"platform", "action", "id", "url", "error"}
+			header := []string{"Platform", "Action", "ID", "URL", "Error"}
+			data := make([][]string, 0, len(targets))
+			succeeded, failed := 0, 0
+			entries := make([]string, 0, len(targets))
+			for _, name := range targets {
+				// The platforms published before the interruption are still written back and reported
+				if err := ctx.Err(); err != nil {
+					failed++
+					data = append(data, []string{name, "canceled", "", "", err.Error()})
+					continue
+				}
+				// The entry of a failed publish is kept unless it failed before the request was sent,
+				// the article may be created though the response is lost
+				result, entry, err := publish(ctx, j, name, mark, rw)
+				if err != nil {
+					failed++
+					data = append(data, []string{name, "failed", "", "", err.Error()})
+					continue
+				}
+				entries = append(entries, entry.ID)
+				succeeded++
+				action := "updated"
+				if result.IsCreate {
+					action = "created"
+				}
+				data = append(data, []string{name, action, result.ArticleID, result.URL, ""})
+			}
+
+			// All the ids are written back at once, so that the file is only rewritten one time
+			if succeeded > 0 {
+				if err = mark.WriteFile(mark.File); err != nil {
+					return errors.Annotate(err, "write back failed, the ids are kept in the journal and written back next time")
+				}
+				if err = j.Finish(entries...); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			table.Print(keys, header, data)
+
+			if failed > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	publishCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in articles")
+	publishCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Create the article even if an article with the same title exists")
+	publishCmd.Flags().StringSliceVarP(&platforms, "platform", "p", nil, "Only publish to the specified platforms, e.g. juejin,csdn")
+}
+
+func NewPublishCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return publishCmd
+}
+
+func newPublisher(name string, mark *markdown.Mark) (platform.Publisher, error) {
+	p, err := cmdutil.NewPublisher(cfg, name, mark)
+	return p, errors.Trace(err)
+}
+
+// publish saves the article with a journaled entry, which is finished after the ids are written back to the file
+func publish(ctx context.Context, j *journal.Journal, name string, mark *markdown.Mark, rw *image.MarkRewriter) (*platform.Result, *journal.Entry, error) {
+	publisher, err := newPublisher(name, mark)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !allowDuplicate {
+		if err = platform.CheckDuplicate(ctx, publisher, mark); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	// The rewritten links are only published, the markdown file keeps the local images
+	m := mark
+	if rw != nil {
+		if m, err = rw.RewriteMark(ctx, publisher, mark); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	result, entry, err := j.Save(mark.File, name, platform.SaveTypeArticle, platform.Title(mark, name), func() (*platform.Result, error) {
+		return publisher.Publish(ctx, m)
+	})
+	if err != nil {
+		return nil, entry, errors.Trace(err)
+	}
+	err = publisher.WriteBack(mark, result)
+	return result, entry, errors.Trace(err)
+}
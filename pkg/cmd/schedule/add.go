@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/schedule"
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	at        string
+	platforms []string
+
+	addCmd = &cobra.Command{
+		Use:   "add <markdownFile>",
+		Short: "Schedule an article to be published at the publish_at time",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			markdownFile := args[0]
+			mark, err := markdown.Parse(markdownFile)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if mark.Draft {
+				fmt.Println("the article is a draft, please publish it first")
+				os.Exit(1)
+				return nil
+			}
+			targets := platform.Targets(mark, platforms)
+			if len(targets) == 0 {
+				fmt.Println("no platform meta found")
+				os.Exit(1)
+				return nil
+			}
+
+			var atTime time.Time
+			if at != "" {
+				if atTime, err = schedule.ParseTime(at); err != nil {
+					return errors.Trace(err)
+				}
+			}
+
+			keys := []string{"id", "platform", "publish_at"}
+			header := []string{"ID", "Platform", "Publish At"}
+			data := make([][]string, 0, len(targets))
+			for _, name := range targets {
+				publishAt := atTime
+				if publishAt.IsZero() {
+					if publishAt, err = schedule.GetPublishAt(mark, name); err != nil {
+						return errors.Annotatef(err, "%s", name)
+					}
+					if publishAt.IsZero() {
+						return errors.Errorf("%s is not set for %s, please set it in the markdown file or use --at", schedule.PublishAtKey, name)
+					}
+				}
+				if publishAt.Before(time.Now()) {
+					color.Yellow("! %s will be published at the next run since %s has passed", name, publishAt.Format(timeFormat))
+				}
+				job, err := queue.Add(markdownFile, name, publishAt)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				data = append(data, []string{job.ID, name, publishAt.Format(timeFormat)})
+			}
+			table.Print(keys, header, data)
+			return nil
+		},
+	}
+)
+
+func init() {
+	addCmd.Flags().StringVar(&at, "at", "", "Publish time which overrides publish_at in the markdown file, e.g. '2006-01-02 15:04'")
+	addCmd.Flags().StringSliceVarP(&platforms, "platform", "p", nil, "Only schedule the specified platforms, e.g. juejin,csdn")
+}
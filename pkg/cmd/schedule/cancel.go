@@ -0,0 +1,24 @@
+package schedule
+
+import (
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cancelCmd = &cobra.Command{
+		Use:   "cancel <id>...",
+		Short: "Cancel scheduled articles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			for _, id := range args {
+				if err := queue.Cancel(id); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
+		},
+	}
+)
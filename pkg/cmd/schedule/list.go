@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/table"
+)
+
+var (
+	all bool
+
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled articles",
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := []string{"id", "file", "platform", "publish_at", "status", "url", "error"}
+			header := []string{"ID", "File", "Platform", "Publish At", "Status", "URL", "Error"}
+			data := make([][]string, 0)
+			for _, job := range queue.Jobs() {
+				if job.Done() && !all {
+					continue
+				}
+				data = append(data, []string{
+					job.ID,
+					job.File,
+					job.Platform,
+					job.PublishAt.Format(timeFormat),
+					string(job.Status),
+					job.URL,
+					job.Error,
+				})
+			}
+			table.Print(keys, header, data)
+		},
+	}
+)
+
+func init() {
+	listCmd.Flags().BoolVarP(&all, "all", "a", false, "Show the published, failed and canceled articles too")
+}
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/schedule"
+)
+
+var (
+	once           bool
+	interval       time.Duration
+	draftAhead     time.Duration
+	maxAttempts    int
+	skipImages     bool
+	allowDuplicate bool
+
+	runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Save drafts ahead and publish the scheduled articles when they are due",
+		Long: `Save drafts ahead and publish the scheduled articles when they are due.
+It keeps running and checks the queue every interval, use --once to check only one time, e.g. in a cron job.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				fmt.Println("interval must be greater than 0")
+				os.Exit(1)
+				return nil
+			}
+
+			j, err := journal.LoadJournal(journal.DefaultJournalFile())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			r := &schedule.Runner{
+				Queue: queue,
+				NewPublisher: func(name string, mark *markdown.Mark) (platform.Publisher, error) {
+					p, err := cmdutil.NewPublisher(cfg, name, mark)
+					return p, errors.Trace(err)
+				},
+				Journal:        j,
+				AllowDuplicate: allowDuplicate,
+				DraftAhead:     draftAhead,
+				MaxAttempts:    maxAttempts,
+				Logf:           log.Printf,
+			}
+			if !skipImages {
+				rw, err := cmdutil.NewMarkRewriter(cfg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				r.Rewriter = rw
+			}
+
+			ctx := cmd.Context()
+			if once {
+				return errors.Trace(r.RunOnce(ctx))
+			}
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("run failed: %s", err)
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+)
+
+func init() {
+	runCmd.Flags().BoolVar(&once, "once", false, "Check the queue one time and exit")
+	runCmd.Flags().DurationVar(&interval, "interval", time.Minute, "Interval between checks")
+	runCmd.Flags().DurationVar(&draftAhead, "draft-ahead", schedule.DefaultDraftAhead, "How long before the publish time the draft of a new article is saved")
+	runCmd.Flags().IntVar(&maxAttempts, "max-attempts", schedule.DefaultMaxAttempts, "How many times an article is tried before it is marked as failed")
+	runCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in articles")
+	runCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Create the articles even if the articles with the same titles exist")
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	_ "github.com/k8scat/articli/pkg/platform/csdn"
+	_ "github.com/k8scat/articli/pkg/platform/juejin"
+	_ "github.com/k8scat/articli/pkg/platform/oschina"
+	"github.com/k8scat/articli/pkg/schedule"
+)
+
+const timeFormat = "2006-01-02 15:04"
+
+var (
+	cfg   *config.Config
+	queue *schedule.Queue
+
+	scheduleCmd = &cobra.Command{
+		Use:   "schedule",
+		Short: "Publish articles at the publish_at time",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			queue, err = schedule.LoadQueue(schedule.DefaultQueueFile())
+			return errors.Trace(err)
+		},
+	}
+)
+
+func init() {
+	scheduleCmd.AddCommand(addCmd)
+	scheduleCmd.AddCommand(listCmd)
+	scheduleCmd.AddCommand(cancelCmd)
+	scheduleCmd.AddCommand(runCmd)
+}
+
+func NewScheduleCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return scheduleCmd
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+var (
+	migrateCmd = &cobra.Command{
+		Use:   "migrate <files...>",
+		Short: "Move the ids, times and hashes in the front matter to the state file",
+		Long: `Move the ids, times and hashes in the front matter to the state file.
+The state backend is set by state in .articli.yml, one of manifest and sidecar.
+The directories are walked for markdown files, and the files kept in the front matter are skipped.`,
+		Example: `  acli state migrate posts`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			files := make([]string, 0)
+			for _, arg := range args {
+				found, err := findMarkdownFiles(arg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				files = append(files, found...)
+			}
+
+			gr := color.New(color.FgGreen)
+			migrated, skipped, failed := 0, 0, 0
+			for _, file := range files {
+				mark, err := markdown.Parse(file)
+				if err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "%s: %s\n", file, errors.Cause(err))
+					continue
+				}
+				if mark.StateFile() == "" {
+					skipped++
+					continue
+				}
+				changed, err := mark.MigrateState()
+				if err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "%s: %s\n", file, errors.Cause(err))
+					continue
+				}
+				if !changed {
+					skipped++
+					continue
+				}
+				migrated++
+				gr.Print("✓ ")
+				fmt.Printf("%s -> %s\n", file, mark.StateFile())
+			}
+
+			fmt.Printf("Migrated: %d, Skipped: %d, Failed: %d\n", migrated, skipped, failed)
+			if failed > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func findMarkdownFiles(path string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if info.IsDir() {
+			if p != path && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// The files given explicitly are always migrated
+		if p == path || strings.EqualFold(filepath.Ext(p), ".md") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, errors.Trace(err)
+}
@@ -0,0 +1,18 @@
+package state
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the state written back after publishing",
+}
+
+func init() {
+	stateCmd.AddCommand(migrateCmd)
+}
+
+func NewStateCmd() *cobra.Command {
+	return stateCmd
+}
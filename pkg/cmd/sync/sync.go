@@ -0,0 +1,357 @@
+package synccmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/cmd/cmdutil"
+	"github.com/k8scat/articli/pkg/image"
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	_ "github.com/k8scat/articli/pkg/platform/csdn"
+	_ "github.com/k8scat/articli/pkg/platform/juejin"
+	_ "github.com/k8scat/articli/pkg/platform/oschina"
+	"github.com/k8scat/articli/pkg/table"
+	"github.com/k8scat/articli/pkg/transport"
+)
+
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionSkip   = "skip"
+	actionFail   = "fail"
+	actionCancel = "cancel"
+)
+
+var (
+	cfg *config.Config
+
+	dryRun         bool
+	platforms      []string
+	concurrency    int
+	skipImages     bool
+	showStats      bool
+	allowDuplicate bool
+
+	syncCmd = &cobra.Command{
+		Use:   "sync <dir>",
+		Short: "Publish the changed articles in a directory to every configured platform",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			if concurrency <= 0 {
+				fmt.Println("concurrency must be greater than 0")
+				os.Exit(1)
+				return nil
+			}
+
+			s := &syncer{
+				publishers: make(map[string]platform.Publisher),
+				errs:       make(map[string]error),
+			}
+			// The ids left by the interrupted publishes are written back before the files are parsed
+			if !dryRun {
+				j, err := journal.LoadJournal(journal.DefaultJournalFile())
+				if err != nil {
+					return errors.Trace(err)
+				}
+				recoveries, err := j.Recover(cmd.Context(), s.getPublisher)
+				for _, r := range recoveries {
+					fmt.Fprintln(os.Stderr, r)
+				}
+				if err != nil {
+					return errors.Trace(err)
+				}
+				s.journal = j
+			}
+
+			files, err := findMarkdownFiles(args[0])
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			if !skipImages && !dryRun {
+				rw, err := cmdutil.NewMarkRewriter(cfg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				s.rw = rw
+			}
+			results := make([][]*result, len(files))
+			var wg sync.WaitGroup
+			sem := make(chan struct{}, concurrency)
+			for i, file := range files {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(i int, file string) {
+					defer func() {
+						<-sem
+						wg.Done()
+					}()
+					results[i] = s.syncFile(cmd.Context(), file)
+				}(i, file)
+			}
+			wg.Wait()
+
+			keys := []string{"file", "platform", "action", "url", "error"}
+			header := []string{"File", "Platform", "Action", "URL", "Error"}
+			data := make([][]string, 0)
+			summary := make(map[string]int)
+			for _, rs := range results {
+				for _, r := range rs {
+					summary[r.action]++
+					if r.action == actionSkip {
+						continue
+					}
+					var errMsg string
+					if r.err != nil {
+						errMsg = r.err.Error()
+					}
+					data = append(data, []string{r.file, r.platform, r.action, r.url, errMsg})
+				}
+			}
+			if len(data) > 0 || table.IsStructured() {
+				table.Print(keys, header, data)
+			}
+			// The summary does not break the structured output
+			w := os.Stdout
+			if table.IsStructured() {
+				w = os.Stderr
+			}
+			if dryRun {
+				fmt.Fprint(w, "[dry run] ")
+			}
+			fmt.Fprintf(w, "Created: %d, Updated: %d, Skipped: %d, Failed: %d",
+				summary[actionCreate], summary[actionUpdate], summary[actionSkip], summary[actionFail])
+			if summary[actionCancel] > 0 {
+				fmt.Fprintf(w, ", Canceled: %d", summary[actionCancel])
+			}
+			fmt.Fprintln(w)
+			if showStats {
+				printStats(w)
+			}
+
+			if summary[actionFail] > 0 || summary[actionCancel] > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	syncCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Do not upload the local images in articles")
+	syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print what would be published")
+	syncCmd.Flags().StringSliceVarP(&platforms, "platform", "p", nil, "Only sync to the specified platforms, e.g. juejin,csdn")
+	syncCmd.Flags().IntVarP(&concurrency, "concurrency", "n", 4, "Maximum number of files to publish at the same time")
+	syncCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Create the articles even if the articles with the same titles exist")
+	syncCmd.Flags().BoolVar(&showStats, "stats", false, "Print the requests, retries and latencies of every platform")
+}
+
+func NewSyncCmd(c *config.Config) *cobra.Command {
+	cfg = c
+	return syncCmd
+}
+
+// printStats prints the metrics of the requests sent to the platforms
+func printStats(w io.Writer) {
+	keys := []string{"platform", "requests,number", "attempts,number", "retries,number", "failures,number", "avg_latency", "max_latency"}
+	header := []string{"Platform", "Requests", "Attempts", "Retries", "Failures", "Avg Latency", "Max Latency"}
+	data := make([][]string, 0)
+	for _, s := range transport.DefaultMetrics.Snapshot() {
+		data = append(data, []string{
+			s.Name,
+			strconv.Itoa(s.Requests),
+			strconv.Itoa(s.Attempts),
+			strconv.Itoa(s.Retries),
+			strconv.Itoa(s.Failures),
+			s.AvgLatency().Round(time.Millisecond).String(),
+			s.MaxLatency.Round(time.Millisecond).String(),
+		})
+	}
+	if err := table.Fprint(w, keys, header, data); err != nil {
+		fmt.Fprintf(os.Stderr, "print stats failed: %s\n", err)
+	}
+}
+
+// failAction reports the failures caused by the interruption as canceled
+func failAction(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return actionCancel
+	}
+	return actionFail
+}
+
+type result struct {
+	file     string
+	platform string
+	action   string
+	url      string
+	err      error
+}
+
+// syncer shares one publisher per platform and profile between all the files
+type syncer struct {
+	mu         sync.Mutex
+	publishers map[string]platform.Publisher
+	errs       map[string]error
+	rw         *image.MarkRewriter
+	journal    *journal.Journal
+}
+
+func (s *syncer) getPublisher(name string, mark *markdown.Mark) (platform.Publisher, error) {
+	key := name
+	if profile := platform.Profile(mark, name); profile != "" {
+		key += "@" + profile
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.publishers[key]; ok {
+		return p, nil
+	}
+	if err, ok := s.errs[key]; ok {
+		return nil, err
+	}
+	p, err := cmdutil.NewPublisher(cfg, name, mark)
+	if err != nil {
+		err = errors.Trace(err)
+		s.errs[key] = err
+		return nil, err
+	}
+	s.publishers[key] = p
+	return p, nil
+}
+
+// syncFile publishes the changed platforms of file, the platforms not published yet are canceled when ctx is done
+func (s *syncer) syncFile(ctx context.Context, file string) []*result {
+	if err := ctx.Err(); err != nil {
+		return []*result{{file: file, action: actionCancel, err: err}}
+	}
+	mark, err := markdown.Parse(file)
+	if err != nil {
+		return []*result{{file: file, action: actionFail, err: errors.Trace(err)}}
+	}
+	if mark.Draft {
+		return []*result{{file: file, action: actionSkip}}
+	}
+
+	// The local images are published as uploaded, so an edit of only an image is a change too
+	var images []string
+	if s.rw != nil {
+		if images, err = image.HashImages(mark); err != nil {
+			return []*result{{file: file, action: actionFail, err: errors.Trace(err)}}
+		}
+	}
+
+	results := make([]*result, 0)
+	changed := false
+	entries := make([]string, 0)
+	for _, name := range platform.Targets(mark, platforms) {
+		r := &result{file: file, platform: name}
+		results = append(results, r)
+
+		hash, err := platform.ContentHash(mark, name, images...)
+		if err != nil {
+			r.action, r.err = actionFail, errors.Trace(err)
+			continue
+		}
+		if hash == platform.GetContentHash(mark, name) {
+			r.action = actionSkip
+			continue
+		}
+		if mark.Meta.GetString(name+".article_id") == "" {
+			r.action = actionCreate
+		} else {
+			r.action = actionUpdate
+		}
+		if dryRun {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			r.action, r.err = actionCancel, err
+			continue
+		}
+
+		p, err := s.getPublisher(name, mark)
+		if err != nil {
+			r.action, r.err = actionFail, err
+			continue
+		}
+		if r.action == actionCreate && !allowDuplicate {
+			if err = platform.CheckDuplicate(ctx, p, mark); err != nil {
+				r.action, r.err = failAction(ctx), errors.Trace(err)
+				continue
+			}
+		}
+		// The rewritten links are only published, the markdown file keeps the local images
+		m := mark
+		if s.rw != nil {
+			if m, err = s.rw.RewriteMark(ctx, p, mark); err != nil {
+				r.action, r.err = failAction(ctx), errors.Trace(err)
+				continue
+			}
+		}
+		res, entry, err := s.journal.Save(mark.File, name, platform.SaveTypeArticle, platform.Title(mark, name), func() (*platform.Result, error) {
+			return p.Publish(ctx, m)
+		})
+		if err != nil {
+			r.action, r.err = failAction(ctx), errors.Trace(err)
+			continue
+		}
+		entries = append(entries, entry.ID)
+		r.url = res.URL
+		if err = p.WriteBack(mark, res); err != nil {
+			r.action, r.err = actionFail, errors.Trace(err)
+			continue
+		}
+		platform.SetContentHash(mark, name, hash)
+		changed = true
+	}
+
+	if changed {
+		if err = mark.WriteFile(mark.File); err != nil {
+			for _, r := range results {
+				if r.action == actionCreate || r.action == actionUpdate {
+					r.action, r.err = actionFail, errors.Annotate(err, "write back failed, the ids are kept in the journal")
+				}
+			}
+		} else if err = s.journal.Finish(entries...); err != nil {
+			fmt.Fprintf(os.Stderr, "finish journal failed: %s\n", err)
+		}
+	}
+	return results
+}
+
+func findMarkdownFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".md") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, errors.Trace(err)
+}
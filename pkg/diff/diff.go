@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/juju/errors"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+// Field is a metadata field whose value differs between the remote and the local version
+type Field struct {
+	Name   string
+	Remote string
+	Local  string
+}
+
+// Result is the difference between the remote and the local version of an article
+type Result struct {
+	// Body is the unified diff of the markdown body, empty if the body is not changed
+	Body   string
+	Fields []*Field
+}
+
+func (r *Result) HasDiff() bool {
+	return r.Body != "" || len(r.Fields) > 0
+}
+
+// Print writes the changed fields and the body diff to w
+func (r *Result) Print(w io.Writer) {
+	for _, f := range r.Fields {
+		fmt.Fprintf(w, "%s:\n", f.Name)
+		fmt.Fprintln(w, color.RedString("- %s", f.Remote))
+		fmt.Fprintln(w, color.GreenString("+ %s", f.Local))
+	}
+	if r.Body == "" {
+		return
+	}
+	if len(r.Fields) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, line := range strings.SplitAfter(r.Body, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Fprint(w, color.New(color.Bold).Sprint(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Fprint(w, color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Fprint(w, color.RedString(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Fprint(w, color.CyanString(line))
+		default:
+			fmt.Fprint(w, line)
+		}
+	}
+}
+
+// Normalizer returns the name which an item of the field stands for on the platform, e.g. the tag of an alias,
+// so that the names which the platform resolves to the same one are not reported as changes
+type Normalizer func(field, item string) string
+
+// Compare compares the platform meta fields and the content of the local mark with the remote one,
+// remote is expected to be built from the platform like juejin.NewMark.
+// The items of the fields are compared after they are normalized if normalizer is not nil.
+func Compare(platform string, local, remote *markdown.Mark, fields []string, normalizer Normalizer) (*Result, error) {
+	result := new(Result)
+	for _, name := range fields {
+		l := Value(local, platform, name)
+		r := Value(remote, platform, name)
+		if l != r && (normalizer == nil || normalizeValue(l, name, normalizer) != normalizeValue(r, name, normalizer)) {
+			result.Fields = append(result.Fields, &Field{Name: name, Remote: r, Local: l})
+		}
+	}
+
+	body, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(normalize(remote.Content)),
+		B:        difflib.SplitLines(normalize(local.Content)),
+		FromFile: "remote",
+		ToFile:   "local",
+		Context:  3,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.Body = body
+	return result, nil
+}
+
+// Value returns the meta value of the platform as a string, falls back to the common meta if it is not set.
+// Lists are joined with commas.
+func Value(mark *markdown.Mark, platform, name string) string {
+	v := mark.Meta.Get(platform + "." + name)
+	if isEmpty(v) {
+		v = mark.Meta.Get(name)
+	}
+	if isEmpty(v) && name == "cover_image" {
+		// The first one of the common cover images is used as the cover image
+		if images := mark.Meta.GetStringSlice("cover_images"); len(images) > 0 {
+			v = images[0]
+		}
+	}
+	return format(v)
+}
+
+// Body returns the content of mark with the prefix and suffix content which are published along,
+// the common ones are only published by the platforms which fall back to them as markdown.FallbackKeys
+func Body(mark *markdown.Mark, platform string) string {
+	content := mark.Content
+	if prefix := publishedValue(mark, platform, "prefix_content"); prefix != "" {
+		content = fmt.Sprintf("%s\n\n%s", prefix, content)
+	}
+	if suffix := publishedValue(mark, platform, "suffix_content"); suffix != "" {
+		content = fmt.Sprintf("%s\n\n%s", content, suffix)
+	}
+	return content
+}
+
+// publishedValue returns the platform meta value, or the common one if the platform falls back to it
+func publishedValue(mark *markdown.Mark, platform, name string) string {
+	if v := format(mark.Meta.Get(platform + "." + name)); v != "" {
+		return v
+	}
+	for _, k := range markdown.FallbackKeys[platform] {
+		if k == name {
+			return format(mark.Meta.Get(name))
+		}
+	}
+	return ""
+}
+
+// normalizeValue normalizes every item of the value formatted by Value
+func normalizeValue(v, field string, normalizer Normalizer) string {
+	if v == "" {
+		return v
+	}
+	items := strings.Split(v, ", ")
+	for i, item := range items {
+		items[i] = normalizer(field, item)
+	}
+	return strings.Join(items, ", ")
+}
+
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(t, ", ")
+	case []interface{}:
+		items := make([]string, 0, len(t))
+		for _, i := range t {
+			items = append(items, fmt.Sprint(i))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	return format(v) == ""
+}
+
+func normalize(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+func newMark(t *testing.T, meta, content string) *markdown.Mark {
+	var m markdown.Meta
+	assert.Nil(t, yaml.Unmarshal([]byte(meta), &m))
+	return &markdown.Mark{Meta: m, Content: content}
+}
+
+func TestCompare(t *testing.T) {
+	local := newMark(t, `
+title: New title
+cover_images:
+  - https://cdn.com/a.png
+juejin:
+  tags: [Go, Linux]
+  category: 后端
+  suffix_content: bye
+`, "# Title\n\nhello\n")
+	remote := newMark(t, `
+title: Old title
+juejin:
+  tags: [Go, Linux]
+  category: 后端
+  cover_image: https://cdn.com/a.png
+`, "# Title\n\nhello\n\n\nbye")
+
+	local.Content = Body(local, "juejin")
+	result, err := Compare("juejin", local, remote, []string{"title", "tags", "category", "cover_image"}, nil)
+	assert.Nil(t, err)
+	assert.True(t, result.HasDiff())
+	assert.Equal(t, "", result.Body)
+	assert.Equal(t, []*Field{{Name: "title", Remote: "Old title", Local: "New title"}}, result.Fields)
+
+	local.Content = "# Title\n\nworld\n"
+	result, err = Compare("juejin", local, remote, nil, nil)
+	assert.Nil(t, err)
+	assert.Contains(t, result.Body, "-hello\n-\n-\n-bye\n+world\n")
+
+	color.NoColor = true
+	buf := new(bytes.Buffer)
+	result.Print(buf)
+	assert.Contains(t, buf.String(), "--- remote\n+++ local\n")
+}
+
+func TestCompareSame(t *testing.T) {
+	mark := newMark(t, "title: Title\n", "hello\n")
+	result, err := Compare("juejin", mark, mark, []string{"title"}, nil)
+	assert.Nil(t, err)
+	assert.False(t, result.HasDiff())
+}
+
+func TestCompareNormalize(t *testing.T) {
+	local := newMark(t, `
+juejin:
+  tags: [golang, linux]
+  category: 后端
+`, "hello\n")
+	remote := newMark(t, `
+juejin:
+  tags: [Go, Linux]
+  category: 后端
+`, "hello\n")
+	aliases := map[string]string{"golang": "Go", "linux": "Linux"}
+	normalize := func(field, item string) string {
+		if name, ok := aliases[item]; ok && field == "tags" {
+			return name
+		}
+		return item
+	}
+
+	result, err := Compare("juejin", local, remote, []string{"tags", "category"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result.Fields))
+
+	result, err = Compare("juejin", local, remote, []string{"tags", "category"}, normalize)
+	assert.Nil(t, err)
+	assert.False(t, result.HasDiff())
+}
+
+func TestBody(t *testing.T) {
+	mark := newMark(t, `
+prefix_content: hi
+suffix_content: bye
+oschina:
+  suffix_content: see you
+`, "hello")
+	// juejin never publishes the common prefix and suffix content
+	assert.Equal(t, "hello", Body(mark, "juejin"))
+	assert.Equal(t, "hello\n\nsee you", Body(mark, "oschina"))
+	assert.Equal(t, "hi\n\nhello\n\nbye", Body(mark, "csdn"))
+}
@@ -0,0 +1,147 @@
+package image
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+const cacheFileName = "images.json"
+
+// Entry is an uploaded image
+type Entry struct {
+	Host       string    `json:"host"`
+	Hash       string    `json:"hash"`
+	File       string    `json:"file"`
+	URL        string    `json:"url"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// Cache maps the sha256 of local images to the uploaded urls per image host
+type Cache struct {
+	file    string
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// DefaultCacheFile returns the path of the image cache in the config dir
+func DefaultCacheFile() string {
+	return filepath.Join(config.GetConfigDir(), cacheFileName)
+}
+
+// LoadCache loads the cache from file, an empty cache is returned if file does not exist
+func LoadCache(file string) (*Cache, error) {
+	c := &Cache{
+		file:    file,
+		entries: make(map[string]*Entry),
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, errors.Trace(err)
+	}
+
+	var entries []*Entry
+	if err = json.Unmarshal(b, &entries); err != nil {
+		return nil, errors.Errorf("invalid cache data: %s", file)
+	}
+	for _, e := range entries {
+		c.entries[cacheKey(e.Host, e.Hash)] = e
+	}
+	return c, nil
+}
+
+// Get returns the cached entry of the image, nil is returned if it is not uploaded to host
+func (c *Cache) Get(host, hash string) *Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[cacheKey(host, hash)]
+}
+
+// Put adds an uploaded image to the cache and saves the cache file
+func (c *Cache) Put(host, hash, file, url string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheKey(host, hash)] = &Entry{
+		Host:       host,
+		Hash:       hash,
+		File:       file,
+		URL:        url,
+		CreateTime: time.Now(),
+	}
+	return errors.Trace(c.save())
+}
+
+func (c *Cache) save() error {
+	b, err := json.MarshalIndent(c.sortedEntries(), "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The cache is replaced at once, so that it is never left half written
+	return errors.Trace(utils.WriteFileAtomic(c.file, b, 0644))
+}
+
+func (c *Cache) sortedEntries() []*Entry {
+	entries := make([]*Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Host != entries[j].Host {
+			return entries[i].Host < entries[j].Host
+		}
+		return entries[i].CreateTime.Before(entries[j].CreateTime)
+	})
+	return entries
+}
+
+func cacheKey(host, hash string) string {
+	return host + ":" + hash
+}
+
+// Entries returns all the cached images sorted by host and create time
+func (c *Cache) Entries() []*Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sortedEntries()
+}
+
+// Remove deletes entries from the cache and saves the cache file
+func (c *Cache) Remove(entries ...*Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range entries {
+		delete(c.entries, cacheKey(e.Host, e.Hash))
+	}
+	return errors.Trace(c.save())
+}
+
+// Verify checks whether the url of the entry is still reachable
+func Verify(client *http.Client, e *Entry) error {
+	resp, err := client.Head(e.URL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+		// Some image stores do not support HEAD requests
+		resp.Body.Close()
+		resp, err = client.Get(e.URL)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package image
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "articli-image")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cacheFile := filepath.Join(dir, cacheFileName)
+	cache, err := LoadCache(cacheFile)
+	assert.Nil(t, err)
+	assert.Empty(t, cache.Entries())
+
+	assert.Nil(t, cache.Put("juejin", "a", "/tmp/a.png", "https://cdn.com/a.png"))
+	assert.Nil(t, cache.Put("csdn", "a", "/tmp/a.png", "https://csdn.com/a.png"))
+	assert.Nil(t, cache.Put("juejin", "b", "/tmp/b.png", "https://cdn.com/b.png"))
+
+	cache, err = LoadCache(cacheFile)
+	assert.Nil(t, err)
+	entries := cache.Entries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "csdn", entries[0].Host)
+	assert.Equal(t, "https://cdn.com/a.png", cache.Get("juejin", "a").URL)
+
+	assert.Nil(t, cache.Remove(cache.Get("juejin", "a")))
+	cache, err = LoadCache(cacheFile)
+	assert.Nil(t, err)
+	assert.Nil(t, cache.Get("juejin", "a"))
+	assert.Len(t, cache.Entries(), 2)
+}
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.png":
+			w.WriteHeader(http.StatusOK)
+		case "/head.png":
+			// HEAD is not allowed, GET is used instead
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	assert.Nil(t, Verify(client, &Entry{URL: server.URL + "/ok.png"}))
+	assert.Nil(t, Verify(client, &Entry{URL: server.URL + "/head.png"}))
+	assert.NotNil(t, Verify(client, &Entry{URL: server.URL + "/missing.png"}))
+}
@@ -0,0 +1,239 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	githubsdk "github.com/k8scat/articli/pkg/platform/github"
+	gitlabsdk "github.com/k8scat/articli/pkg/platform/gitlab"
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+const (
+	HostTypePlatform = "platform"
+	HostTypeGithub   = "github"
+	HostTypeGitlab   = "gitlab"
+)
+
+// PlatformUploader uploads images to the image store of an article platform
+type PlatformUploader struct {
+	Name     string
+	Uploader platform.ImageUploader
+}
+
+func (u *PlatformUploader) Host() string {
+	return u.Name
+}
+
+func (u *PlatformUploader) Upload(ctx context.Context, path string) (string, error) {
+	imageURL, err := u.Uploader.UploadImage(ctx, path)
+	return imageURL, errors.Trace(err)
+}
+
+// GithubUploader uploads images to a GitHub repository
+type GithubUploader struct {
+	Client *githubsdk.Client
+	Owner  string
+	Repo   string
+	Branch string
+	Dir    string
+}
+
+func (u *GithubUploader) Host() string {
+	return fmt.Sprintf("github:%s/%s", u.Owner, u.Repo)
+}
+
+// Upload uploads the image named by its hash, the image uploaded before is not uploaded again
+func (u *GithubUploader) Upload(ctx context.Context, p string) (string, error) {
+	filePath, err := repoFilePath(u.Dir, p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req := &githubsdk.UploadFileRequest{
+		Path:    p,
+		Message: uploadMessage(),
+		Branch:  u.Branch,
+	}
+	result, err := u.Client.UploadFileContext(ctx, u.Owner, u.Repo, filePath, req)
+	if errors.IsAlreadyExists(err) {
+		var refs []string
+		if u.Branch != "" {
+			refs = append(refs, u.Branch)
+		}
+		f, _, err := u.Client.GetFileContext(ctx, u.Owner, u.Repo, filePath, refs...)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if f == nil || f.DownloadURL == "" {
+			return "", errors.NotFoundf("download url of %s", filePath)
+		}
+		return f.DownloadURL, nil
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.Content.DownloadURL, nil
+}
+
+// GitlabUploader uploads images to a GitLab project
+type GitlabUploader struct {
+	Client  *gitlabsdk.Client
+	Project *gitlabsdk.Project
+	Branch  string
+	Dir     string
+}
+
+func (u *GitlabUploader) Host() string {
+	return fmt.Sprintf("gitlab:%s", u.Project.PathWithNamespace)
+}
+
+// Upload uploads the image named by its hash, the image uploaded before is not uploaded again
+func (u *GitlabUploader) Upload(ctx context.Context, p string) (string, error) {
+	filePath, err := repoFilePath(u.Dir, p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	branch := u.Branch
+	if branch == "" {
+		branch = u.Project.DefaultBranch
+	}
+	projectID := u.Project.PathWithNamespace
+	data := &gitlabsdk.CreateFileData{
+		ProjectID:     projectID,
+		FilePath:      filePath,
+		Branch:        branch,
+		Encoding:      gitlabsdk.ContentEncodingBase64,
+		CommitMessage: uploadMessage(),
+		Content:       utils.Base64Encode(b),
+	}
+	result, err := u.Client.CreateFileContext(ctx, data)
+	if errors.IsAlreadyExists(err) {
+		return u.Client.BuildFileDownloadURL(projectID, filePath, branch, u.Project.IsPrivate()), nil
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return u.Client.BuildFileDownloadURL(projectID, result.FilePath, result.Branch, u.Project.IsPrivate()), nil
+}
+
+// repoFilePath names the file by its hash, so that the same image is stored only once
+func repoFilePath(dir, p string) (string, error) {
+	hash, err := HashFile(p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	name := hash[:16] + strings.ToLower(filepath.Ext(p))
+	return strings.TrimPrefix(path.Join(dir, name), "/"), nil
+}
+
+func uploadMessage() string {
+	return fmt.Sprintf("Uploaded by [Articli](https://github.com/k8scat/Articli) at %s", time.Now().Format("2006-01-02 15:04:05"))
+}
+
+// MarkRewriter rewrites the local images in markdown files with the image host in config
+type MarkRewriter struct {
+	Config *config.Config
+	Cache  *Cache
+
+	mu      sync.Mutex
+	repo    Uploader
+	repoErr error
+}
+
+// RewriteMark returns a copy of mark whose local images are uploaded for publisher p,
+// mark itself is returned if there is no local image.
+func (r *MarkRewriter) RewriteMark(ctx context.Context, p platform.Publisher, mark *markdown.Mark) (*markdown.Mark, error) {
+	if len(FindLocalImages(mark.Content)) == 0 {
+		return mark, nil
+	}
+	uploader, err := r.uploader(ctx, p)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rw := &Rewriter{
+		Uploader: uploader,
+		Cache:    r.Cache,
+	}
+	content, err := rw.Rewrite(ctx, mark.Content, filepath.Dir(mark.File))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m := *mark
+	m.Content = content
+	return &m, nil
+}
+
+func (r *MarkRewriter) uploader(ctx context.Context, p platform.Publisher) (Uploader, error) {
+	host := r.Config.ImageHost
+	switch host.Type {
+	case "", HostTypePlatform:
+		u, ok := p.(platform.ImageUploader)
+		if !ok {
+			return nil, errors.Errorf("%s does not host images, please set image_host in config", p.Name())
+		}
+		return &PlatformUploader{Name: p.Name(), Uploader: u}, nil
+	case HostTypeGithub, HostTypeGitlab:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.repo == nil && r.repoErr == nil {
+			r.repo, r.repoErr = newRepoUploader(ctx, r.Config)
+		}
+		return r.repo, r.repoErr
+	default:
+		return nil, errors.NotSupportedf("image host type %q", host.Type)
+	}
+}
+
+func newRepoUploader(ctx context.Context, cfg *config.Config) (Uploader, error) {
+	host := cfg.ImageHost
+	if host.Repo == "" {
+		return nil, errors.New("image_host.repo is required")
+	}
+	if host.Type == HostTypeGithub {
+		parts := strings.SplitN(host.Repo, "/", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid github repo %q, owner/repo is required", host.Repo)
+		}
+		client, err := githubsdk.NewClient(cfg.Github().Token, githubsdk.WithOptions(cfg.Github().Options()))
+		if err != nil {
+			return nil, errors.Annotate(err, "please login github first")
+		}
+		return &GithubUploader{
+			Client: client,
+			Owner:  parts[0],
+			Repo:   parts[1],
+			Branch: host.Branch,
+			Dir:    host.Dir,
+		}, nil
+	}
+
+	client, err := gitlabsdk.NewClient(cfg.Gitlab().BaseURL, cfg.Gitlab().Token, gitlabsdk.WithOptions(cfg.Gitlab().Options()))
+	if err != nil {
+		return nil, errors.Annotate(err, "please login gitlab first")
+	}
+	project, err := client.GetProjectContext(ctx, host.Repo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &GitlabUploader{
+		Client:  client,
+		Project: project,
+		Branch:  host.Branch,
+		Dir:     host.Dir,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform/fake"
+	githubsdk "github.com/k8scat/articli/pkg/platform/github"
+	gitlabsdk "github.com/k8scat/articli/pkg/platform/gitlab"
+	"github.com/k8scat/articli/pkg/transport"
+)
+
+const testImage = "../../images/go.png"
+
+func TestGithubUploader(t *testing.T) {
+	server := fake.NewGitHub()
+	defer server.Close()
+	client, err := githubsdk.NewClient(fake.Token, githubsdk.WithBaseAPI(server.URL), githubsdk.WithHTTP(transport.WithRateLimit(-1)))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	u := &GithubUploader{Client: client, Owner: "k8scat", Repo: "images", Dir: "articli"}
+	imageURL, err := u.Upload(context.Background(), testImage)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, imageURL)
+
+	// The image uploaded before, e.g. with a lost cache, is not an error
+	again, err := u.Upload(context.Background(), testImage)
+	assert.Nil(t, err)
+	assert.Equal(t, imageURL, again)
+}
+
+func TestGitlabUploader(t *testing.T) {
+	server := fake.NewGitLab()
+	defer server.Close()
+	server.AddProject("kube/images", "public")
+	client, err := gitlabsdk.NewClient(server.URL, fake.Token, gitlabsdk.WithHTTP(transport.WithRateLimit(-1)))
+	if !assert.Nil(t, err) {
+		return
+	}
+	project, err := client.GetProject("kube/images")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	u := &GitlabUploader{Client: client, Project: project, Dir: "articli"}
+	imageURL, err := u.Upload(context.Background(), testImage)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, imageURL)
+
+	again, err := u.Upload(context.Background(), testImage)
+	assert.Nil(t, err)
+	assert.Equal(t, imageURL, again)
+}
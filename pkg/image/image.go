@@ -0,0 +1,168 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+// Uploader uploads local images to an image host
+type Uploader interface {
+	// Host identifies the image host, uploaded urls are cached per host
+	Host() string
+	// Upload uploads a local image and returns the url of it
+	Upload(ctx context.Context, path string) (string, error)
+}
+
+// FindLocalImages returns the destinations of the local images referenced in the markdown content,
+// remote images and data uris are ignored.
+func FindLocalImages(content string) []string {
+	p := parser.NewWithExtensions(parser.CommonExtensions)
+	doc := p.Parse([]byte(content))
+
+	seen := make(map[string]bool)
+	dests := make([]string, 0)
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		img, ok := node.(*ast.Image)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		dest := string(img.Destination)
+		if isLocal(dest) && !seen[dest] {
+			seen[dest] = true
+			dests = append(dests, dest)
+		}
+		return ast.GoToNext
+	})
+	return dests
+}
+
+// ResolvePath returns the file path of an image destination relative to baseDir
+func ResolvePath(baseDir, dest string) string {
+	if s, err := url.PathUnescape(dest); err == nil {
+		dest = s
+	}
+	dest = strings.TrimPrefix(dest, "file://")
+	if filepath.IsAbs(dest) {
+		return dest
+	}
+	return filepath.Join(baseDir, dest)
+}
+
+// ReplaceImage replaces the image destination dest with newURL in inline images and link references
+func ReplaceImage(content, dest, newURL string) string {
+	q := regexp.QuoteMeta(dest)
+	inline := regexp.MustCompile(`(\]\(\s*<?)` + q + `(>?[\s)])`)
+	content = inline.ReplaceAllString(content, "${1}"+escapeReplacement(newURL)+"${2}")
+	reference := regexp.MustCompile(`(?m)(^ {0,3}\[[^\]]+\]:\s*<?)` + q + `(>?(\s|$))`)
+	content = reference.ReplaceAllString(content, "${1}"+escapeReplacement(newURL)+"${2}")
+	return content
+}
+
+// HashFile returns the sha256 of the file content
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashImages returns the sha256 of the local images referenced in the content of mark in order,
+// relative paths are resolved against the dir of the file.
+func HashImages(mark *markdown.Mark) ([]string, error) {
+	dests := FindLocalImages(mark.Content)
+	hashes := make([]string, 0, len(dests))
+	for _, dest := range dests {
+		hash, err := HashFile(ResolvePath(filepath.Dir(mark.File), dest))
+		if err != nil {
+			return nil, errors.Annotatef(err, "hash image %s", dest)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// Rewriter uploads the local images referenced in markdown content and rewrites the links
+type Rewriter struct {
+	Uploader Uploader
+	// Cache is optional, uploaded urls are reused when it is set
+	Cache *Cache
+}
+
+// Rewrite returns content with every local image replaced by the uploaded url,
+// relative paths are resolved against baseDir.
+func (r *Rewriter) Rewrite(ctx context.Context, content, baseDir string) (string, error) {
+	for _, dest := range FindLocalImages(content) {
+		path := ResolvePath(baseDir, dest)
+		imageURL, err := Upload(ctx, r.Uploader, r.Cache, path)
+		if err != nil {
+			return "", errors.Annotatef(err, "upload image %s", dest)
+		}
+		content = ReplaceImage(content, dest, imageURL)
+	}
+	return content, nil
+}
+
+// Upload uploads a local image with u, the url is reused if the image is in cache already.
+// cache is optional, and remote images are never cached.
+func Upload(ctx context.Context, u Uploader, cache *Cache, path string) (string, error) {
+	if cache == nil || utils.IsValidURL(path) {
+		imageURL, err := u.Upload(ctx, path)
+		return imageURL, errors.Trace(err)
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	host := u.Host()
+	if entry := cache.Get(host, hash); entry != nil {
+		return entry.URL, nil
+	}
+	imageURL, err := u.Upload(ctx, path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	err = cache.Put(host, hash, path, imageURL)
+	return imageURL, errors.Trace(err)
+}
+
+func isLocal(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") {
+		return false
+	}
+	if strings.HasPrefix(strings.ToLower(dest), "data:") {
+		return false
+	}
+	if strings.HasPrefix(dest, "//") {
+		return false
+	}
+	return !utils.IsValidURL(dest)
+}
+
+func escapeReplacement(s string) string {
+	return strings.Replace(s, "$", "$$", -1)
+}
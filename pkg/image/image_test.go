@@ -0,0 +1,111 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+const content = `# Title
+
+![local](./img/a.png)
+![remote](https://example.com/b.png)
+![data](data:image/png;base64,AAAA)
+![with title](img/c%20d.png "title")
+![ref][logo]
+
+[logo]: ../logo.png
+`
+
+func TestFindLocalImages(t *testing.T) {
+	images := FindLocalImages(content)
+	assert.Equal(t, []string{"./img/a.png", "img/c%20d.png", "../logo.png"}, images)
+}
+
+func TestResolvePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("posts", "img", "c d.png"), ResolvePath("posts", "img/c%20d.png"))
+	assert.Equal(t, "/tmp/a.png", ResolvePath("posts", "/tmp/a.png"))
+}
+
+func TestReplaceImage(t *testing.T) {
+	s := ReplaceImage(content, "./img/a.png", "https://cdn.com/a.png")
+	assert.Contains(t, s, "![local](https://cdn.com/a.png)")
+	s = ReplaceImage(s, "img/c%20d.png", "https://cdn.com/c.png")
+	assert.Contains(t, s, `![with title](https://cdn.com/c.png "title")`)
+	s = ReplaceImage(s, "../logo.png", "https://cdn.com/logo.png")
+	assert.Contains(t, s, "[logo]: https://cdn.com/logo.png")
+	assert.Contains(t, s, "![remote](https://example.com/b.png)")
+}
+
+type fakeUploader struct {
+	count int
+}
+
+func (u *fakeUploader) Host() string {
+	return "fake"
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, path string) (string, error) {
+	u.count++
+	return fmt.Sprintf("https://cdn.com/%s", filepath.Base(path)), nil
+}
+
+func TestRewriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "articli-image")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "a.png"), []byte("a"), 0644)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "b.png"), []byte("a"), 0644)
+	assert.Nil(t, err)
+
+	cacheFile := filepath.Join(dir, cacheFileName)
+	cache, err := LoadCache(cacheFile)
+	assert.Nil(t, err)
+
+	uploader := new(fakeUploader)
+	rw := &Rewriter{Uploader: uploader, Cache: cache}
+	s, err := rw.Rewrite(context.Background(), "![a](a.png)\n![b](./b.png)\n", dir)
+	assert.Nil(t, err)
+	// b.png has the same content as a.png, so it is not uploaded again
+	assert.Equal(t, "![a](https://cdn.com/a.png)\n![b](https://cdn.com/a.png)\n", s)
+	assert.Equal(t, 1, uploader.count)
+
+	cache, err = LoadCache(cacheFile)
+	assert.Nil(t, err)
+	rw.Cache = cache
+	_, err = rw.Rewrite(context.Background(), "![a](a.png)\n", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, uploader.count)
+
+	_, err = rw.Rewrite(context.Background(), "![missing](missing.png)\n", dir)
+	assert.NotNil(t, err)
+}
+
+func TestHashImages(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "a.png"), []byte("a"), 0644)
+	assert.Nil(t, err)
+	mark := &markdown.Mark{File: filepath.Join(dir, "hello.md"), Content: "![a](a.png)\n![remote](https://example.com/b.png)\n"}
+	hashes, err := HashImages(mark)
+	assert.Nil(t, err)
+	assert.Len(t, hashes, 1)
+
+	// An edit of only the image changes its hash
+	err = ioutil.WriteFile(filepath.Join(dir, "a.png"), []byte("b"), 0644)
+	assert.Nil(t, err)
+	again, err := HashImages(mark)
+	assert.Nil(t, err)
+	assert.NotEqual(t, hashes, again)
+
+	mark.Content = "![missing](missing.png)\n"
+	_, err = HashImages(mark)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,234 @@
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+const journalFileName = "journal.json"
+
+type Status string
+
+const (
+	StatusPending Status = "pending" // the request is sent, the ids are unknown
+	StatusSaved   Status = "saved"   // the ids are returned, waiting for writing back
+)
+
+// Entry is a save of an article or a draft which is not written back to the markdown file yet
+type Entry struct {
+	ID         string            `json:"id"`
+	File       string            `json:"file"`
+	Platform   string            `json:"platform"`
+	SaveType   platform.SaveType `json:"save_type"`
+	Title      string            `json:"title"`
+	Status     Status            `json:"status"`
+	ArticleID  string            `json:"article_id,omitempty"`
+	DraftID    string            `json:"draft_id,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	IsCreate   bool              `json:"is_create,omitempty"`
+	PID        int               `json:"pid"`
+	CreateTime time.Time         `json:"create_time"`
+	UpdateTime time.Time         `json:"update_time"`
+}
+
+// Result returns the result of the save returned by the platform
+func (e *Entry) Result() *platform.Result {
+	return &platform.Result{
+		Platform:  e.Platform,
+		SaveType:  e.SaveType,
+		ArticleID: e.ArticleID,
+		DraftID:   e.DraftID,
+		URL:       e.URL,
+		IsCreate:  e.IsCreate,
+	}
+}
+
+// Journal is the write-ahead log of the saves persisted in a json file, an entry is added before the request is sent,
+// updated with the ids returned and removed after the ids are written back, so that the ids are never lost
+// if the process is killed or the markdown file can not be written.
+// The file is locked and reloaded before every change so that the journal can be shared by processes.
+type Journal struct {
+	mu      sync.Mutex
+	file    string
+	entries []*Entry
+}
+
+// DefaultJournalFile returns the path of the journal in the config dir
+func DefaultJournalFile() string {
+	return filepath.Join(config.GetConfigDir(), journalFileName)
+}
+
+// LoadJournal loads the journal from file, an empty journal is returned if file does not exist
+func LoadJournal(file string) (*Journal, error) {
+	j := &Journal{file: file}
+	if err := j.load(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return j, nil
+}
+
+// Entries returns the entries sorted by create time
+func (j *Journal) Entries() []*Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries := make([]*Entry, len(j.entries))
+	copy(entries, j.entries)
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].CreateTime.Before(entries[k].CreateTime)
+	})
+	return entries
+}
+
+// Begin adds a pending entry before saving file to the platform
+func (j *Journal) Begin(file, name string, saveType platform.SaveType, title string) (*Entry, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := time.Now()
+	entry := &Entry{
+		ID:         newID(),
+		File:       abs,
+		Platform:   name,
+		SaveType:   saveType,
+		Title:      title,
+		Status:     StatusPending,
+		PID:        os.Getpid(),
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	err = j.modify(func() error {
+		j.entries = append(j.entries, entry)
+		return nil
+	})
+	return entry, errors.Trace(err)
+}
+
+// Record saves the ids returned by the platform in the entry
+func (j *Journal) Record(id string, result *platform.Result) error {
+	return j.modify(func() error {
+		for _, e := range j.entries {
+			if e.ID == id {
+				e.Status = StatusSaved
+				e.ArticleID = result.ArticleID
+				e.DraftID = result.DraftID
+				e.URL = result.URL
+				e.IsCreate = result.IsCreate
+				e.UpdateTime = time.Now()
+				return nil
+			}
+		}
+		return errors.NotFoundf("journal entry %s", id)
+	})
+}
+
+// Finish removes the entries after their ids are written back
+func (j *Journal) Finish(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return j.modify(func() error {
+		finished := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			finished[id] = true
+		}
+		entries := make([]*Entry, 0, len(j.entries))
+		for _, e := range j.entries {
+			if !finished[e.ID] {
+				entries = append(entries, e)
+			}
+		}
+		j.entries = entries
+		return nil
+	})
+}
+
+// Save saves file to the named platform by save with a journaled entry, the entry must be finished by Finish
+// after the ids are written back. The entry is kept if save fails, because the article may be saved
+// though the response is lost, which is checked by Recover. It is finished at once if the error is marked
+// by platform.NotSent, and no entry is returned then.
+func (j *Journal) Save(file, name string, saveType platform.SaveType, title string, save func() (*platform.Result, error)) (*platform.Result, *Entry, error) {
+	entry, err := j.Begin(file, name, saveType, title)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "write journal failed")
+	}
+	result, err := save()
+	if platform.IsNotSent(err) {
+		if finishErr := j.Finish(entry.ID); finishErr != nil {
+			return nil, entry, errors.Trace(err)
+		}
+		return nil, nil, errors.Trace(err)
+	}
+	if err != nil {
+		return nil, entry, errors.Trace(err)
+	}
+	if err = j.Record(entry.ID, result); err != nil {
+		return nil, entry, errors.Annotate(err, "write journal failed")
+	}
+	return result, entry, nil
+}
+
+func (j *Journal) modify(f func() error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	unlock, err := utils.LockFile(j.file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer unlock()
+	if err := j.load(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := f(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(j.save())
+}
+
+func (j *Journal) load() error {
+	b, err := ioutil.ReadFile(j.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			j.entries = nil
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	var entries []*Entry
+	if err = json.Unmarshal(b, &entries); err != nil {
+		return errors.Errorf("invalid journal data: %s", j.file)
+	}
+	j.entries = entries
+	return nil
+}
+
+// save writes the journal atomically, so that the journal is never half written
+func (j *Journal) save() error {
+	b, err := json.MarshalIndent(j.entries, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.WriteFileAtomic(j.file, b, 0644))
+}
+
+func newID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b)
+}
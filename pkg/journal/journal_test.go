@@ -0,0 +1,224 @@
+package journal
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+type fakePublisher struct {
+	articles []*platform.Article
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	return &platform.Result{Platform: "fake", SaveType: platform.SaveTypeArticle, ArticleID: "1", IsCreate: true}, nil
+}
+
+func (p *fakePublisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	return &platform.Result{Platform: "fake", SaveType: platform.SaveTypeDraft, DraftID: "2", IsCreate: true}, nil
+}
+
+func (p *fakePublisher) DeleteArticle(ctx context.Context, id string) error { return nil }
+
+func (p *fakePublisher) WriteBack(mark *markdown.Mark, result *platform.Result) error {
+	meta, _ := mark.Meta.Get("fake").(markdown.Meta)
+	if result.ArticleID != "" {
+		meta = meta.Set("article_id", result.ArticleID)
+	}
+	if result.DraftID != "" {
+		meta = meta.Set("draft_id", result.DraftID)
+	}
+	mark.Meta = mark.Meta.Set("fake", meta)
+	return nil
+}
+
+func (p *fakePublisher) FindArticles(ctx context.Context, keyword string) ([]*platform.Article, error) {
+	return p.articles, nil
+}
+
+func writeArticle(t *testing.T, dir, name string) string {
+	file := filepath.Join(dir, name)
+	content := "---\ntitle: " + name + "\nfake:\n  tags: [Go]\n---\n# Hello\n"
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	j, err := LoadJournal(filepath.Join(dir, "journal.json"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 0, len(j.Entries()))
+
+	p := &fakePublisher{}
+	file := writeArticle(t, dir, "a.md")
+	result, entry, err := j.Save(file, "fake", platform.SaveTypeArticle, "a.md", func() (*platform.Result, error) {
+		return p.Publish(context.Background(), nil)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "1", result.ArticleID)
+
+	// The journal is shared by processes
+	other, err := LoadJournal(filepath.Join(dir, "journal.json"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	entries := other.Entries()
+	if assert.Equal(t, 1, len(entries)) {
+		assert.Equal(t, entry.ID, entries[0].ID)
+		assert.Equal(t, StatusSaved, entries[0].Status)
+		assert.Equal(t, "1", entries[0].ArticleID)
+		assert.Equal(t, os.Getpid(), entries[0].PID)
+	}
+
+	// The entry of a failed save is kept
+	_, failed, err := j.Save(file, "fake", platform.SaveTypeArticle, "a.md", func() (*platform.Result, error) {
+		return nil, errors.New("timeout")
+	})
+	assert.NotNil(t, err)
+	if assert.NotNil(t, failed) {
+		assert.Equal(t, StatusPending, failed.Status)
+	}
+
+	assert.Nil(t, j.Finish(entry.ID, failed.ID))
+	assert.Equal(t, 0, len(j.Entries()))
+
+	// The entry of a save failed before the request is sent is finished at once
+	_, notSent, err := j.Save(file, "fake", platform.SaveTypeArticle, "a.md", func() (*platform.Result, error) {
+		return nil, errors.Trace(platform.NotSent(errors.New("title is required")))
+	})
+	assert.True(t, platform.IsNotSent(err))
+	assert.Nil(t, notSent)
+	assert.Equal(t, 0, len(j.Entries()))
+}
+
+func TestConcurrentJournals(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal.json")
+	// The journals of two processes add the entries at the same time
+	journals := make([]*Journal, 2)
+	for i := range journals {
+		j, err := LoadJournal(file)
+		if !assert.Nil(t, err) {
+			return
+		}
+		journals[i] = j
+	}
+	var wg sync.WaitGroup
+	for _, j := range journals {
+		wg.Add(1)
+		go func(j *Journal) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_, err := j.Begin("a.md", "fake", platform.SaveTypeArticle, "a")
+				assert.Nil(t, err)
+			}
+		}(j)
+	}
+	wg.Wait()
+
+	j, err := LoadJournal(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, len(j.Entries()))
+	files, _ := filepath.Glob(file + "*")
+	assert.Equal(t, []string{file}, files)
+}
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	j, err := LoadJournal(filepath.Join(dir, "journal.json"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	p := &fakePublisher{articles: []*platform.Article{
+		{ID: "3", Title: "c.md", CreateTime: time.Now()},
+		{ID: "4", Title: "f.md", CreateTime: time.Now().Add(-time.Hour)},
+	}}
+	newPublisher := func(name string, mark *markdown.Mark) (platform.Publisher, error) {
+		return p, nil
+	}
+
+	// The ids returned but not written back
+	saved, err := j.Begin(writeArticle(t, dir, "a.md"), "fake", platform.SaveTypeArticle, "a.md")
+	assert.Nil(t, err)
+	assert.Nil(t, j.Record(saved.ID, &platform.Result{Platform: "fake", SaveType: platform.SaveTypeArticle, ArticleID: "1", IsCreate: true}))
+	// The save failed before the article is created
+	failed, err := j.Begin(writeArticle(t, dir, "b.md"), "fake", platform.SaveTypeArticle, "b.md")
+	assert.Nil(t, err)
+	// The article is created but the response is lost
+	lost, err := j.Begin(writeArticle(t, dir, "c.md"), "fake", platform.SaveTypeArticle, "c.md")
+	assert.Nil(t, err)
+	// The markdown file is deleted
+	deleted, err := j.Begin(filepath.Join(dir, "d.md"), "fake", platform.SaveTypeArticle, "d.md")
+	assert.Nil(t, err)
+	// The article with the same title is created before, e.g. republished
+	republished, err := j.Begin(writeArticle(t, dir, "f.md"), "fake", platform.SaveTypeArticle, "f.md")
+	assert.Nil(t, err)
+	// The entry of a running process
+	running, err := j.Begin(writeArticle(t, dir, "e.md"), "fake", platform.SaveTypeArticle, "e.md")
+	assert.Nil(t, err)
+	assert.Nil(t, j.modify(func() error {
+		for _, e := range j.entries {
+			if e.ID == running.ID {
+				e.PID = os.Getppid()
+			}
+		}
+		return nil
+	}))
+
+	recoveries, err := j.Recover(context.Background(), newPublisher)
+	assert.Nil(t, err)
+	actions := make(map[string]string)
+	for _, r := range recoveries {
+		actions[r.Entry.ID] = r.Action
+	}
+	assert.Equal(t, map[string]string{
+		saved.ID:       ActionRecover,
+		failed.ID:      ActionFinish,
+		lost.ID:        ActionRecover,
+		deleted.ID:     ActionFail,
+		republished.ID: ActionFail,
+	}, actions)
+
+	mark, err := markdown.Parse(filepath.Join(dir, "a.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1", mark.Meta.GetString("fake.article_id"))
+	mark, err = markdown.Parse(filepath.Join(dir, "b.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "", mark.Meta.GetString("fake.article_id"))
+	mark, err = markdown.Parse(filepath.Join(dir, "c.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "3", mark.Meta.GetString("fake.article_id"))
+	mark, err = markdown.Parse(filepath.Join(dir, "f.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "", mark.Meta.GetString("fake.article_id"))
+
+	entries := j.Entries()
+	if assert.Equal(t, 3, len(entries)) {
+		assert.Equal(t, deleted.ID, entries[0].ID)
+		assert.Equal(t, republished.ID, entries[1].ID)
+		assert.Equal(t, running.ID, entries[2].ID)
+	}
+
+	// Nothing is written back again
+	recoveries, err = j.Recover(context.Background(), newPublisher)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(recoveries))
+	for _, r := range recoveries {
+		assert.Equal(t, ActionFail, r.Action)
+	}
+}
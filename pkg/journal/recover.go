@@ -0,0 +1,156 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+const (
+	ActionRecover = "recover" // the ids are written back to the markdown file
+	ActionFinish  = "finish"  // nothing is lost, e.g. the ids are written back already or the save failed
+	ActionFail    = "fail"    // the entry is kept and recovered next time
+)
+
+// clockSkew is the max difference between the local clock and the clocks of the platforms,
+// the create times of the platforms are truncated to seconds too.
+const clockSkew = time.Minute
+
+// Recovery is the outcome of recovering an entry
+type Recovery struct {
+	Entry  *Entry
+	Action string
+	Err    error
+}
+
+func (r *Recovery) String() string {
+	e := r.Entry
+	s := fmt.Sprintf("journal %s: %s %s %s", e.ID, r.Action, e.Platform, e.File)
+	if e.ArticleID != "" {
+		s += " article " + e.ArticleID
+	} else if e.DraftID != "" {
+		s += " draft " + e.DraftID
+	}
+	if r.Err != nil {
+		s += ": " + r.Err.Error()
+	}
+	return s
+}
+
+// Recover reconciles the entries left by the interrupted saves, the entries of the running processes are skipped.
+// The ids in the saved entries are written back to the markdown files, the pending entries are looked up by
+// their titles if the platform is an ArticleFinder, and finished if no article with the title is found.
+// Only the article created after the entry is adopted, the entry is kept for journal drop if the articles
+// with the title are created before it, e.g. a republished article with the same title.
+func (j *Journal) Recover(ctx context.Context, newPublisher func(name string, mark *markdown.Mark) (platform.Publisher, error)) ([]*Recovery, error) {
+	j.mu.Lock()
+	err := j.load()
+	j.mu.Unlock()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	recoveries := make([]*Recovery, 0)
+	finished := make([]string, 0)
+	for _, e := range j.Entries() {
+		if e.PID != os.Getpid() && running(e.PID) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		r := &Recovery{Entry: e}
+		r.Action, r.Err = recoverEntry(ctx, e, newPublisher)
+		if r.Action != ActionFail {
+			finished = append(finished, e.ID)
+		}
+		recoveries = append(recoveries, r)
+	}
+	return recoveries, errors.Trace(j.Finish(finished...))
+}
+
+func recoverEntry(ctx context.Context, e *Entry, newPublisher func(name string, mark *markdown.Mark) (platform.Publisher, error)) (string, error) {
+	mark, err := markdown.Parse(e.File)
+	if err != nil {
+		return ActionFail, errors.Trace(err)
+	}
+	if !platform.HasMeta(mark, e.Platform) {
+		return ActionFail, errors.Errorf("%s meta not found", e.Platform)
+	}
+	if e.Status == StatusSaved && written(mark, e.Result()) {
+		return ActionFinish, nil
+	}
+	if e.Status == StatusPending && saved(mark, e) {
+		// Updating an article or a draft never loses the ids
+		return ActionFinish, nil
+	}
+
+	p, err := newPublisher(e.Platform, mark)
+	if err != nil {
+		return ActionFail, errors.Trace(err)
+	}
+	result := e.Result()
+	if e.Status == StatusPending {
+		a, err := platform.FindCreated(ctx, p, e.Title, e.CreateTime.Add(-clockSkew))
+		if err != nil {
+			return ActionFail, errors.Trace(err)
+		}
+		if a == nil {
+			old, err := platform.FindDuplicate(ctx, p, e.Title)
+			if err != nil {
+				return ActionFail, errors.Trace(err)
+			}
+			if old == nil {
+				return ActionFinish, nil
+			}
+			return ActionFail, errors.Errorf("article %q is created before the journal: %s, check it and drop the entry",
+				old.Title, old.URL)
+		}
+		result.ArticleID, result.DraftID, result.URL, result.IsCreate = a.ID, a.DraftID, a.URL, true
+		if e.SaveType == platform.SaveTypeDraft && a.DraftID == "" {
+			// The drafts share the ids with articles, e.g. csdn
+			result.SaveType = platform.SaveTypeArticle
+		}
+		e.ArticleID, e.DraftID = result.ArticleID, result.DraftID
+	}
+	if err = p.WriteBack(mark, result); err != nil {
+		return ActionFail, errors.Trace(err)
+	}
+	if err = mark.WriteFile(mark.File); err != nil {
+		return ActionFail, errors.Trace(err)
+	}
+	return ActionRecover, nil
+}
+
+// written reports whether the ids in result are in the platform meta of mark
+func written(mark *markdown.Mark, result *platform.Result) bool {
+	return (result.ArticleID == "" || mark.Meta.GetString(result.Platform+".article_id") == result.ArticleID) &&
+		(result.DraftID == "" || mark.Meta.GetString(result.Platform+".draft_id") == result.DraftID)
+}
+
+// saved reports whether mark has the id of the article or the draft saved by e, which is updated not created
+func saved(mark *markdown.Mark, e *Entry) bool {
+	if mark.Meta.GetString(e.Platform+".article_id") != "" {
+		return true
+	}
+	return e.SaveType == platform.SaveTypeDraft && mark.Meta.GetString(e.Platform+".draft_id") != ""
+}
+
+// running reports whether the process is running, it is always false on windows
+func running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
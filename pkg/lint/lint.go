@@ -0,0 +1,259 @@
+// Package lint checks the platform meta of markdown files offline,
+// so that the mistakes are found before the articles are published.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
+)
+
+// Severity of a diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in the meta of a markdown file
+type Diagnostic struct {
+	File     string
+	Line     int
+	Severity Severity
+	// Path of the meta key, e.g. juejin.tags.1
+	Path    string
+	Message string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s: %s", d.File, d.Line, d.Severity, d.Path, d.Message)
+}
+
+// Options of linting
+type Options struct {
+	// Platforms limits the checked platforms, all the platforms in the meta are checked if empty
+	Platforms []string
+	// Names are the known values by Field.Names, e.g. the cached juejin tags and the aliases,
+	// the values are not checked if absent
+	Names map[string][]string
+}
+
+// LintFile parses the markdown file and lints its meta
+func LintFile(path string, opts *Options) ([]*Diagnostic, error) {
+	mark, err := markdown.Parse(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	diags, err := Lint(mark, opts)
+	return diags, errors.Trace(err)
+}
+
+// Lint checks the platform meta of mark, the diagnostics are sorted by line
+func Lint(mark *markdown.Mark, opts *Options) ([]*Diagnostic, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	loc := newLocator(mark)
+	project, err := newProjectLocator(mark)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	diags := make([]*Diagnostic, 0)
+	report := func(path string, severity Severity, format string, args ...interface{}) {
+		// The inherited defaults are reported where they are written in the project file
+		file, line := mark.File, loc.Line(path)
+		if project != nil && mark.Inherited(path) {
+			if p, ok := projectPath(project, path); ok {
+				file, line = mark.Project, project.Line(p)
+			}
+		}
+		diags = append(diags, &Diagnostic{
+			File:     file,
+			Line:     line,
+			Severity: severity,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, name := range platformNames(mark, opts.Platforms) {
+		schema := Schemas[name]
+		meta, ok := mark.Meta.Get(name).(markdown.Meta)
+		if !ok {
+			report(name, SeverityError, "%s meta must be a map", name)
+			continue
+		}
+		checkFields(mark, name, meta, schema, opts, report)
+		if schema.Check != nil {
+			for _, p := range schema.Check(mark, meta) {
+				path := name
+				if p.Key != "" {
+					path += "." + p.Key
+				}
+				report(path, p.Severity, "%s", p.Message)
+			}
+		}
+	}
+
+	// The diagnostics of the file come before the ones of the project file
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File == mark.File
+		}
+		return diags[i].Line < diags[j].Line
+	})
+	return diags, nil
+}
+
+// platformNames returns the platforms in the meta which have a schema
+func platformNames(mark *markdown.Mark, only []string) []string {
+	names := make([]string, 0)
+	for _, item := range mark.Meta {
+		name, ok := item.Key.(string)
+		if !ok || Schemas[name] == nil {
+			continue
+		}
+		if len(only) > 0 && !contains(only, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func checkFields(mark *markdown.Mark, name string, meta markdown.Meta, schema *Schema, opts *Options,
+	report func(path string, severity Severity, format string, args ...interface{})) {
+	fields := make(map[string]*Field, len(schema.Fields))
+	for _, f := range schema.Fields {
+		fields[f.Key] = f
+	}
+	for _, item := range meta {
+		key, _ := item.Key.(string)
+		if fields[key] == nil && !contains(commonKeys, key) {
+			report(name+"."+key, SeverityWarning, "unknown key %q", key)
+		}
+	}
+
+	for _, f := range schema.Fields {
+		path := name + "." + f.Key
+		v := meta.Get(f.Key)
+		if v == nil && f.Inherit {
+			v = mark.Meta.Get(f.Key)
+		}
+		if isEmpty(v) {
+			if f.Required {
+				report(path, SeverityError, "%s is required", f.Key)
+			}
+			continue
+		}
+
+		switch f.Kind {
+		case Bool:
+			if _, ok := v.(bool); !ok {
+				report(path, SeverityError, "%s must be true or false", f.Key)
+			}
+		case String:
+			s, ok := v.(string)
+			if !ok {
+				report(path, SeverityError, "%s must be a string", f.Key)
+				continue
+			}
+			checkString(path, f, s, report)
+			checkNames(path, f, []string{s}, opts, report)
+		case List:
+			items, ok := v.([]interface{})
+			if !ok {
+				report(path, SeverityError, "%s must be a list", f.Key)
+				continue
+			}
+			values := make([]string, len(items))
+			for i, item := range items {
+				s, ok := item.(string)
+				if !ok {
+					report(fmt.Sprintf("%s.%d", path, i), SeverityError, "%s must be a list of strings", f.Key)
+				}
+				values[i] = s
+			}
+			if f.MaxItems > 0 && len(items) > f.MaxItems {
+				report(path, f.Limit, "%s has %d items, at most %d are allowed", f.Key, len(items), f.MaxItems)
+			}
+			checkNames(path, f, values, opts, report)
+		}
+	}
+}
+
+func checkString(path string, f *Field, s string, report func(path string, severity Severity, format string, args ...interface{})) {
+	if len(f.Enum) > 0 && !contains(f.Enum, s) {
+		report(path, SeverityError, "invalid %s %q, must be one of %s", f.Key, s, strings.Join(f.Enum, ", "))
+	}
+	n := len([]rune(s))
+	if f.MinLen > 0 && n < f.MinLen {
+		report(path, f.Limit, "%s has %d characters, at least %d are required", f.Key, n, f.MinLen)
+	}
+	if f.MaxLen > 0 && n > f.MaxLen {
+		report(path, f.Limit, "%s has %d characters, at most %d are allowed", f.Key, n, f.MaxLen)
+	}
+}
+
+// checkNames warns the values not in the known names case-insensitively, the empty values are skipped,
+// the names are usually cached and may be outdated, so they are not errors
+func checkNames(path string, f *Field, values []string, opts *Options, report func(path string, severity Severity, format string, args ...interface{})) {
+	if f.Names == "" {
+		return
+	}
+	names, ok := opts.Names[f.Names]
+	if !ok {
+		return
+	}
+	for i, v := range values {
+		if v == "" || containsFold(names, v) {
+			continue
+		}
+		p := path
+		if f.Kind == List {
+			p = fmt.Sprintf("%s.%d", path, i)
+		}
+		if suggestions := juejinsdk.Suggest(names, v); len(suggestions) > 0 {
+			report(p, SeverityWarning, "%s %q is not in the known names, did you mean %s?", f.Key, v, strings.Join(suggestions, ", "))
+			continue
+		}
+		report(p, SeverityWarning, "%s %q is not in the known names", f.Key, v)
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func containsFold(ss []string, s string) bool {
+	for _, i := range ss {
+		if strings.EqualFold(i, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, i := range ss {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
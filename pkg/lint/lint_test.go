@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+const testArticle = `---
+title: 标题
+juejin:
+  category: 后端
+  tags:
+  - Go
+  - 不存在
+  brief_content: 太短
+  sync_to_org: "yes"
+csdn:
+  tags: [a, b, c, d, e, f]
+  cover_images:
+  - https://example.com/1.png
+  - https://example.com/2.png
+  read_type: everyone
+  article_type: repost
+  unknown_key: 1
+oschina:
+  title: oschina
+---
+# Hello
+`
+
+func lintString(t *testing.T, content string, opts *Options) []*Diagnostic {
+	file := filepath.Join(t.TempDir(), "hello.md")
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diags, err := LintFile(file, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range diags {
+		assert.Equal(t, file, d.File)
+		d.File = ""
+	}
+	return diags
+}
+
+func TestLint(t *testing.T) {
+	opts := &Options{Names: map[string][]string{
+		"juejin.tags":     {"Go", "后端"},
+		"juejin.category": {"后端", "前端"},
+	}}
+	diags := lintString(t, testArticle, opts)
+
+	expected := []*Diagnostic{
+		{Line: 7, Severity: SeverityWarning, Path: "juejin.tags.1", Message: `tags "不存在" is not in the known names`},
+		{Line: 8, Severity: SeverityWarning, Path: "juejin.brief_content", Message: "brief_content has 2 characters, at least 50 are required"},
+		{Line: 9, Severity: SeverityError, Path: "juejin.sync_to_org", Message: "sync_to_org must be true or false"},
+		{Line: 15, Severity: SeverityError, Path: "csdn.read_type", Message: `invalid read_type "everyone", must be one of public, private, read_need_vip, read_need_fans`},
+		{Line: 11, Severity: SeverityError, Path: "csdn.tags", Message: "tags has 6 items, at most 5 are allowed"},
+		{Line: 12, Severity: SeverityWarning, Path: "csdn.cover_images", Message: "2 cover images are not supported, only the first one is used, use 1 or 3 cover images"},
+		{Line: 16, Severity: SeverityError, Path: "csdn.article_type", Message: "original_url is required for the repost article"},
+		{Line: 17, Severity: SeverityWarning, Path: "csdn.unknown_key", Message: `unknown key "unknown_key"`},
+		{Line: 18, Severity: SeverityError, Path: "oschina.category", Message: "category is required"},
+	}
+	assert.ElementsMatch(t, expected, diags)
+	for i := 1; i < len(diags); i++ {
+		assert.LessOrEqual(t, diags[i-1].Line, diags[i].Line)
+	}
+}
+
+func TestLintPlatforms(t *testing.T) {
+	diags := lintString(t, testArticle, &Options{Platforms: []string{"oschina"}})
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "oschina.category", diags[0].Path)
+	assert.Equal(t, ":18: error: oschina.category: category is required", diags[0].String())
+}
+
+func TestLintRequired(t *testing.T) {
+	diags := lintString(t, "\n---\njuejin:\n  tags: Go\n---\n", nil)
+	paths := make([]string, 0, len(diags))
+	for _, d := range diags {
+		paths = append(paths, d.Path)
+		// The missing keys are reported at the line of the platform
+		if d.Path != "juejin.tags" {
+			assert.Equal(t, 3, d.Line)
+		}
+	}
+	assert.ElementsMatch(t, []string{"juejin.title", "juejin.category", "juejin.tags"}, paths)
+
+	diags = lintString(t, "---\ntitle: 标题\njuejin:\n  category: 后端\n  tags: [Go]\n---\n", nil)
+	assert.Empty(t, diags)
+
+	diags = lintString(t, "---\ncsdn: csdn\n---\n", nil)
+	assert.Equal(t, []*Diagnostic{{Line: 2, Severity: SeverityError, Path: "csdn", Message: "csdn meta must be a map"}}, diags)
+}
+
+func TestLintLocation(t *testing.T) {
+	// A line longer than the default buffer of bufio.Scanner
+	long := strings.Repeat("a", 70*1024)
+	diags := lintString(t, "<!-- "+long+" -->\n---\ntitle: "+long+"\njuejin:\n  category: 后端\n  tags: [Go]\n  sync_to_org: 1\n---\n", nil)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, 7, diags[0].Line)
+	}
+
+	// The inherited defaults are reported in the project file
+	dir := t.TempDir()
+	project := filepath.Join(dir, markdown.ProjectFile)
+	if err := ioutil.WriteFile(project, []byte("platforms:\n  juejin:\n    category: 后端\n    sync_to_org: \"yes\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "hello.md")
+	if err := ioutil.WriteFile(file, []byte("---\ntitle: 标题\njuejin:\n  tags: [Go]\n  brief_content: 太短\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diags, err := LintFile(file, nil)
+	if !assert.Nil(t, err) || !assert.Len(t, diags, 2) {
+		return
+	}
+	assert.Equal(t, file, diags[0].File)
+	assert.Equal(t, 5, diags[0].Line)
+	assert.Equal(t, "juejin.brief_content", diags[0].Path)
+	assert.Equal(t, project, diags[1].File)
+	assert.Equal(t, 4, diags[1].Line)
+	assert.Equal(t, "juejin.sync_to_org", diags[1].Path)
+}
@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+// locator finds the lines of the meta keys in a yaml document
+type locator struct {
+	lines map[string]int
+	// start is the line returned for the paths not found
+	start int
+}
+
+// newLocator reads the lines of the yaml front matter of mark, all the keys are at the line
+// where the front matter starts in other formats
+func newLocator(mark *markdown.Mark) *locator {
+	front, line, ok := mark.FrontMatter()
+	if !ok {
+		return &locator{lines: make(map[string]int), start: 1}
+	}
+	if mark.Format != "" && mark.Format != markdown.FormatYAML {
+		return &locator{lines: make(map[string]int), start: line}
+	}
+	// The yaml text starts after the first separator
+	return parseLocator([]byte(front), line)
+}
+
+// newProjectLocator reads the lines of the keys in the project file of mark, nil if there is none
+func newProjectLocator(mark *markdown.Mark) (*locator, error) {
+	if mark.Project == "" {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(mark.Project)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return parseLocator(b, 0), nil
+}
+
+// parseLocator reads the lines of the keys in b, offset is added to the lines,
+// the meta is parsed by markdown.Parse before, so all the keys fall back to the start if b is invalid
+func parseLocator(b []byte, offset int) *locator {
+	loc := &locator{lines: make(map[string]int), start: offset}
+	if loc.start == 0 {
+		loc.start = 1
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return loc
+	}
+	if len(doc.Content) > 0 {
+		loc.walk("", doc.Content[0], offset)
+	}
+	return loc
+}
+
+func (l *locator) walk(path string, node *yaml.Node, offset int) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			p := join(node.Content[i].Value)
+			l.lines[p] = node.Content[i].Line + offset
+			l.walk(p, node.Content[i+1], offset)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			p := join(strconv.Itoa(i))
+			l.lines[p] = item.Line + offset
+			l.walk(p, item, offset)
+		}
+	}
+}
+
+// Line returns the line of path, or the line of its closest parent if path is not in the file,
+// e.g. the defaults of the project file, the start of the document is returned if none is found.
+func (l *locator) Line(path string) int {
+	for {
+		if n, ok := l.lines[path]; ok {
+			return n
+		}
+		i := strings.LastIndex(path, ".")
+		if i == -1 {
+			return l.start
+		}
+		path = path[:i]
+	}
+}
+
+// projectPath returns the path in the project file of the inherited path of the meta,
+// e.g. platforms.juejin.category for juejin.category, defaults.cover_images for cover_images
+func projectPath(project *locator, path string) (string, bool) {
+	for _, p := range []string{"platforms." + path, "defaults." + path} {
+		if _, ok := project.lines[p]; ok {
+			return p, true
+		}
+	}
+	return "", false
+}
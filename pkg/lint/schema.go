@@ -0,0 +1,144 @@
+package lint
+
+import (
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+	csdnsdk "github.com/k8scat/articli/pkg/platform/csdn"
+	juejinsdk "github.com/k8scat/articli/pkg/platform/juejin"
+	oschinasdk "github.com/k8scat/articli/pkg/platform/oschina"
+	"github.com/k8scat/articli/pkg/schedule"
+)
+
+// Kind is the type of a meta value
+type Kind int
+
+const (
+	String Kind = iota
+	Bool
+	List
+)
+
+// Field is the constraints of a key in the platform meta
+type Field struct {
+	Key  string
+	Kind Kind
+	// Required reports a missing key, the top level meta is also checked if Inherit
+	Required bool
+	// Inherit falls back to the same key in the top level meta, e.g. title
+	Inherit bool
+	Enum    []string
+	// MinLen and MaxLen limit the length of a string in characters
+	MinLen int
+	MaxLen int
+	// MaxItems limits the number of the items in a list
+	MaxItems int
+	// Limit is the severity of the values out of the limits, which are usually truncated by the platform
+	Limit Severity
+	// Names is the key of the known values in Options.Names, e.g. juejin.tags
+	Names string
+}
+
+// Problem is found by a custom check of a schema, Key is relative to the platform meta
+type Problem struct {
+	Key      string
+	Severity Severity
+	Message  string
+}
+
+// Schema is the constraints of the meta of a platform
+type Schema struct {
+	Fields []*Field
+	// Check finds the problems which can not be described by the fields
+	Check func(mark *markdown.Mark, meta markdown.Meta) []*Problem
+}
+
+// commonKeys are allowed in the meta of all the platforms
+var commonKeys = []string{
+	"article_id", "article_create_time", "article_update_time",
+	"draft_id", "draft_create_time", "draft_update_time",
+	"prefix_content", "suffix_content",
+	"profile", platform.ContentHashKey, schedule.PublishAtKey,
+}
+
+// Schemas are the constraints of the supported platforms by name
+var Schemas = map[string]*Schema{
+	juejinsdk.PlatformName: {
+		Fields: []*Field{
+			{Key: "title", Kind: String, Required: true, Inherit: true},
+			{Key: "category", Kind: String, Required: true, Names: "juejin.category"},
+			{Key: "tags", Kind: List, Required: true, Names: "juejin.tags"},
+			{Key: "cover_image", Kind: String},
+			{Key: "brief_content", Kind: String, MinLen: juejinsdk.MinBriefLength, MaxLen: juejinsdk.MaxBriefLength, Limit: SeverityWarning},
+			{Key: "sync_to_org", Kind: Bool},
+		},
+	},
+	csdnsdk.PlatformName: {
+		Fields: []*Field{
+			{Key: "title", Kind: String, Required: true, Inherit: true},
+			{Key: "categories", Kind: List, MaxItems: csdnsdk.MaxCategoryCount, Limit: SeverityError},
+			{Key: "tags", Kind: List, MaxItems: csdnsdk.MaxTagCount, Limit: SeverityError},
+			{Key: "cover_images", Kind: List, MaxItems: csdnsdk.MaxCoverImageCount, Limit: SeverityError},
+			{Key: "brief_content", Kind: String, MaxLen: csdnsdk.MaxDescriptionLength, Limit: SeverityWarning},
+			{Key: "publish_status", Kind: String, Enum: []string{
+				string(csdnsdk.PublishStatusPublish),
+				string(csdnsdk.PublishStatusDraft),
+			}},
+			{Key: "read_type", Kind: String, Enum: []string{
+				string(csdnsdk.ReadTypePublic),
+				string(csdnsdk.ReadTypePrivate),
+				string(csdnsdk.ReadTypeNeedVIP),
+				string(csdnsdk.ReadTypeNeedFans),
+			}},
+			{Key: "article_type", Kind: String, Enum: []string{
+				string(csdnsdk.SaveArticleTypeOriginal),
+				string(csdnsdk.SaveArticleTypeReship),
+				string(csdnsdk.SaveArticleTypeTranslation),
+			}},
+			{Key: "original_url", Kind: String},
+			{Key: "authorized_status", Kind: Bool},
+		},
+		Check: checkCSDN,
+	},
+	oschinasdk.PlatformName: {
+		Fields: []*Field{
+			{Key: "title", Kind: String, Required: true, Inherit: true},
+			{Key: "category", Kind: String, Required: true},
+			{Key: "technical_field", Kind: String},
+			{Key: "cover_image", Kind: String},
+			{Key: "original_url", Kind: String},
+			{Key: "privacy", Kind: Bool},
+			{Key: "deny_comment", Kind: Bool},
+			{Key: "download_image", Kind: Bool},
+			{Key: "top", Kind: Bool},
+		},
+	},
+}
+
+func checkCSDN(mark *markdown.Mark, meta markdown.Meta) []*Problem {
+	problems := make([]*Problem, 0)
+
+	// Two cover images are not supported, only the first one is used
+	images := meta.GetStringSlice("cover_images")
+	key := "cover_images"
+	if images == nil {
+		images = mark.Meta.GetStringSlice("cover_images")
+		key = ""
+	}
+	if len(images) == 2 {
+		problems = append(problems, &Problem{
+			Key:      key,
+			Severity: SeverityWarning,
+			Message:  "2 cover images are not supported, only the first one is used, use 1 or 3 cover images",
+		})
+	}
+
+	articleType := meta.GetString("article_type")
+	if articleType != "" && articleType != string(csdnsdk.SaveArticleTypeOriginal) && meta.GetString("original_url") == "" {
+		problems = append(problems, &Problem{
+			Key:      "article_type",
+			Severity: SeverityError,
+			Message:  "original_url is required for the " + articleType + " article",
+		})
+	}
+	return problems
+}
@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+var invalidFileNamePattern = regexp.MustCompile(`[\\/:*?"<>|\s]+`)
+
+// FileName returns a markdown file name built from title, fallback is used if title is empty
+func FileName(title, fallback string) string {
+	name := strings.Trim(invalidFileNamePattern.ReplaceAllString(strings.TrimSpace(title), "-"), "-.")
+	if name == "" {
+		name = fallback
+	}
+	return name + ".md"
+}
+
+// EnsureNewline makes sure the content ends with a newline
+func EnsureNewline(content string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		return content + "\n"
+	}
+	return content
+}
+
+// writeFileAtomic writes data to a temp file in the same dir and renames it to filename,
+// so that filename is never left half written, the mode of the existing file is kept.
+func writeFileAtomic(filename string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return errors.Trace(err)
+	}
+	if err = f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err = os.Chmod(tmp, mode); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp, filename))
+}
@@ -0,0 +1,20 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "Go-语言入门.md", FileName(" Go 语言入门 ", "1"))
+	assert.Equal(t, "a-b-c.md", FileName("a/b: c?", "1"))
+	assert.Equal(t, "1.md", FileName("", "1"))
+	assert.Equal(t, "1.md", FileName("...", "1"))
+}
+
+func TestEnsureNewline(t *testing.T) {
+	assert.Equal(t, "", EnsureNewline(""))
+	assert.Equal(t, "a\n", EnsureNewline("a"))
+	assert.Equal(t, "a\n", EnsureNewline("a\n"))
+}
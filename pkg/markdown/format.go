@@ -0,0 +1,493 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Format is the format of the front matter
+type Format string
+
+const (
+	// FormatYAML is the front matter between the --- lines
+	FormatYAML Format = "yaml"
+	// FormatTOML is the front matter between the +++ lines, e.g. hugo
+	FormatTOML Format = "toml"
+	// FormatJSON is the json object at the beginning of the file, e.g. hugo and hexo
+	FormatJSON Format = "json"
+)
+
+var (
+	tomlSeparatorPattern = regexp.MustCompile(`^\+\+\+ *$`)
+	bareKeyPattern       = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// splitFrontMatter splits the raw file into the front matter and the content, found reports
+// whether the front matter is closed, the format is yaml if the file starts with neither +++
+// nor a json object, e.g. a hugo shortcode {{< figure >}} is the content not the front matter.
+// line is the line where the front matter starts, i.e. the first separator or the json object.
+func splitFrontMatter(raw []byte) (format Format, front, content []byte, line int, found bool) {
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+	if n, ok := jsonObjectLen(raw); ok {
+		content = raw[n:]
+		if i := bytes.IndexByte(content, '\n'); i != -1 && len(bytes.TrimSpace(content[:i])) == 0 {
+			content = content[i+1:]
+		}
+		return FormatJSON, raw[:n], content, 1, true
+	}
+	if tomlSeparatorPattern.Match(firstLine(raw)) {
+		front, content, line, found = splitDelimited(raw, tomlSeparatorPattern)
+		return FormatTOML, front, content, line, found
+	}
+	front, content, line, found = splitDelimited(raw, metaSeparatorPattern)
+	return FormatYAML, front, content, line, found
+}
+
+// jsonObjectLen returns the length of the json object at the beginning of raw,
+// ok is false if raw does not start with a valid json object.
+func jsonObjectLen(raw []byte) (n int, ok bool) {
+	if !bytes.HasPrefix(raw, []byte("{")) {
+		return 0, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	var v map[string]json.RawMessage
+	if err := dec.Decode(&v); err != nil {
+		return 0, false
+	}
+	return int(dec.InputOffset()), true
+}
+
+// splitDelimited returns the lines between the first two separators and the lines after them,
+// the lines before the first separator are dropped, start is the line of the first separator.
+func splitDelimited(raw []byte, separator *regexp.Regexp) (front, content []byte, start int, found bool) {
+	separators := 0
+	for n, line := range splitLines(string(raw)) {
+		if separators < 2 && separator.MatchString(strings.TrimSuffix(line, "\n")) {
+			if separators == 0 {
+				start = n + 1
+			}
+			separators++
+			continue
+		}
+		switch separators {
+		case 1:
+			front = append(front, line...)
+		case 2:
+			content = append(content, line...)
+		}
+	}
+	if separators == 2 && front == nil {
+		front = []byte{}
+	}
+	return front, content, start, separators == 2
+}
+
+func firstLine(b []byte) []byte {
+	if i := bytes.IndexByte(b, '\n'); i != -1 {
+		return b[:i]
+	}
+	return b
+}
+
+// decodeMeta decodes the front matter in format
+func decodeMeta(format Format, front []byte) (Meta, error) {
+	switch format {
+	case FormatTOML:
+		m, err := decodeTOML(front)
+		return m, errors.Trace(err)
+	case FormatJSON:
+		if len(bytes.TrimSpace(front)) == 0 {
+			return nil, nil
+		}
+		dec := json.NewDecoder(bytes.NewReader(front))
+		dec.UseNumber()
+		v, err := decodeJSON(dec)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m, ok := v.(Meta)
+		if !ok {
+			return nil, errors.New("json front matter must be an object")
+		}
+		return m, nil
+	default:
+		var m Meta
+		err := yaml.Unmarshal(front, &m)
+		return m, errors.Trace(err)
+	}
+}
+
+// decodeTOML decodes the toml in the order of the keys in the text
+func decodeTOML(front []byte) (Meta, error) {
+	var v map[string]interface{}
+	md, err := toml.Decode(string(front), &v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var m Meta
+	for _, key := range md.Keys() {
+		parent := v
+		for _, k := range key[:len(key)-1] {
+			sub, ok := parent[k].(map[string]interface{})
+			if !ok {
+				// The keys in an array of tables
+				parent = nil
+				break
+			}
+			parent = sub
+		}
+		if parent == nil {
+			continue
+		}
+		value, ok := parent[key[len(key)-1]]
+		if !ok {
+			continue
+		}
+		if m.getPath(key) != nil {
+			continue
+		}
+		if _, ok := value.(map[string]interface{}); ok {
+			value = Meta{}
+		}
+		m = m.setPath(key, fromTOML(value))
+	}
+	return fillTOML(m, v), nil
+}
+
+// fillTOML appends the keys which are not listed in the order, e.g. the keys of inline tables
+func fillTOML(m Meta, v map[string]interface{}) Meta {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		existing, ok := getItem(m, k)
+		if !ok {
+			m = append(m, yaml.MapItem{Key: k, Value: fromTOML(v[k])})
+			continue
+		}
+		sub, ok1 := asMeta(existing)
+		table, ok2 := v[k].(map[string]interface{})
+		if ok1 && ok2 {
+			m = m.setPath([]string{k}, fillTOML(sub, table))
+		}
+	}
+	return m
+}
+
+func fromTOML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case Meta:
+		return t
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		m := make(Meta, 0, len(t))
+		for _, k := range keys {
+			m = append(m, yaml.MapItem{Key: k, Value: fromTOML(t[k])})
+		}
+		return m
+	case []map[string]interface{}:
+		s := make([]interface{}, len(t))
+		for i, item := range t {
+			s[i] = fromTOML(item)
+		}
+		return s
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, item := range t {
+			s[i] = fromTOML(item)
+		}
+		return s
+	case int64:
+		return int(t)
+	}
+	return v
+}
+
+// getPath returns the value by the keys, the keys may contain dots unlike Get
+func (m Meta) getPath(keys []string) interface{} {
+	v, _ := lookup(m, stringsToPath(keys))
+	return v
+}
+
+// setPath sets the value by the keys, the missing maps are created
+func (m Meta) setPath(keys []string, value interface{}) Meta {
+	for i, item := range m {
+		if item.Key != keys[0] {
+			continue
+		}
+		if len(keys) == 1 {
+			m[i].Value = value
+			return m
+		}
+		sub, _ := asMeta(item.Value)
+		m[i].Value = sub.setPath(keys[1:], value)
+		return m
+	}
+	if len(keys) == 1 {
+		return append(m, yaml.MapItem{Key: keys[0], Value: value})
+	}
+	return append(m, yaml.MapItem{Key: keys[0], Value: Meta{}.setPath(keys[1:], value)})
+}
+
+func stringsToPath(keys []string) []interface{} {
+	path := make([]interface{}, len(keys))
+	for i, k := range keys {
+		path[i] = k
+	}
+	return path
+}
+
+// decodeJSON decodes the next json value, the objects are decoded as Meta in order
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch d := t.(type) {
+	case json.Delim:
+		switch d {
+		case '{':
+			m := Meta{}
+			for dec.More() {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				v, err := decodeJSON(dec)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				m = append(m, yaml.MapItem{Key: k, Value: v})
+			}
+			_, err = dec.Token()
+			return m, errors.Trace(err)
+		case '[':
+			s := make([]interface{}, 0)
+			for dec.More() {
+				v, err := decodeJSON(dec)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				s = append(s, v)
+			}
+			_, err = dec.Token()
+			return s, errors.Trace(err)
+		}
+	case json.Number:
+		if i, err := d.Int64(); err == nil {
+			return int(i), nil
+		}
+		f, err := d.Float64()
+		return f, errors.Trace(err)
+	}
+	return t, nil
+}
+
+// encodeMeta encodes meta in format, without the separators
+func encodeMeta(format Format, meta Meta) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatTOML:
+		err = encodeTOML(&buf, nil, meta)
+	case FormatJSON:
+		err = encodeJSON(&buf, meta, "")
+		buf.WriteString("\n")
+	default:
+		var b []byte
+		b, err = yaml.Marshal(meta)
+		buf.Write(b)
+	}
+	return buf.Bytes(), errors.Trace(err)
+}
+
+// encodeTOML writes the keys of the table in order, the sub tables are written after the other keys
+func encodeTOML(w *bytes.Buffer, path []string, meta Meta) error {
+	tables := make([]yaml.MapItem, 0)
+	for _, item := range meta {
+		if item.Value == nil {
+			continue
+		}
+		if _, ok := asMeta(item.Value); ok {
+			tables = append(tables, item)
+			continue
+		}
+		v, err := tomlValue(item.Value)
+		if err != nil {
+			return errors.Annotatef(err, "encode %v", item.Key)
+		}
+		w.WriteString(tomlKey(toString(item.Key)) + " = " + v + "\n")
+	}
+	for _, item := range tables {
+		sub, _ := asMeta(item.Value)
+		p := append(append([]string{}, path...), toString(item.Key))
+		keys := make([]string, len(p))
+		for i, k := range p {
+			keys[i] = tomlKey(k)
+		}
+		// The header of a table with only sub tables is implied by the sub tables
+		if !onlyTables(sub) {
+			if w.Len() > 0 {
+				w.WriteString("\n")
+			}
+			w.WriteString("[" + strings.Join(keys, ".") + "]\n")
+		}
+		if err := encodeTOML(w, p, sub); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func onlyTables(m Meta) bool {
+	for _, item := range m {
+		if _, ok := asMeta(item.Value); !ok {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+// tomlValue encodes a value in a line, the maps in arrays are written as inline tables
+func tomlValue(v interface{}) (string, error) {
+	if m, ok := asMeta(v); ok {
+		items := make([]string, 0, len(m))
+		for _, item := range m {
+			if item.Value == nil {
+				continue
+			}
+			s, err := tomlValue(item.Value)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			items = append(items, tomlKey(toString(item.Key))+" = "+s)
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	}
+	if s, ok := v.([]interface{}); ok {
+		items := make([]string, 0, len(s))
+		for _, item := range s {
+			i, err := tomlValue(item)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			items = append(items, i)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+
+	// The scalars and the slices of scalars are encoded by toml
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"v": v}); err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(buf.String(), "v = "), "\n"), nil
+}
+
+func tomlKey(k string) string {
+	if bareKeyPattern.MatchString(k) {
+		return k
+	}
+	b, _ := json.Marshal(k)
+	return string(b)
+}
+
+// encodeJSON writes the json indented by 2 spaces, the keys of Meta are written in order
+func encodeJSON(w *bytes.Buffer, v interface{}, indent string) error {
+	if m, ok := asMeta(v); ok {
+		if len(m) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+		w.WriteString("{\n")
+		for i, item := range m {
+			w.WriteString(indent + "  ")
+			if err := writeJSONScalar(w, toString(item.Key)); err != nil {
+				return errors.Trace(err)
+			}
+			w.WriteString(": ")
+			if err := encodeJSON(w, item.Value, indent+"  "); err != nil {
+				return errors.Trace(err)
+			}
+			if i < len(m)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(indent + "}")
+		return nil
+	}
+	if s, ok := v.([]interface{}); ok {
+		// The arrays of scalars are written in a line
+		if len(s) == 0 || onlyScalars(s) {
+			var buf bytes.Buffer
+			for i, item := range s {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				if err := writeJSONScalar(&buf, item); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			w.WriteString("[" + buf.String() + "]")
+			return nil
+		}
+		w.WriteString("[\n")
+		for i, item := range s {
+			w.WriteString(indent + "  ")
+			if err := encodeJSON(w, item, indent+"  "); err != nil {
+				return errors.Trace(err)
+			}
+			if i < len(s)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(indent + "]")
+		return nil
+	}
+	return errors.Trace(writeJSONScalar(w, v))
+}
+
+func onlyScalars(s []interface{}) bool {
+	for _, item := range s {
+		switch item.(type) {
+		case Meta, yaml.MapSlice, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSONScalar(w io.Writer, v interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return errors.Trace(err)
+}
+
+func toString(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	b, _ := yaml.Marshal(k)
+	return strings.TrimSpace(string(b))
+}
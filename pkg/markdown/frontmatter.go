@@ -0,0 +1,301 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// edit sets or deletes the value of a key in the front matter
+type edit struct {
+	path   []interface{}
+	value  interface{}
+	delete bool
+}
+
+// patchFrontMatter returns the yaml text of the front matter with only the keys changed from old to meta rewritten,
+// the comments, anchors, quoting and blank lines of the other keys are kept as they are.
+func patchFrontMatter(text string, old, meta Meta) (string, error) {
+	edits := diffMeta(nil, old, meta)
+	if len(edits) == 0 {
+		return text, nil
+	}
+
+	lines := splitLines(text)
+	for _, e := range edits {
+		var err error
+		if lines, err = applyEdit(lines, e, meta); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	patched := strings.Join(lines, "")
+
+	// The patched text must be parsed to the same meta, otherwise the whole meta is marshaled
+	var m Meta
+	if err := yaml.Unmarshal([]byte(patched), &m); err != nil {
+		return "", errors.Annotate(err, "invalid patched front matter")
+	}
+	if !equalValue(m, meta) {
+		return "", errors.New("patched front matter does not match the meta")
+	}
+	return patched, nil
+}
+
+// diffMeta returns the edits which change old to meta, the new keys are appended in the order of meta
+func diffMeta(path []interface{}, old, meta Meta) []*edit {
+	edits := make([]*edit, 0)
+	for _, item := range meta {
+		p := appendPath(path, item.Key)
+		v, ok := getItem(old, item.Key)
+		if !ok {
+			edits = append(edits, &edit{path: p, value: item.Value})
+			continue
+		}
+		oldSub, ok1 := asMeta(v)
+		sub, ok2 := asMeta(item.Value)
+		if ok1 && ok2 && len(oldSub) > 0 && len(sub) > 0 {
+			edits = append(edits, diffMeta(p, oldSub, sub)...)
+			continue
+		}
+		if !equalValue(v, item.Value) {
+			edits = append(edits, &edit{path: p, value: item.Value})
+		}
+	}
+	for _, item := range old {
+		if _, ok := getItem(meta, item.Key); !ok {
+			edits = append(edits, &edit{path: appendPath(path, item.Key), delete: true})
+		}
+	}
+	return edits
+}
+
+// applyEdit patches the lines of the front matter, meta is the whole new meta
+// which replaces the nearest block mapping if the edited mapping is in flow style.
+func applyEdit(lines []string, e *edit, meta Meta) ([]string, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(strings.Join(lines, "")), &doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(doc.Content) == 0 {
+		// An empty front matter
+		if e.delete {
+			return lines, nil
+		}
+		return insertLines(lines, len(lines), render(e.path[0], e.value, 0)), nil
+	}
+
+	node := doc.Content[0]
+	if node.Kind != yamlv3.MappingNode || node.Style&yamlv3.FlowStyle != 0 {
+		return nil, errors.New("front matter is not a block mapping")
+	}
+	for depth, key := range e.path {
+		i := findKey(node, key)
+		last := depth == len(e.path)-1
+		if i == -1 {
+			if e.delete {
+				// The key is not in the text, e.g. merged by <<
+				return nil, errors.NotFoundf("key %v", key)
+			}
+			if !last {
+				return nil, errors.NotFoundf("key %v", key)
+			}
+			return addKey(lines, node, key, e.value), nil
+		}
+
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if last {
+			if e.delete {
+				start, end := entrySpan(lines, keyNode, valueNode)
+				return append(lines[:start], lines[end:]...), nil
+			}
+			return setKey(lines, keyNode, valueNode, e.value), nil
+		}
+		if valueNode.Kind != yamlv3.MappingNode || valueNode.Style&yamlv3.FlowStyle != 0 {
+			// The flow mapping is rewritten as a whole
+			v, _ := lookup(meta, e.path[:depth+1])
+			return setKey(lines, keyNode, valueNode, v), nil
+		}
+		node = valueNode
+	}
+	return lines, nil
+}
+
+// setKey replaces the lines of the key and its value, the comment at the end of a single line value is kept
+func setKey(lines []string, keyNode, valueNode *yamlv3.Node, value interface{}) []string {
+	start, end := entrySpan(lines, keyNode, valueNode)
+	rendered := render(keyNode.Value, value, keyNode.Column-1)
+	comment := valueNode.LineComment
+	if comment == "" {
+		comment = keyNode.LineComment
+	}
+	if comment != "" && len(rendered) == 1 && end-start == 1 {
+		rendered[0] = strings.TrimSuffix(rendered[0], "\n") + " " + comment + "\n"
+	}
+	replaced := append(append([]string{}, lines[:start]...), rendered...)
+	return append(replaced, lines[end:]...)
+}
+
+// addKey appends the key after the last entry of the block mapping node
+func addKey(lines []string, node *yamlv3.Node, key, value interface{}) []string {
+	n := len(node.Content)
+	if n == 0 {
+		return insertLines(lines, len(lines), render(key, value, 0))
+	}
+	lastKey, lastValue := node.Content[n-2], node.Content[n-1]
+	_, end := entrySpan(lines, lastKey, lastValue)
+	return insertLines(lines, end, render(key, value, node.Content[0].Column-1))
+}
+
+// entrySpan returns the lines [start, end) of a mapping entry, from the key to the last line of the value,
+// the blank lines and comments after the value are not included, which usually belong to the next key.
+func entrySpan(lines []string, keyNode, valueNode *yamlv3.Node) (int, int) {
+	start := keyNode.Line - 1
+	indent := keyNode.Column - 1
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		s := strings.TrimRight(lines[i], "\r\n")
+		trimmed := strings.TrimLeft(s, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		n := len(s) - len(trimmed)
+		// The items of a sequence may be at the same indentation as the key
+		if n < indent || (n == indent && !(valueNode.Kind == yamlv3.SequenceNode && strings.HasPrefix(trimmed, "-"))) {
+			end = i
+			break
+		}
+	}
+	for end > start+1 {
+		trimmed := strings.TrimSpace(lines[end-1])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		end--
+	}
+	return start, end
+}
+
+// render marshals the key and value into the lines indented by indent spaces
+func render(key, value interface{}, indent int) []string {
+	b, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: value}})
+	if err != nil {
+		b = []byte(fmt.Sprintf("%v: %v\n", key, value))
+	}
+	lines := splitLines(string(b))
+	prefix := strings.Repeat(" ", indent)
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return lines
+}
+
+func findKey(node *yamlv3.Node, key interface{}) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == fmt.Sprint(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func insertLines(lines []string, i int, inserted []string) []string {
+	// The last line may have no newline
+	if i > 0 && i == len(lines) && !strings.HasSuffix(lines[i-1], "\n") {
+		lines[i-1] += "\n"
+	}
+	result := append(append([]string{}, lines[:i]...), inserted...)
+	return append(result, lines[i:]...)
+}
+
+// splitLines splits s into the lines with their newlines
+func splitLines(s string) []string {
+	lines := make([]string, 0)
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, key)
+}
+
+func getItem(m Meta, key interface{}) (interface{}, bool) {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func lookup(m Meta, path []interface{}) (interface{}, bool) {
+	var v interface{} = m
+	for _, key := range path {
+		sub, ok := asMeta(v)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = getItem(sub, key); !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func asMeta(v interface{}) (Meta, bool) {
+	switch t := v.(type) {
+	case Meta:
+		return t, true
+	case yaml.MapSlice:
+		return Meta(t), true
+	}
+	return nil, false
+}
+
+// equalValue compares the values by their yaml, so that e.g. []string equals to []interface{}
+func equalValue(a, b interface{}) bool {
+	x, err1 := yaml.Marshal(a)
+	y, err2 := yaml.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
+
+// clone returns a deep copy of m, so that it is not changed by Set
+func (m Meta) clone() Meta {
+	if m == nil {
+		return nil
+	}
+	result := make(Meta, len(m))
+	for i, item := range m {
+		result[i] = yaml.MapItem{Key: item.Key, Value: cloneValue(item.Value)}
+	}
+	return result
+}
+
+func cloneValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case Meta:
+		return t.clone()
+	case yaml.MapSlice:
+		return Meta(t).clone()
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, item := range t {
+			s[i] = cloneValue(item)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package markdown
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "Update the golden files")
+
+func TestWriteFileGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		edit func(m *Mark)
+	}{
+		{
+			name: "comments",
+			edit: func(m *Mark) {
+				m.Meta = m.Meta.Set("juejin.article_id", "7055689358657093646")
+				m.Meta = m.Meta.Set("juejin.article_create_time", "2022-03-01 09:30:00")
+				m.Meta = m.Meta.Set("csdn.article_id", "123")
+				m.Meta = m.Meta.Set("title", "Hello, World")
+			},
+		},
+		{
+			name: "empty",
+			edit: func(m *Mark) {
+				m.Meta = m.Meta.Set("title", "Hello")
+				m.Meta = m.Meta.Set("juejin", Meta{}.Set("article_id", "1"))
+			},
+		},
+		{
+			name: "list",
+			edit: func(m *Mark) {
+				m.Meta = m.Meta.Set("tags", []string{"a", "c"})
+				m.Meta = m.Meta.Set("oschina.content_hash", "abc")
+				m.Meta = m.Meta.Set("csdn", Meta{}.Set("article_id", "2"))
+				m.Meta = Meta(m.Meta).without("", map[string]interface{}{"title": "列表"})
+			},
+		},
+		{
+			name: "hugo",
+			edit: func(m *Mark) {
+				m.Meta = m.Meta.Set("juejin.article_id", "7055689358657093646")
+				m.Meta = m.Meta.Set("csdn", Meta{}.Set("article_id", "1"))
+			},
+		},
+		{
+			name: "hexo",
+			edit: func(m *Mark) {
+				m.Meta = m.Meta.Set("csdn.article_id", "123")
+				m.Meta = m.Meta.Set("juejin", Meta{}.Set("draft_id", "1"))
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input := filepath.Join("testdata", "frontmatter", c.name+".md")
+			golden := filepath.Join("testdata", "frontmatter", c.name+".golden.md")
+			b, err := ioutil.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(t.TempDir(), c.name+".md")
+			if err = ioutil.WriteFile(file, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mark, err := Parse(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Nothing is changed without edits
+			assert.Nil(t, mark.WriteFile(file))
+			actual, _ := ioutil.ReadFile(file)
+			assert.Equal(t, string(b), string(actual))
+
+			c.edit(mark)
+			assert.Nil(t, mark.WriteFile(file))
+			actual, _ = ioutil.ReadFile(file)
+			if *update {
+				if err = ioutil.WriteFile(golden, actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(actual))
+
+			// The written file is parsed to the same meta
+			written, err := Parse(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, equalValue(mark.Meta, written.Meta))
+			assert.Equal(t, mark.Content, written.Content)
+		})
+	}
+}
+
+func TestWriteFileWithoutFrontMatter(t *testing.T) {
+	mark := &Mark{Content: "# Hello\n"}
+	mark.Meta = mark.Meta.Set("title", "Hello")
+	file := filepath.Join(t.TempDir(), "new.md")
+	assert.Nil(t, mark.WriteFile(file))
+	b, _ := ioutil.ReadFile(file)
+	assert.Equal(t, "---\ntitle: Hello\n---\n# Hello\n", string(b))
+
+	// The raw file is read once
+	parsed, err := Parse(file)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), string(parsed.Raw))
+	assert.Equal(t, 1, strings.Count(string(parsed.Raw), "# Hello"))
+}
+
+func TestParseFormats(t *testing.T) {
+	mark, err := Parse(filepath.Join("testdata", "frontmatter", "hugo.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, FormatTOML, mark.Format)
+	assert.Equal(t, "Hugo 文章", mark.Meta.GetString("title"))
+	assert.Equal(t, []string{"Go", "Hugo"}, mark.Meta.GetStringSlice("tags"))
+	assert.Equal(t, 10, mark.Meta.Get("weight"))
+	assert.Equal(t, "后端", mark.Meta.GetString("juejin.category"))
+	assert.Equal(t, "k8scat", mark.Meta.GetString("params.author.name"))
+	assert.Equal(t, "# Hugo\n\n<!--more-->\n", mark.Content)
+	assert.Equal(t, "# Hugo", mark.Brief)
+	keys := make([]interface{}, 0)
+	for _, item := range mark.Meta {
+		keys = append(keys, item.Key)
+	}
+	assert.Equal(t, []interface{}{"title", "date", "draft", "tags", "weight", "juejin", "params"}, keys)
+
+	mark, err = Parse(filepath.Join("testdata", "frontmatter", "hexo.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, FormatJSON, mark.Format)
+	assert.Equal(t, "Hexo 文章", mark.Meta.GetString("title"))
+	assert.Equal(t, []string{"Go"}, mark.Meta.GetStringSlice("csdn.tags"))
+	assert.Equal(t, "# Hexo\n", mark.Content)
+
+	mark, err = Parse(filepath.Join("testdata", "frontmatter", "comments.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, FormatYAML, mark.Format)
+
+	// The hugo shortcode at the beginning is not a json front matter
+	mark, err = Parse(filepath.Join("testdata", "frontmatter", "shortcode.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, FormatYAML, mark.Format)
+	assert.Nil(t, mark.Meta)
+}
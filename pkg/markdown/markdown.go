@@ -1,14 +1,10 @@
 package markdown
 
 import (
-	"bufio"
 	"bytes"
 	"github.com/gomarkdown/markdown"
 	"github.com/juju/errors"
-	"gopkg.in/yaml.v2"
-	"io"
 	"io/ioutil"
-	"os"
 	"regexp"
 	"strings"
 )
@@ -19,33 +15,119 @@ var (
 )
 
 type Mark struct {
+	// Meta is the front matter merged with the defaults of the project file
 	Meta    Meta
 	Raw     []byte
 	Content string
 	Brief   string
 	File    string
+	// Project is the path of the project file whose defaults are merged, empty if not found
+	Project string
+	// Format is the format of the front matter, which is kept on writing, yaml is used if empty
+	Format Format
+	// Draft is set by the draft field of the mapping, e.g. draft: true of hugo, a draft is not published
+	Draft bool
+
+	// defaults are the merged defaults by path, e.g. juejin.category
+	defaults map[string]interface{}
+	// front is the text of the front matter in the file, nil if the mark is not parsed from a file
+	front *string
+	// frontLine is the line where front starts in the file, i.e. the first separator or the json object
+	frontLine int
+	// original is the meta of front, the changes from it are patched into front on writing
+	original Meta
+	// state is where the state written back after publishing is kept, nil if it is kept in the front matter
+	state *stateStore
 }
 
+// WriteFile writes the mark to filename atomically, only the changed keys of the yaml front matter
+// are rewritten if the mark is parsed from a file, so that the comments and formatting are kept.
+// If the project keeps the state out of the front matter, the state is saved to the state file instead,
+// and the file is not written if nothing else is changed.
 func (m *Mark) WriteFile(filename string) error {
-	f, err := os.Create(filename)
-	if err != nil {
-		return errors.Trace(err)
+	meta := m.OwnMeta()
+	if m.state != nil {
+		if err := m.state.save(stateOf(meta)); err != nil {
+			return errors.Trace(err)
+		}
+		// The state left in the front matter is kept as it is until migrated
+		meta = withState(meta, func(name, key string) (interface{}, bool) {
+			v := m.original.Get(name + "." + key)
+			return v, v != nil
+		})
 	}
-	defer f.Close()
+	return errors.Trace(m.write(filename, meta))
+}
 
-	f.Write([]byte("---\n"))
+// write writes meta as the front matter and the content of m to filename, which is skipped if the file is unchanged
+func (m *Mark) write(filename string, meta Meta) error {
+	format := m.Format
+	if format == "" {
+		format = FormatYAML
+	}
+	var front string
+	patched := false
+	if m.front != nil {
+		if equalValue(m.original, meta) {
+			front, patched = *m.front, true
+		} else if format == FormatYAML {
+			var err error
+			if front, err = patchFrontMatter(*m.front, m.original, meta); err == nil {
+				patched = true
+			}
+		}
+	}
+	if !patched {
+		b, err := encodeMeta(format, meta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		front = string(b)
+	}
 
-	b, err := yaml.Marshal(m.Meta)
-	if err != nil {
-		return errors.Trace(err)
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		buf.WriteString(front)
+		if !strings.HasSuffix(front, "\n") {
+			buf.WriteString("\n")
+		}
+	default:
+		separator := "---\n"
+		if format == FormatTOML {
+			separator = "+++\n"
+		}
+		buf.WriteString(separator)
+		buf.WriteString(front)
+		if front != "" && !strings.HasSuffix(front, "\n") {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(separator)
+	}
+	buf.WriteString(m.Content)
+	if b, err := ioutil.ReadFile(filename); err != nil || !bytes.Equal(b, buf.Bytes()) {
+		if err := writeFileAtomic(filename, buf.Bytes()); err != nil {
+			return errors.Trace(err)
+		}
 	}
-	f.Write(b)
 
-	f.Write([]byte("---\n"))
-	f.Write([]byte(m.Content))
+	m.front = &front
+	m.frontLine = 1
+	m.original = meta.clone()
 	return nil
 }
 
+// FrontMatter returns the text of the front matter in the file and the line where it starts,
+// i.e. the first separator or the json object, ok is false if the mark is not parsed from a file
+// or the file has no front matter.
+func (m *Mark) FrontMatter() (front string, line int, ok bool) {
+	if m.front == nil {
+		return "", 0, false
+	}
+	return *m.front, m.frontLine, true
+}
+
+// Parse reads the markdown file with the yaml, toml or json front matter
 func Parse(filepath string) (result *Mark, err error) {
 	result = &Mark{
 		File: filepath,
@@ -56,65 +138,33 @@ func Parse(filepath string) (result *Mark, err error) {
 		return
 	}
 
-	br := bufio.NewReader(bytes.NewReader(result.Raw))
-	var meta, brief, content []byte
-	metaSeparatorCount := 0
-	moreSeparatorCount := 0
-	afterMeta := false
-	afterMore := false
-	for {
-		if metaSeparatorCount == 2 {
-			afterMeta = true
-		}
-		if moreSeparatorCount == 1 {
-			afterMore = true
-		}
+	format, front, content, line, found := splitFrontMatter(result.Raw)
+	m, err := decodeMeta(format, front)
+	if err != nil {
+		err = errors.Annotatef(err, "invalid %s front matter", format)
+		return
+	}
 
-		line, isPrefix, re := br.ReadLine()
-		if re == io.EOF {
+	// The brief is the content before the more separator, or the whole content
+	var brief []byte
+	for _, line := range splitLines(string(content)) {
+		if moreSeparatorPattern.MatchString(strings.TrimSuffix(line, "\n")) {
 			break
 		}
-		result.Raw = append(result.Raw, line...)
-		if !isPrefix {
-			result.Raw = append(result.Raw, '\n')
-		}
-
-		if metaSeparatorPattern.Match(line) {
-			metaSeparatorCount += 1
-		}
-		if moreSeparatorPattern.Match(line) {
-			moreSeparatorCount += 1
-		}
-		if metaSeparatorCount == 1 {
-			meta = append(meta, line...)
-			if !isPrefix {
-				meta = append(meta, '\n')
-			}
-		}
-		if afterMeta && !afterMore {
-			brief = append(brief, line...)
-			if !isPrefix {
-				brief = append(brief, '\n')
-			}
-		}
-		if afterMeta {
-			content = append(content, line...)
-			if !isPrefix {
-				content = append(content, '\n')
-			}
-		}
-	}
-
-	var m Meta
-	err = yaml.Unmarshal(meta, &m)
-	if err != nil {
-		err = errors.Trace(err)
-		return
+		brief = append(brief, line...)
 	}
 
+	result.Format = format
 	result.Brief = strings.TrimSpace(string(brief))
 	result.Content = string(content)
 	result.Meta = m
+	if found {
+		f := string(front)
+		result.front = &f
+		result.frontLine = line
+		result.original = m.clone()
+	}
+	err = errors.Trace(result.applyProject())
 	return
 }
 
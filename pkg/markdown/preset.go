@@ -0,0 +1,247 @@
+package markdown
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// The standard fields which are mapped from the meta of static site generators
+const (
+	FieldTitle        = "title"
+	FieldTags         = "tags"
+	FieldCategories   = "categories"
+	FieldBriefContent = "brief_content"
+	FieldCoverImage   = "cover_image"
+	FieldDraft        = "draft"
+	FieldPublishAt    = "publish_at"
+)
+
+// Mapping maps the standard fields to the top level keys of the meta, the first non-empty key is used,
+// a key is a path like cover.image, and !published means the negation of the boolean published.
+type Mapping map[string][]string
+
+// Presets are the mappings of the static site generators
+var Presets = map[string]Mapping{
+	"hugo": {
+		FieldTitle:        {"title"},
+		FieldTags:         {"tags"},
+		FieldCategories:   {"categories"},
+		FieldBriefContent: {"description", "summary"},
+		FieldCoverImage:   {"images", "featured_image"},
+		FieldDraft:        {"draft"},
+	},
+	"hugo-papermod": {
+		FieldTitle:        {"title"},
+		FieldTags:         {"tags"},
+		FieldCategories:   {"categories"},
+		FieldBriefContent: {"description", "summary"},
+		FieldCoverImage:   {"cover.image", "images"},
+		FieldDraft:        {"draft"},
+	},
+	"hexo": {
+		FieldTitle:        {"title"},
+		FieldTags:         {"tags"},
+		FieldCategories:   {"categories"},
+		FieldBriefContent: {"description", "excerpt"},
+		FieldCoverImage:   {"cover", "thumbnail", "banner"},
+		FieldDraft:        {"!published"},
+	},
+	"jekyll": {
+		FieldTitle:        {"title"},
+		FieldTags:         {"tags"},
+		FieldCategories:   {"categories", "category"},
+		FieldBriefContent: {"excerpt", "description"},
+		FieldCoverImage:   {"image.path", "image"},
+		FieldDraft:        {"!published"},
+	},
+}
+
+// target is a key of the platform meta which is filled by a standard field
+type target struct {
+	key     string
+	field   string
+	convert func(v interface{}) interface{}
+}
+
+// platformTargets are the keys of each platform filled by the standard fields
+var platformTargets = map[string][]*target{
+	"juejin": {
+		{key: "title", field: FieldTitle, convert: firstString},
+		{key: "tags", field: FieldTags, convert: stringList},
+		{key: "category", field: FieldCategories, convert: firstString},
+		{key: "brief_content", field: FieldBriefContent, convert: firstString},
+		{key: "cover_image", field: FieldCoverImage, convert: firstString},
+		{key: "publish_at", field: FieldPublishAt, convert: timeValue},
+	},
+	"csdn": {
+		{key: "title", field: FieldTitle, convert: firstString},
+		{key: "tags", field: FieldTags, convert: stringList},
+		{key: "categories", field: FieldCategories, convert: stringList},
+		{key: "brief_content", field: FieldBriefContent, convert: firstString},
+		{key: "cover_images", field: FieldCoverImage, convert: stringList},
+		{key: "publish_at", field: FieldPublishAt, convert: timeValue},
+	},
+	"oschina": {
+		{key: "title", field: FieldTitle, convert: firstString},
+		{key: "category", field: FieldCategories, convert: firstString},
+		{key: "cover_image", field: FieldCoverImage, convert: firstString},
+		{key: "publish_at", field: FieldPublishAt, convert: timeValue},
+	},
+}
+
+// mapping returns the mapping of the preset overridden by the mapping in the project
+func (p *Project) mapping() (Mapping, error) {
+	if p.Preset == "" && len(p.Mapping) == 0 {
+		return nil, nil
+	}
+	m := make(Mapping)
+	if p.Preset != "" {
+		preset, ok := Presets[p.Preset]
+		if !ok {
+			return nil, errors.NotSupportedf("preset %q, supported presets are %s", p.Preset, strings.Join(PresetNames(), ", "))
+		}
+		for k, v := range preset {
+			m[k] = v
+		}
+	}
+	for k, v := range p.Mapping {
+		if !isField(k) {
+			return nil, errors.NotValidf("mapping field %q", k)
+		}
+		// An empty list disables the field
+		m[k] = v
+	}
+	return m, nil
+}
+
+// PresetNames returns the names of the presets in order
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isField(k string) bool {
+	switch k {
+	case FieldTitle, FieldTags, FieldCategories, FieldBriefContent, FieldCoverImage, FieldDraft, FieldPublishAt:
+		return true
+	}
+	return false
+}
+
+// apply fills the missing keys of the platform meta in mark with the standard fields,
+// the filled values are recorded as defaults, so that they are not written back.
+// The draft field is not mapped to any platform, it marks the whole article as a draft.
+func (m Mapping) apply(mark *Mark) {
+	mark.Draft = m.value(mark.Meta, FieldDraft, isTrue) != nil
+	for name, targets := range platformTargets {
+		meta, ok := mark.Meta.Get(name).(Meta)
+		if !ok {
+			continue
+		}
+		merged := append(Meta{}, meta...)
+		for _, t := range targets {
+			if meta.Get(t.key) != nil {
+				continue
+			}
+			v := m.value(mark.Meta, t.field, t.convert)
+			if v == nil {
+				continue
+			}
+			merged = merged.Set(t.key, v)
+			mark.defaults[name+"."+t.key] = v
+		}
+		mark.Meta = mark.Meta.Set(name, merged)
+	}
+}
+
+// value returns the first non-empty value of the keys of the field which is converted successfully
+func (m Mapping) value(meta Meta, field string, convert func(v interface{}) interface{}) interface{} {
+	for _, key := range m[field] {
+		negate := strings.HasPrefix(key, "!")
+		v := meta.Get(strings.TrimPrefix(key, "!"))
+		if isEmptyValue(v) {
+			continue
+		}
+		if negate {
+			b, ok := v.(bool)
+			if !ok {
+				continue
+			}
+			v = !b
+		}
+		if v = convert(v); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// firstString returns the string, or the first string of a list
+func firstString(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// stringList returns the strings of a list, or a list of the string
+func stringList(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return []interface{}{t}
+	case []interface{}:
+		s := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			if i, ok := item.(string); ok && i != "" {
+				s = append(s, i)
+			}
+		}
+		if len(s) > 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+// timeValue returns the time or the string of a time
+func timeValue(v interface{}) interface{} {
+	switch v.(type) {
+	case time.Time, string:
+		return v
+	}
+	return nil
+}
+
+// isTrue returns true for the true boolean, nil otherwise
+func isTrue(v interface{}) interface{} {
+	if b, ok := v.(bool); ok && b {
+		return true
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ProjectFile provides the default meta of the markdown files in its directory and all the subdirectories
+const ProjectFile = ".articli.yml"
+
+// FallbackKeys are the keys of each platform meta which fall back to the same keys at the top level,
+// a top level key in a file only overrides the platform default of the project if the platform falls back to it
+var FallbackKeys = map[string][]string{
+	"juejin":  {"title"},
+	"oschina": {"title"},
+	"csdn":    {"title", "cover_images", "prefix_content", "suffix_content"},
+}
+
+// Project is the content of ProjectFile, e.g.
+//
+//	preset: hugo
+//	state: manifest
+//	defaults:
+//	  cover_images:
+//	  - https://example.com/cover.png
+//	platforms:
+//	  juejin:
+//	    category: 后端
+//	    suffix_content: ...
+type Project struct {
+	// Defaults are the default top level meta
+	Defaults Meta `yaml:"defaults,omitempty"`
+	// Platforms are the default meta of each platform, they are only used by the files which have the platform meta
+	Platforms Meta `yaml:"platforms,omitempty"`
+	// Preset is the name of the static site generator in Presets, whose fields fill the platform meta
+	Preset string `yaml:"preset,omitempty"`
+	// Mapping overrides the fields of the preset, e.g. publish_at: [date]
+	Mapping Mapping `yaml:"mapping,omitempty"`
+	// State is the backend of the state written back after publishing, one of front_matter, manifest and sidecar
+	State string `yaml:"state,omitempty"`
+
+	// File is the path of the project file
+	File string `yaml:"-"`
+}
+
+// FindProject returns the nearest project file walking up from dir, empty if not found
+func FindProject(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for {
+		p := filepath.Join(dir, ProjectFile)
+		info, err := os.Stat(p)
+		if err == nil && !info.IsDir() {
+			return p, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", errors.Trace(err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads the project file
+func LoadProject(path string) (*Project, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := &Project{File: path}
+	if err = yaml.Unmarshal(b, p); err != nil {
+		return nil, errors.Annotatef(err, "parse %s", path)
+	}
+	if _, err = p.mapping(); err != nil {
+		return nil, errors.Annotatef(err, "parse %s", path)
+	}
+	if !isStateBackend(p.State) {
+		return nil, errors.NotValidf("state %q in %s", p.State, path)
+	}
+	return p, nil
+}
+
+// Apply merges the defaults of p into the meta of mark, the precedence from high to low is:
+//  1. the platform meta in the file
+//  2. the top level meta in the file, including the fields mapped to the platform meta by the preset,
+//     which only overrides the platform defaults of the keys in FallbackKeys
+//  3. the platform defaults in the project
+//  4. the top level defaults in the project
+//
+// The values are never merged deeply, e.g. the tags in the file replace the default tags.
+// The merged defaults are not written back by Mark.WriteFile.
+func (p *Project) Apply(mark *Mark) {
+	mark.Project = p.File
+	if mark.defaults == nil {
+		mark.defaults = make(map[string]interface{})
+	}
+
+	if m, _ := p.mapping(); m != nil {
+		m.apply(mark)
+	}
+
+	own := make(map[string]bool, len(mark.Meta))
+	for _, item := range mark.Meta {
+		if k, ok := item.Key.(string); ok {
+			own[k] = true
+		}
+	}
+
+	for _, item := range p.Platforms {
+		name, ok := item.Key.(string)
+		if !ok {
+			continue
+		}
+		defaults, ok := item.Value.(Meta)
+		if !ok {
+			continue
+		}
+		meta, ok := mark.Meta.Get(name).(Meta)
+		if !ok {
+			continue
+		}
+		fallback := make(map[string]bool, len(FallbackKeys[name]))
+		for _, k := range FallbackKeys[name] {
+			fallback[k] = true
+		}
+		merged := append(Meta{}, meta...)
+		for _, d := range defaults {
+			k, ok := d.Key.(string)
+			if !ok || (own[k] && fallback[k]) || meta.Get(k) != nil {
+				continue
+			}
+			merged = append(merged, d)
+			mark.defaults[name+"."+k] = d.Value
+		}
+		mark.Meta = mark.Meta.Set(name, merged)
+	}
+
+	for _, d := range p.Defaults {
+		k, ok := d.Key.(string)
+		if !ok || own[k] {
+			continue
+		}
+		mark.Meta = append(mark.Meta, d)
+		mark.defaults[k] = d.Value
+	}
+}
+
+// applyProject merges the defaults of the nearest project file into the meta of m
+func (m *Mark) applyProject() error {
+	path, err := FindProject(filepath.Dir(m.File))
+	if err != nil || path == "" {
+		return errors.Trace(err)
+	}
+	p, err := LoadProject(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.Apply(m)
+
+	s, err := p.stateStore(m)
+	if err != nil || s == nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(m.applyState(s))
+}
+
+// SetFile sets the file of m which is not parsed from a file, e.g. an article pulled from a platform,
+// and merges the defaults of the project of the file like Parse, so that WriteFile leaves out the defaults
+// and keeps the state in the state backend of the project. The state in m replaces the stored one on writing.
+func (m *Mark) SetFile(file string) error {
+	m.File = file
+	path, err := FindProject(filepath.Dir(file))
+	if err != nil || path == "" {
+		return errors.Trace(err)
+	}
+	p, err := LoadProject(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The values same as the defaults are merged back as the defaults, so that they are not written
+	m.Meta = m.Meta.without("", p.defaultValues())
+	p.Apply(m)
+
+	m.state, err = p.stateStore(m)
+	return errors.Trace(err)
+}
+
+// defaultValues returns the defaults of p by path, e.g. juejin.category
+func (p *Project) defaultValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, d := range p.Defaults {
+		if k, ok := d.Key.(string); ok {
+			values[k] = d.Value
+		}
+	}
+	for _, item := range p.Platforms {
+		name, ok := item.Key.(string)
+		if !ok {
+			continue
+		}
+		defaults, _ := item.Value.(Meta)
+		for _, d := range defaults {
+			if k, ok := d.Key.(string); ok {
+				values[name+"."+k] = d.Value
+			}
+		}
+	}
+	return values
+}
+
+// Inherited reports whether the value of path, e.g. juejin.category or juejin.tags.0, is an unchanged
+// default merged from the project file rather than written in the file.
+func (m *Mark) Inherited(path string) bool {
+	for p := path; ; {
+		if v, ok := m.defaults[p]; ok {
+			return reflect.DeepEqual(v, m.Meta.Get(p))
+		}
+		i := strings.LastIndex(p, ".")
+		if i == -1 {
+			return false
+		}
+		p = p[:i]
+	}
+}
+
+// OwnMeta returns the meta of the file without the unchanged defaults of the project
+func (m *Mark) OwnMeta() Meta {
+	if len(m.defaults) == 0 {
+		return m.Meta
+	}
+	return m.Meta.without("", m.defaults)
+}
+
+func (m Meta) without(prefix string, defaults map[string]interface{}) Meta {
+	result := make(Meta, 0, len(m))
+	for _, item := range m {
+		k, ok := item.Key.(string)
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		path := prefix + k
+		if v, ok := defaults[path]; ok && reflect.DeepEqual(v, item.Value) {
+			continue
+		}
+		if sub, ok := item.Value.(Meta); ok && prefix == "" {
+			item.Value = sub.without(path+".", defaults)
+		}
+		result = append(result, item)
+	}
+	return result
+}
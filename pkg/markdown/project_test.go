@@ -0,0 +1,224 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProject = `defaults:
+  title: default title
+  cover_images:
+  - https://example.com/cover.png
+platforms:
+  juejin:
+    category: 后端
+    tags:
+    - Go
+    suffix_content: powered by articli
+    title: juejin title
+  csdn:
+    read_type: public
+`
+
+const testArticle = `---
+title: 标题
+juejin:
+  tags:
+  - 程序员
+oschina:
+  category: 日常记录
+---
+# Hello
+`
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectPlatformDefaults(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), `platforms:
+  juejin:
+    tags: [Go]
+    category: 后端
+  csdn:
+    tags: [Go]
+    prefix_content: csdn prefix
+`)
+	file := filepath.Join(dir, "hello.md")
+	writeFile(t, file, `---
+title: 标题
+tags: [golang]
+category: 随笔
+prefix_content: top prefix
+juejin: {}
+csdn: {}
+---
+# Hello
+`)
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// juejin never reads the top level tags and category, so the defaults are kept
+	assert.Equal(t, []string{"Go"}, mark.Meta.GetStringSlice("juejin.tags"))
+	assert.Equal(t, "后端", mark.Meta.GetString("juejin.category"))
+	// csdn falls back to the top level prefix content, which overrides the default
+	assert.Equal(t, []string{"Go"}, mark.Meta.GetStringSlice("csdn.tags"))
+	assert.Nil(t, mark.Meta.Get("csdn.prefix_content"))
+}
+
+func TestProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), testProject)
+	file := filepath.Join(dir, "posts", "2022", "hello.md")
+	writeFile(t, file, testArticle)
+
+	project, err := FindProject(filepath.Dir(file))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, ProjectFile), project)
+
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, project, mark.Project)
+	// The file overrides the defaults
+	assert.Equal(t, "标题", mark.Meta.GetString("title"))
+	assert.Equal(t, []string{"程序员"}, mark.Meta.GetStringSlice("juejin.tags"))
+	// The top level meta in the file overrides the platform defaults
+	assert.Equal(t, "", mark.Meta.GetString("juejin.title"))
+	assert.Equal(t, "后端", mark.Meta.GetString("juejin.category"))
+	assert.Equal(t, "powered by articli", mark.Meta.GetString("juejin.suffix_content"))
+	assert.Equal(t, []string{"https://example.com/cover.png"}, mark.Meta.GetStringSlice("cover_images"))
+	// The platforms are not added by the defaults
+	assert.Nil(t, mark.Meta.Get("csdn"))
+	assert.Equal(t, "日常记录", mark.Meta.GetString("oschina.category"))
+
+	// The unchanged defaults are not written back
+	mark.Meta = mark.Meta.Set("juejin.article_id", "1")
+	mark.Meta = mark.Meta.Set("juejin.category", "前端")
+	assert.Nil(t, mark.WriteFile(file))
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "suffix_content")
+	assert.NotContains(t, string(b), "cover_images")
+	assert.Contains(t, string(b), "category: 前端")
+	assert.Contains(t, string(b), `article_id: "1"`)
+
+	writeFile(t, filepath.Join(dir, "posts", ProjectFile), "defaults: [")
+	_, err = Parse(file)
+	assert.NotNil(t, err)
+}
+
+func TestFindProjectNotFound(t *testing.T) {
+	dir := t.TempDir()
+	project, err := FindProject(dir)
+	assert.Nil(t, err)
+	if project != "" {
+		// A project file in the parents of the temp dir is outside of the test
+		assert.NotContains(t, project, dir)
+	}
+}
+
+const testHugoArticle = `+++
+title = "Hugo"
+date = 2022-03-01T10:00:00+08:00
+tags = ["Go", "Hugo"]
+categories = ["后端"]
+summary = "摘要"
+draft = true
+
+[cover]
+image = "https://example.com/cover.png"
+
+[juejin]
+tags = ["Go"]
+
+[csdn]
++++
+# Hugo
+`
+
+func TestProjectPreset(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), `preset: hugo-papermod
+mapping:
+  publish_at: [date]
+  categories: []
+platforms:
+  juejin:
+    category: 前端
+`)
+	file := filepath.Join(dir, "hugo.md")
+	writeFile(t, file, testHugoArticle)
+
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// The platform meta in the file wins
+	assert.Equal(t, []string{"Go"}, mark.Meta.GetStringSlice("juejin.tags"))
+	assert.Equal(t, []string{"Go", "Hugo"}, mark.Meta.GetStringSlice("csdn.tags"))
+	assert.Equal(t, "Hugo", mark.Meta.GetString("csdn.title"))
+	assert.Equal(t, "摘要", mark.Meta.GetString("juejin.brief_content"))
+	assert.Equal(t, "https://example.com/cover.png", mark.Meta.GetString("juejin.cover_image"))
+	assert.Equal(t, []string{"https://example.com/cover.png"}, mark.Meta.GetStringSlice("csdn.cover_images"))
+	assert.True(t, mark.Draft)
+	assert.Nil(t, mark.Meta.Get("csdn.publish_status"))
+	assert.NotNil(t, mark.Meta.Get("csdn.publish_at"))
+	// The categories are disabled by the mapping, so the platform defaults are used
+	assert.Equal(t, "前端", mark.Meta.GetString("juejin.category"))
+	assert.Nil(t, mark.Meta.Get("csdn.categories"))
+
+	// The mapped fields are not written back
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	mark.Meta = mark.Meta.Set("juejin.article_id", "1")
+	assert.Nil(t, mark.WriteFile(file))
+	written, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Replace(string(b), "[juejin]\ntags = [\"Go\"]\n", "[juejin]\ntags = [\"Go\"]\narticle_id = \"1\"\n", 1), string(written))
+
+	writeFile(t, filepath.Join(dir, ProjectFile), "preset: gatsby\n")
+	_, err = Parse(file)
+	assert.NotNil(t, err)
+	writeFile(t, filepath.Join(dir, ProjectFile), "mapping:\n  author: [author]\n")
+	_, err = Parse(file)
+	assert.NotNil(t, err)
+}
+
+func TestPresetDraft(t *testing.T) {
+	mark := &Mark{defaults: make(map[string]interface{})}
+	mark.Meta = mark.Meta.Set("published", false)
+	mark.Meta = mark.Meta.Set("image", "https://example.com/a.png")
+	mark.Meta = mark.Meta.Set("csdn", Meta{})
+	Presets["jekyll"].apply(mark)
+	assert.True(t, mark.Draft)
+	assert.Nil(t, mark.Meta.Get("csdn.publish_status"))
+	assert.Equal(t, []string{"https://example.com/a.png"}, mark.Meta.GetStringSlice("csdn.cover_images"))
+
+	mark.Meta = mark.Meta.Set("published", true)
+	Presets["jekyll"].apply(mark)
+	assert.False(t, mark.Draft)
+
+	mark = &Mark{defaults: make(map[string]interface{})}
+	mark.Meta = mark.Meta.Set("draft", true)
+	Presets["hugo"].apply(mark)
+	assert.True(t, mark.Draft)
+
+	// The draft field can be disabled by the mapping
+	m := Mapping{FieldDraft: nil}
+	m.apply(mark)
+	assert.False(t, mark.Draft)
+}
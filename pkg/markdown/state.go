@@ -0,0 +1,336 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// The backends of the state written back after publishing, which is set by state in ProjectFile
+const (
+	// StateFrontMatter keeps the state in the platform meta of the front matter, which is the default
+	StateFrontMatter = "front_matter"
+	// StateManifest keeps the state of all the articles in StateManifestFile of the project dir
+	StateManifest = "manifest"
+	// StateSidecar keeps the state of every article in a file next to it, e.g. post.md.articli.json
+	StateSidecar = "sidecar"
+)
+
+const (
+	// StateManifestFile is the path of the manifest relative to the project dir
+	StateManifestFile = ".articli/state.json"
+	// SidecarSuffix is appended to the name of an article to get its sidecar
+	SidecarSuffix = ".articli.json"
+	// StateIDKey is the key of the stable identifier of an article in the manifest,
+	// the path relative to the project dir is used if it is not set
+	StateIDKey = "slug"
+)
+
+// StateKeys are the keys of the platform meta which are written back after publishing
+var StateKeys = []string{
+	"article_id",
+	"draft_id",
+	"article_create_time",
+	"article_update_time",
+	"draft_create_time",
+	"draft_update_time",
+	"content_hash",
+}
+
+// IsStateKey reports whether k is one of StateKeys
+func IsStateKey(k string) bool {
+	for _, s := range StateKeys {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+// ArticleState is the state of an article in every platform, e.g. juejin: {article_id: "1"}
+type ArticleState struct {
+	// File is the path of the article relative to the project dir
+	File      string                       `json:"file,omitempty"`
+	Platforms map[string]map[string]string `json:"platforms"`
+}
+
+// Manifest is the content of StateManifestFile, the articles are keyed by their identifiers
+type Manifest struct {
+	Articles map[string]*ArticleState `json:"articles"`
+}
+
+// manifestMu serializes the updates of the manifests, e.g. the files published concurrently by sync
+var manifestMu sync.Mutex
+
+// stateStore is where the state of an article is kept out of the markdown file
+type stateStore struct {
+	// file is the manifest or the sidecar
+	file string
+	// key is the identifier of the article in the manifest, empty for a sidecar
+	key string
+	// path is the path of the article relative to the project dir
+	path string
+}
+
+func isStateBackend(s string) bool {
+	switch s {
+	case "", StateFrontMatter, StateManifest, StateSidecar:
+		return true
+	}
+	return false
+}
+
+// stateStore returns the store of the state of mark, nil if the state is kept in the front matter
+func (p *Project) stateStore(mark *Mark) (*stateStore, error) {
+	if p.State != StateManifest && p.State != StateSidecar {
+		return nil, nil
+	}
+	file, err := filepath.Abs(mark.File)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dir := filepath.Dir(p.File)
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &stateStore{path: filepath.ToSlash(rel)}
+	if p.State == StateSidecar {
+		s.file = file + SidecarSuffix
+		return s, nil
+	}
+	s.file = filepath.Join(dir, filepath.FromSlash(StateManifestFile))
+	s.key = s.path
+	if id := mark.Meta.GetString(StateIDKey); id != "" {
+		s.key = id
+	}
+	return s, nil
+}
+
+// load returns the state of the article, nil if not found
+func (s *stateStore) load() (*ArticleState, error) {
+	if s.key == "" {
+		state := new(ArticleState)
+		found, err := readJSON(s.file, state)
+		if !found || err != nil {
+			return nil, errors.Trace(err)
+		}
+		return state, nil
+	}
+
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+	manifest, err := loadManifest(s.file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return manifest.Articles[s.key], nil
+}
+
+// save merges the state of the platforms into the stored state, the other platforms are kept
+func (s *stateStore) save(platforms map[string]map[string]string) error {
+	if len(platforms) == 0 {
+		return nil
+	}
+	if s.key == "" {
+		state := new(ArticleState)
+		if _, err := readJSON(s.file, state); err != nil {
+			return errors.Trace(err)
+		}
+		if !mergeState(state, s.path, platforms) {
+			return nil
+		}
+		return errors.Trace(writeJSON(s.file, state))
+	}
+
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+	manifest, err := loadManifest(s.file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	state, ok := manifest.Articles[s.key]
+	if !ok {
+		state = new(ArticleState)
+		manifest.Articles[s.key] = state
+	}
+	if !mergeState(state, s.path, platforms) {
+		return nil
+	}
+	if err = os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(writeJSON(s.file, manifest))
+}
+
+// mergeState sets the platforms of state, and reports whether state is changed
+func mergeState(state *ArticleState, path string, platforms map[string]map[string]string) bool {
+	changed := state.File != path
+	state.File = path
+	if state.Platforms == nil {
+		state.Platforms = make(map[string]map[string]string)
+	}
+	for name, values := range platforms {
+		if !equalValue(state.Platforms[name], values) {
+			state.Platforms[name] = values
+			changed = true
+		}
+	}
+	return changed
+}
+
+// LoadManifest reads the manifest, an empty manifest is returned if file does not exist
+func LoadManifest(file string) (*Manifest, error) {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+	manifest, err := loadManifest(file)
+	return manifest, errors.Trace(err)
+}
+
+func loadManifest(file string) (*Manifest, error) {
+	manifest := new(Manifest)
+	if _, err := readJSON(file, manifest); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if manifest.Articles == nil {
+		manifest.Articles = make(map[string]*ArticleState)
+	}
+	return manifest, nil
+}
+
+func readJSON(file string, v interface{}) (bool, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return false, errors.Errorf("invalid state data: %s", file)
+	}
+	return true, nil
+}
+
+func writeJSON(file string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(writeFileAtomic(file, append(b, '\n')))
+}
+
+// stateOf returns the values of StateKeys in the platform meta of meta
+func stateOf(meta Meta) map[string]map[string]string {
+	platforms := make(map[string]map[string]string)
+	for _, item := range meta {
+		name, ok := item.Key.(string)
+		if !ok {
+			continue
+		}
+		sub, ok := item.Value.(Meta)
+		if !ok {
+			continue
+		}
+		values := make(map[string]string)
+		for _, i := range sub {
+			k, ok := i.Key.(string)
+			if !ok || !IsStateKey(k) || i.Value == nil {
+				continue
+			}
+			values[k] = fmt.Sprint(i.Value)
+		}
+		if len(values) > 0 {
+			platforms[name] = values
+		}
+	}
+	return platforms
+}
+
+// withState returns meta whose values of StateKeys are replaced by the ones in state,
+// a key is removed if it is not in state, and the platform meta not in meta is not added.
+func withState(meta Meta, state func(name, key string) (interface{}, bool)) Meta {
+	result := make(Meta, 0, len(meta))
+	for _, item := range meta {
+		name, ok := item.Key.(string)
+		sub, isMeta := item.Value.(Meta)
+		if !ok || !isMeta {
+			result = append(result, item)
+			continue
+		}
+		// The keys are replaced in place, so that the order of the keys is kept
+		values := make(Meta, 0, len(sub))
+		seen := make(map[string]bool)
+		for _, i := range sub {
+			k, ok := i.Key.(string)
+			if !ok || !IsStateKey(k) {
+				values = append(values, i)
+				continue
+			}
+			seen[k] = true
+			if v, ok := state(name, k); ok {
+				i.Value = v
+				values = append(values, i)
+			}
+		}
+		for _, k := range StateKeys {
+			if v, ok := state(name, k); ok && !seen[k] {
+				values = append(values, yaml.MapItem{Key: k, Value: v})
+			}
+		}
+		item.Value = values
+		result = append(result, item)
+	}
+	return result
+}
+
+// applyState merges the stored state into the platform meta of m, which takes precedence
+// over the state in the front matter, e.g. the ids left before migrating.
+func (m *Mark) applyState(s *stateStore) error {
+	m.state = s
+	state, err := s.load()
+	if err != nil || state == nil {
+		return errors.Trace(err)
+	}
+	m.Meta = withState(m.Meta, func(name, key string) (interface{}, bool) {
+		if v, ok := state.Platforms[name][key]; ok {
+			return v, true
+		}
+		v := m.Meta.Get(name + "." + key)
+		return v, v != nil
+	})
+	return nil
+}
+
+// StateFile returns the manifest or the sidecar keeping the state of m, empty if the state is kept in the front matter
+func (m *Mark) StateFile() string {
+	if m.state == nil {
+		return ""
+	}
+	return m.state.file
+}
+
+// MigrateState moves the state in the front matter into the state file and removes it from the markdown file,
+// it reports whether the markdown file is changed.
+func (m *Mark) MigrateState() (bool, error) {
+	if m.state == nil {
+		return false, errors.NotSupportedf("migrating the state kept in the front matter")
+	}
+	meta := m.OwnMeta()
+	if err := m.state.save(stateOf(meta)); err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(stateOf(m.original)) == 0 {
+		return false, nil
+	}
+	meta = withState(meta, func(name, key string) (interface{}, bool) {
+		return nil, false
+	})
+	return true, errors.Trace(m.write(m.File, meta))
+}
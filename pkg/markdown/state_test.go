@@ -0,0 +1,157 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStateArticle = `---
+title: 标题 # comment
+juejin:
+  tags:
+  - Go
+  article_id: "1"
+csdn:
+  read_type: public
+---
+# Hello
+`
+
+func TestStateManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), "state: manifest\n")
+	file := filepath.Join(dir, "posts", "hello.md")
+	writeFile(t, file, testStateArticle)
+
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, filepath.Join(dir, ".articli", "state.json"), mark.StateFile())
+
+	// The state is saved to the manifest, and the file is not changed
+	mark.Meta = mark.Meta.Set("juejin.article_id", "2")
+	mark.Meta = mark.Meta.Set("csdn.article_id", "3")
+	assert.Nil(t, mark.WriteFile(file))
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, testStateArticle, string(b))
+
+	manifest, err := LoadManifest(mark.StateFile())
+	if !assert.Nil(t, err) {
+		return
+	}
+	state := manifest.Articles["posts/hello.md"]
+	if assert.NotNil(t, state) {
+		assert.Equal(t, "posts/hello.md", state.File)
+		assert.Equal(t, map[string]string{"article_id": "2"}, state.Platforms["juejin"])
+		assert.Equal(t, map[string]string{"article_id": "3"}, state.Platforms["csdn"])
+	}
+
+	// The stored state takes precedence over the front matter
+	mark, err = Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "2", mark.Meta.GetString("juejin.article_id"))
+	assert.Equal(t, "3", mark.Meta.GetString("csdn.article_id"))
+
+	changed, err := mark.MigrateState()
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	b, err = ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "---\ntitle: 标题 # comment\njuejin:\n  tags:\n  - Go\ncsdn:\n  read_type: public\n---\n# Hello\n", string(b))
+
+	mark, err = Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "2", mark.Meta.GetString("juejin.article_id"))
+	changed, err = mark.MigrateState()
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
+
+func TestStateSidecar(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), "state: sidecar\n")
+	file := filepath.Join(dir, "hello.md")
+	writeFile(t, file, "---\nslug: hello\njuejin: {}\noschina: {}\n---\n# Hello\n")
+
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, file+SidecarSuffix, mark.StateFile())
+	mark.Meta = mark.Meta.Set("juejin.article_id", "1")
+	assert.Nil(t, mark.WriteFile(file))
+	mark.Meta = mark.Meta.Set("oschina.draft_id", "2")
+	assert.Nil(t, mark.WriteFile(file))
+
+	mark, err = Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "1", mark.Meta.GetString("juejin.article_id"))
+	assert.Equal(t, "2", mark.Meta.GetString("oschina.draft_id"))
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "---\nslug: hello\njuejin: {}\noschina: {}\n---\n# Hello\n", string(b))
+}
+
+func TestSetFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), "state: manifest\nplatforms:\n  juejin:\n    category: 后端\n")
+
+	// The pulled article keeps its state in the manifest and leaves out the defaults of the project
+	var meta Meta
+	meta = meta.Set("title", "Hello")
+	meta = meta.Set("juejin", Meta{}.Set("category", "后端").Set("article_id", "1"))
+	mark := &Mark{Meta: meta, Content: "# Hello\n"}
+	file := filepath.Join(dir, "hello.md")
+	if !assert.Nil(t, mark.SetFile(file)) {
+		return
+	}
+	assert.Equal(t, filepath.Join(dir, StateManifestFile), mark.StateFile())
+	assert.Nil(t, mark.WriteFile(file))
+
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "---\ntitle: Hello\njuejin: {}\n---\n# Hello\n", string(b))
+	mark, err = Parse(file)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "1", mark.Meta.GetString("juejin.article_id"))
+		assert.Equal(t, "后端", mark.Meta.GetString("juejin.category"))
+	}
+}
+
+func TestStateKey(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ProjectFile), "state: manifest\n")
+	file := filepath.Join(dir, "hello.md")
+	writeFile(t, file, "---\nslug: hello-world\njuejin: {}\n---\n# Hello\n")
+
+	mark, err := Parse(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+	mark.Meta = mark.Meta.Set("juejin.article_id", "1")
+	assert.Nil(t, mark.WriteFile(file))
+
+	// The state follows the slug after the file is renamed
+	renamed := filepath.Join(dir, "posts", "hello.md")
+	writeFile(t, renamed, "---\nslug: hello-world\njuejin: {}\n---\n# Hello\n")
+	mark, err = Parse(renamed)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "1", mark.Meta.GetString("juejin.article_id"))
+
+	writeFile(t, filepath.Join(dir, ProjectFile), "state: git\n")
+	_, err = Parse(file)
+	assert.NotNil(t, err)
+}
@@ -2,20 +2,27 @@ package csdn
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/juju/errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
 func (c *Client) ListArticles(req *ListArticlesRequest) (articles []Article, count *ArticleCount, err error) {
+	return c.ListArticlesContext(context.Background(), req)
+}
+
+func (c *Client) ListArticlesContext(ctx context.Context, req *ListArticlesRequest) (articles []Article, count *ArticleCount, err error) {
 	if err = req.Validate(); err != nil {
 		err = errors.Trace(err)
 		return
 	}
 
-	rawurl := BuildBizAPIURL("/blog-console-api/v1/article/list")
+	rawurl := c.BuildBizAPIURL("/blog-console-api/v1/article/list")
 	query := req.IntoQuery()
 
 	if ResourceGateway == nil {
@@ -25,7 +32,7 @@ func (c *Client) ListArticles(req *ListArticlesRequest) (articles []Article, cou
 	}
 
 	var resp *http.Response
-	resp, err = c.Get(rawurl, query, ResourceGateway)
+	resp, err = c.GetContext(ctx, rawurl, query, ResourceGateway)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -57,8 +64,39 @@ func (c *Client) ListArticles(req *ListArticlesRequest) (articles []Article, cou
 	return
 }
 
+// ListAllArticles lists the articles page by page until limit is reached, all articles are listed if limit <= 0
+func (c *Client) ListAllArticles(req *ListArticlesRequest, limit int) ([]Article, error) {
+	return c.ListAllArticlesContext(context.Background(), req, limit)
+}
+
+func (c *Client) ListAllArticlesContext(ctx context.Context, req *ListArticlesRequest, limit int) ([]Article, error) {
+	if err := req.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Article, 0)
+	for {
+		articles, _, err := c.ListArticlesContext(ctx, req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, articles...)
+		if (limit > 0 && len(result) >= limit) || len(articles) < req.PageSize {
+			break
+		}
+		req.Page++
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
 func (c *Client) SaveArticle(params *SaveArticleParams) error {
-	rawurl := BuildBizAPIURL("/blog-console-api/v3/mdeditor/saveArticle")
+	return c.SaveArticleContext(context.Background(), params)
+}
+
+func (c *Client) SaveArticleContext(ctx context.Context, params *SaveArticleParams) error {
+	rawurl := c.BuildBizAPIURL("/blog-console-api/v3/mdeditor/saveArticle")
 	b, err := json.Marshal(params)
 	if err != nil {
 		return errors.Trace(err)
@@ -71,7 +109,7 @@ func (c *Client) SaveArticle(params *SaveArticleParams) error {
 	}
 
 	body := bytes.NewReader(b)
-	resp, err := c.Post(rawurl, nil, body, ResourceGateway)
+	resp, err := c.PostContext(ctx, rawurl, nil, body, ResourceGateway)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -97,3 +135,102 @@ func (c *Client) SaveArticle(params *SaveArticleParams) error {
 	params.URL = result.Data.URL
 	return nil
 }
+
+// GetArticle returns the article detail with the markdown content
+func (c *Client) GetArticle(id string) (*ArticleDetail, error) {
+	return c.GetArticleContext(context.Background(), id)
+}
+
+func (c *Client) GetArticleContext(ctx context.Context, id string) (*ArticleDetail, error) {
+	rawurl := c.BuildBizAPIURL("/blog-console-api/v3/editor/getArticle")
+	query := url.Values{
+		"id":         []string{id},
+		"model_type": []string{""},
+	}
+
+	if ResourceGateway == nil {
+		if err := InitResourceGateway(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	resp, err := c.GetContext(ctx, rawurl, query, ResourceGateway)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("request failed %d: %s", resp.StatusCode, b)
+	}
+
+	var result *GetArticleResponse
+	if err = json.Unmarshal(b, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Code != 200 {
+		return nil, errors.New(result.Message)
+	}
+	if result.Data == nil {
+		return nil, errors.NotFoundf("article %s", id)
+	}
+	return result.Data, nil
+}
+
+// DeleteArticle deletes an article or a draft, it is moved to the recycle bin unless deep is true
+func (c *Client) DeleteArticle(id string, deep bool) error {
+	return c.DeleteArticleContext(context.Background(), id, deep)
+}
+
+func (c *Client) DeleteArticleContext(ctx context.Context, id string, deep bool) error {
+	rawurl := c.BuildBizAPIURL("/blog-console-api/v1/article/del")
+	b, err := json.Marshal(map[string]interface{}{
+		"article_id": id,
+		"deep":       strconv.FormatBool(deep),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if ResourceGateway == nil {
+		if err = InitResourceGateway(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	resp, err := c.PostContext(ctx, rawurl, nil, bytes.NewReader(b), ResourceGateway)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	defer resp.Body.Close()
+	b, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("request failed %d: %s", resp.StatusCode, b)
+	}
+
+	var result *BaseResponse
+	if err = json.Unmarshal(b, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Code != 200 {
+		return errors.New(result.Message)
+	}
+	return nil
+}
+
+// BuildArticleURL returns the url of the article of the logged in user
+func (c *Client) BuildArticleURL(id string) string {
+	return fmt.Sprintf("https://blog.csdn.net/%s/article/details/%s", c.AuthInfo.Basic.ID, id)
+}
+
+func BuildDraftEditorURL(id string) string {
+	return fmt.Sprintf("https://editor.csdn.net/md?articleId=%s", id)
+}
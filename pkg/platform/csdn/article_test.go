@@ -1,16 +1,56 @@
 package csdn
 
 import (
-	"fmt"
-	"os"
+	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
+func TestSaveArticle(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := NewSaveArticleParams()
+	params.Title = "Title"
+	params.MarkdownContent = "# Title"
+	params.Content = "<h1>Title</h1>"
+	params.Type = SaveArticleTypeOriginal
+	params.ReadType = ReadTypePublic
+	params.PubStatus = PublishStatusPublish
+	params.SetTags([]string{"Go", "Linux"})
+	params.SetCategories([]string{"后端"})
+	err := client.SaveArticle(params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", params.ID)
+	assert.NotEqual(t, "", params.URL)
+
+	article, err := client.GetArticle(params.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", article.Title)
+	assert.Equal(t, "# Title", article.MarkdownContent)
+	assert.Equal(t, "Go,Linux", article.Tags)
+	assert.Equal(t, "后端", article.Categories)
+
+	params.Title = "New Title"
+	err = client.SaveArticle(params)
+	assert.Nil(t, err)
+	article, err = client.GetArticle(params.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "New Title", article.Title)
+}
+
 func TestListArticles(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_CSDN_COOKIE"))
-	if err != nil {
-		t.Error(err)
-		return
+	client, _ := newTestClient(t)
+
+	for i, title := range []string{"Go", "Docker", "Go Modules"} {
+		params := NewSaveArticleParams()
+		params.Title = title
+		params.MarkdownContent = title
+		if i == 1 {
+			params.Status = ArticleStatusDraft
+		}
+		assert.Nil(t, client.SaveArticle(params))
 	}
 
 	req := &ListArticlesRequest{
@@ -18,10 +58,84 @@ func TestListArticles(t *testing.T) {
 		PageSize: 20,
 	}
 	articles, count, err := client.ListArticles(req)
-	if err != nil {
-		t.Error(err)
-		return
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(articles))
+	assert.Equal(t, 3, count.All)
+	assert.Equal(t, 1, count.Draft)
+	assert.Equal(t, "Go Modules", articles[0].Title)
+
+	req = &ListArticlesRequest{Status: ListArticleStatusDraft}
+	articles, _, err = client.ListArticles(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(articles))
+	assert.Equal(t, "Docker", articles[0].Title)
+
+	req = &ListArticlesRequest{Keyword: "Go", PageSize: 1}
+	articles, err = client.ListAllArticles(req, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(articles))
+}
+
+func TestFindArticles(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := NewSaveArticleParams()
+	params.Title = "Title"
+	params.MarkdownContent = "# Title"
+	params.Content = "<h1>Title</h1>"
+	assert.Nil(t, client.SaveArticle(params))
+
+	p := &Publisher{Client: client}
+	articles, err := p.FindArticles(context.Background(), "Title")
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(articles)) {
+		assert.Equal(t, params.ID, articles[0].ID)
+		assert.Equal(t, "Title", articles[0].Title)
+		// The post time is in the time zone of csdn
+		assert.WithinDuration(t, time.Now(), articles[0].CreateTime, time.Minute)
 	}
-	fmt.Println(articles)
-	fmt.Println(count)
+}
+
+func TestDeleteArticle(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := NewSaveArticleParams()
+	params.Title = "Title"
+	params.MarkdownContent = "# Title"
+	assert.Nil(t, client.SaveArticle(params))
+
+	// The deleted article is moved to the recycle bin
+	err := client.DeleteArticle(params.ID, false)
+	assert.Nil(t, err)
+	articles, _, err := client.ListArticles(&ListArticlesRequest{Status: ListArticleStatusDeleted})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(articles))
+	_, err = client.GetArticle(params.ID)
+	assert.NotNil(t, err)
+
+	err = client.DeleteArticle(params.ID, true)
+	assert.Nil(t, err)
+	articles, _, err = client.ListArticles(&ListArticlesRequest{Status: ListArticleStatusDeleted})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(articles))
+}
+
+func TestListArticlesRequestIntoQuery(t *testing.T) {
+	req := &ListArticlesRequest{
+		Status:  ListArticleStatusDraft,
+		Year:    2022,
+		Month:   3,
+		Keyword: "go",
+	}
+	assert.Nil(t, req.Validate())
+	query := req.IntoQuery()
+	assert.Equal(t, "1", query.Get("page"))
+	assert.Equal(t, "20", query.Get("pageSize"))
+	assert.Equal(t, "draft", query.Get("status"))
+	assert.Equal(t, "2022", query.Get("year"))
+	assert.Equal(t, "03", query.Get("month"))
+	assert.Equal(t, "go", query.Get("keyword"))
+
+	req.Status = ListArticleStatusAll
+	assert.Equal(t, "", req.IntoQuery().Get("status"))
 }
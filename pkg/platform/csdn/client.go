@@ -1,14 +1,17 @@
 package csdn
 
 import (
+	"context"
 	"fmt"
-	browser "github.com/EDDYCJY/fake-useragent"
 	"github.com/juju/errors"
 	"github.com/k8scat/aliyun-api-gateway-sign-golang"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
 // csdn图床
@@ -33,21 +36,71 @@ const (
 	UserAppKey    = "203796071"
 	UserAppSecret = "i5rbx2z2ivnxzidzpfc0z021imsp2nec"
 
-	BizAPIBase = "https://bizapi.csdn.net"
+	BizAPIBase   = "https://bizapi.csdn.net"
+	ImageAPIBase = "https://imgservice.csdn.net"
 )
 
 type Client struct {
 	Cookie   string
 	AuthInfo *AuthInfo
+	BizAPI   string
+	ImageAPI string
+	// HTTP sends all the requests of the client, a random user agent of browsers is sent if the user agent is empty
+	transport.HTTP
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithBizAPI sets the base url of the bizapi which serves the articles and the user info
+func WithBizAPI(api string) Option {
+	return func(c *Client) {
+		c.BizAPI = strings.TrimSuffix(api, "/")
+	}
+}
+
+// WithImageAPI sets the base url of the image service which signs the image uploads
+func WithImageAPI(api string) Option {
+	return func(c *Client) {
+		c.ImageAPI = strings.TrimSuffix(api, "/")
+	}
+}
+
+// WithHTTP applies the http options shared by the clients of the platforms, e.g. transport.WithTimeout
+func WithHTTP(opts ...transport.HTTPOption) Option {
+	return func(c *Client) {
+		c.Apply(opts...)
+	}
 }
 
-func NewClient(cookie string) (*Client, error) {
+// WithOptions applies the http settings in opts, the base url overrides the bizapi and the image url
+// overrides the image service, the cookie in opts is passed to NewClient by the caller
+func WithOptions(opts *platform.Options) Option {
+	return func(c *Client) {
+		if opts.BaseURL != "" {
+			WithBizAPI(opts.BaseURL)(c)
+		}
+		if opts.ImageURL != "" {
+			WithImageAPI(opts.ImageURL)(c)
+		}
+		c.Apply(opts.HTTPOptions()...)
+	}
+}
+
+func NewClient(cookie string, opts ...Option) (*Client, error) {
 	if cookie == "" {
 		return nil, errors.New("cookie is required")
 	}
 	client := &Client{
-		Cookie: cookie,
+		Cookie:   cookie,
+		BizAPI:   BizAPIBase,
+		ImageAPI: ImageAPIBase,
+		HTTP:     transport.HTTP{HTTPClient: http.DefaultClient},
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	client.Wrap(PlatformName)
 
 	info, err := client.GetAuthInfo()
 	if err != nil {
@@ -58,23 +111,37 @@ func NewClient(cookie string) (*Client, error) {
 	return client, nil
 }
 
+// Request sends req with the cookie of the client, it is canceled by the context of req.
+// The request is signed by apiGateway again before every retry, as the gateway refuses a nonce seen before.
 func (c *Client) Request(req *http.Request, apiGateway ...*sign.APIGateway) (*http.Response, error) {
 	req.Header.Set("Cookie", c.Cookie)
-	req.Header.Set("User-Agent", browser.Computer())
+	req.Header.Set("User-Agent", c.BrowserUserAgent())
 
 	if len(apiGateway) > 0 {
-		if err := apiGateway[0].Sign(req); err != nil {
+		gateway := apiGateway[0]
+		signRequest := func(r *http.Request) error {
+			// The signature of the last attempt must not be signed as a header of the request
+			r.Header.Del(sign.HTTPHeaderCASignature)
+			r.Header.Del(sign.HTTPHeaderCASignatureHeaders)
+			return errors.Trace(gateway.Sign(r))
+		}
+		if err := signRequest(req); err != nil {
 			return nil, errors.Trace(err)
 		}
+		req = req.WithContext(transport.WithPrepare(req.Context(), signRequest))
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	err = errors.Trace(err)
 	return resp, err
 }
 
 func (c *Client) Get(rawurl string, query url.Values, apiGateway ...*sign.APIGateway) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	return c.GetContext(context.Background(), rawurl, query, apiGateway...)
+}
+
+func (c *Client) GetContext(ctx context.Context, rawurl string, query url.Values, apiGateway ...*sign.APIGateway) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -87,7 +154,11 @@ func (c *Client) Get(rawurl string, query url.Values, apiGateway ...*sign.APIGat
 }
 
 func (c *Client) Post(rawurl string, query url.Values, body io.Reader, apiGateway ...*sign.APIGateway) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, rawurl, body)
+	return c.PostContext(context.Background(), rawurl, query, body, apiGateway...)
+}
+
+func (c *Client) PostContext(ctx context.Context, rawurl string, query url.Values, body io.Reader, apiGateway ...*sign.APIGateway) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawurl, body)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -99,9 +170,9 @@ func (c *Client) Post(rawurl string, query url.Values, body io.Reader, apiGatewa
 	return resp, err
 }
 
-func BuildBizAPIURL(path string) string {
+func (c *Client) BuildBizAPIURL(path string) string {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	return fmt.Sprintf("%s%s", BizAPIBase, path)
+	return fmt.Sprintf("%s%s", c.BizAPI, path)
 }
@@ -1,26 +1,99 @@
 package csdn
 
 import (
-	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/url"
-	"os"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
-func TestRequest(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_CSDN_COOKIE"))
+// newTestClient returns a client of a fake csdn server which is closed when the test finishes
+func newTestClient(t *testing.T) (*Client, *fake.CSDN) {
+	server := fake.NewCSDN(map[string]string{
+		ResourceAppKey: ResourceAppSecret,
+		UserAppKey:     UserAppSecret,
+	})
+	t.Cleanup(server.Close)
+
+	// The fake server is not rate limited
+	client, err := NewClient(fake.Cookie, WithBizAPI(server.URL), WithImageAPI(server.URL), WithHTTP(transport.WithRateLimit(-1)))
 	if err != nil {
-		t.Error(err)
-		return
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func TestNewClient(t *testing.T) {
+	client, server := newTestClient(t)
+	assert.Equal(t, server.UserID, client.AuthInfo.Basic.ID)
+
+	_, err := NewClient("sessionid=invalid", WithBizAPI(server.URL))
+	assert.NotNil(t, err)
+}
+
+func TestRequest(t *testing.T) {
+	client, _ := newTestClient(t)
+	if ResourceGateway == nil {
+		assert.Nil(t, InitResourceGateway())
 	}
-	rawurl := "https://bizapi.csdn.net/blog-console-api/v3/editor/getArticle"
+
+	rawurl := client.BuildBizAPIURL("/blog-console-api/v1/article/list")
 	query := make(url.Values)
-	query.Set("id", "113740060")
-	query.Set("model_type", "")
+	query.Set("page", "1")
+	query.Set("keyword", "")
+
+	// The request without the signature of the api gateway is rejected
 	resp, err := client.Get(rawurl, query)
-	if err != nil {
-		t.Error(err)
-		return
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = client.Get(rawurl, query, ResourceGateway)
+	assert.Nil(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(b))
+}
+
+// failFirst sends the requests to the server but replies the first one with 503 Service Unavailable
+type failFirst struct {
+	sent bool
+}
+
+func (f *failFirst) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || f.sent {
+		return resp, err
+	}
+	f.sent = true
+	resp.Body.Close()
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestRequestRetry(t *testing.T) {
+	client, _ := newTestClient(t)
+	if ResourceGateway == nil {
+		assert.Nil(t, InitResourceGateway())
+	}
+	client.Apply(transport.WithTransport(&failFirst{}))
+	client.Wrap(PlatformName)
+
+	// The retry is signed again, as the server refuses the nonce of the first attempt
+	resp, err := client.Get(client.BuildBizAPIURL("/blog-console-api/v1/article/list"), nil, ResourceGateway)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, resp.Header.Get("X-Ca-Error-Message"))
 	}
-	fmt.Println(resp.StatusCode)
 }
@@ -1,8 +1,9 @@
 package csdn
 
 import (
+	"context"
 	"encoding/json"
-	browser "github.com/EDDYCJY/fake-useragent"
+	"fmt"
 	"github.com/juju/errors"
 	"io/ioutil"
 	"net/http"
@@ -27,14 +28,14 @@ type RequestUploadResponse struct {
 	BaseResponse
 }
 
-func (c *Client) requestUpload(filename string) (*UploadData, error) {
+func (c *Client) requestUpload(ctx context.Context, filename string) (*UploadData, error) {
 	ext := strings.TrimLeft(filepath.Ext(filename), ".")
 	if err := validateExt(ext); err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	rawurl := "https://imgservice.csdn.net/direct/v1.0/image/upload"
-	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	rawurl := fmt.Sprintf("%s/direct/v1.0/image/upload", c.ImageAPI)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -81,7 +82,11 @@ type UploadResponse struct {
 // UploadImage uploads image to Aliyun OSS.
 // 仅对文件名的后缀进行验证，不对真正的文件内容格式进行验证，所以可以将一个格式不支持的文件进行重命名即可上传
 func (c *Client) UploadImage(path string) (string, error) {
-	uploadData, err := c.requestUpload(path)
+	return c.UploadImageContext(context.Background(), path)
+}
+
+func (c *Client) UploadImageContext(ctx context.Context, path string) (string, error) {
+	uploadData, err := c.requestUpload(ctx, path)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -100,14 +105,14 @@ func (c *Client) UploadImage(path string) (string, error) {
 	}
 
 	rawurl := uploadData.Host
-	req, err := http.NewRequest(http.MethodPost, rawurl, buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawurl, buf)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("User-Agent", browser.Computer())
+	req.Header.Set("User-Agent", c.BrowserUserAgent())
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
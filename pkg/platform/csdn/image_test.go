@@ -1,24 +1,29 @@
 package csdn
 
 import (
-	"fmt"
-	"github.com/stretchr/testify/assert"
-	"os"
+	"io/ioutil"
+	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestUploadImage(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_CSDN_COOKIE"))
-	if err != nil {
-		t.Error(err)
-		return
-	}
+	client, _ := newTestClient(t)
+
+	path := "../../../images/go.png"
+	downloadURL, err := client.UploadImage(path)
+	assert.Nil(t, err)
+
+	resp, err := http.Get(downloadURL)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	want, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, want, b)
 
-	downloadURL, err := client.UploadImage("/Users/hsowan/Downloads/mmap.pNg")
-	if err != nil {
-		t.Error(err)
-		return
-	}
-	assert.NotEqual(t, "", downloadURL)
-	fmt.Println(downloadURL)
+	_, err = client.UploadImage("go.svg")
+	assert.NotNil(t, err)
 }
@@ -17,6 +17,8 @@ const (
 	MaxCategoryCount   = 3
 	MaxTagCount        = 5
 	MaxCoverImageCount = 3
+	// MaxDescriptionLength is the max length of the description, the longer one is truncated
+	MaxDescriptionLength = 256
 )
 
 // ParseMark parse mark to article params
@@ -65,8 +67,8 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 	if params.Description == "" {
 		params.Description = mark.Brief
 	}
-	if len([]rune(params.Description)) > 256 {
-		params.Description = string([]rune(params.Description)[:256])
+	if len([]rune(params.Description)) > MaxDescriptionLength {
+		params.Description = string([]rune(params.Description)[:MaxDescriptionLength])
 	}
 
 	categories := meta.GetStringSlice("categories")
@@ -156,7 +158,15 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 	return
 }
 
+// WriteBack update the csdn meta of mark and write it back to the markdown file
 func WriteBack(mark *markdown.Mark, params *SaveArticleParams, isCreate bool) error {
+	UpdateMeta(mark, params, isCreate)
+	err := mark.WriteFile(mark.File)
+	return errors.Trace(err)
+}
+
+// UpdateMeta update the csdn meta of mark without writing the markdown file
+func UpdateMeta(mark *markdown.Mark, params *SaveArticleParams, isCreate bool) {
 	now := time.Now().Format("2006-01-02 15:04:05")
 	v := mark.Meta.Get("csdn")
 	meta, _ := v.(markdown.Meta)
@@ -169,6 +179,56 @@ func WriteBack(mark *markdown.Mark, params *SaveArticleParams, isCreate bool) er
 		meta = meta.Set("article_id", params.ID)
 	}
 	mark.Meta = mark.Meta.Set("csdn", meta)
-	err := mark.WriteFile(mark.File)
-	return errors.Trace(err)
+}
+
+// NewMark converts an article into mark, the csdn meta is pre-filled with the article
+func NewMark(article *ArticleDetail) *markdown.Mark {
+	var meta markdown.Meta
+	if categories := splitList(article.Categories); len(categories) > 0 {
+		meta = meta.Set("categories", categories)
+	}
+	if tags := splitList(article.Tags); len(tags) > 0 {
+		meta = meta.Set("tags", tags)
+	}
+	if len(article.CoverImages) > 0 {
+		meta = meta.Set("cover_images", article.CoverImages)
+	}
+	if article.Description != "" {
+		meta = meta.Set("brief_content", article.Description)
+	}
+	if article.Status == ArticleStatusDraft {
+		meta = meta.Set("publish_status", string(PublishStatusDraft))
+	}
+	if article.ReadType != "" && article.ReadType != ReadTypePublic {
+		meta = meta.Set("read_type", string(article.ReadType))
+	}
+	if article.Type != "" && article.Type != SaveArticleTypeOriginal {
+		meta = meta.Set("article_type", string(article.Type))
+		if article.OriginalURL != "" {
+			meta = meta.Set("original_url", article.OriginalURL)
+		}
+	}
+	if article.AuthorizedStatus {
+		meta = meta.Set("authorized_status", true)
+	}
+	meta = meta.Set("article_id", article.ID)
+
+	var root markdown.Meta
+	root = root.Set("title", article.Title)
+	root = root.Set("csdn", meta)
+	return &markdown.Mark{
+		Meta:    root,
+		Content: markdown.EnsureNewline(article.MarkdownContent),
+		Brief:   article.Description,
+	}
+}
+
+func splitList(s string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
 }
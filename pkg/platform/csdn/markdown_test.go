@@ -0,0 +1,29 @@
+package csdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMark(t *testing.T) {
+	article := &ArticleDetail{
+		ID:              "1",
+		Title:           "Title",
+		MarkdownContent: "# Title\n",
+		Tags:            "Go, Linux,",
+		Categories:      "后端",
+		Type:            SaveArticleTypeReship,
+		OriginalURL:     "https://example.com",
+		Status:          ArticleStatusDraft,
+	}
+	mark := NewMark(article)
+	assert.Equal(t, "Title", mark.Meta.GetString("title"))
+	assert.Equal(t, []string{"Go", "Linux"}, mark.Meta.Get("csdn.tags"))
+	assert.Equal(t, []string{"后端"}, mark.Meta.Get("csdn.categories"))
+	assert.Equal(t, "repost", mark.Meta.GetString("csdn.article_type"))
+	assert.Equal(t, "https://example.com", mark.Meta.GetString("csdn.original_url"))
+	assert.Equal(t, "draft", mark.Meta.GetString("csdn.publish_status"))
+	assert.Equal(t, "1", mark.Meta.GetString("csdn.article_id"))
+	assert.Equal(t, "# Title\n", mark.Content)
+}
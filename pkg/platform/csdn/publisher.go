@@ -0,0 +1,125 @@
+package csdn
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+const PlatformName = "csdn"
+
+const (
+	// PostTimeLayout is the layout of the post time of the articles
+	PostTimeLayout = "2006-01-02 15:04:05"
+)
+
+// Location is the time zone of the post time of the articles
+var Location = time.FixedZone("CST", 8*60*60)
+
+func init() {
+	platform.Register(PlatformName, NewPublisher)
+}
+
+// Publisher implements platform.Publisher for csdn.net
+type Publisher struct {
+	Client *Client
+}
+
+var (
+	_ platform.Publisher     = (*Publisher)(nil)
+	_ platform.ImageUploader = (*Publisher)(nil)
+	_ platform.ArticleFinder = (*Publisher)(nil)
+)
+
+// NewPublisher creates a Publisher with the cookie and the settings in opts
+func NewPublisher(opts *platform.Options) (platform.Publisher, error) {
+	client, err := NewClient(opts.Cookie, WithOptions(opts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Publisher{Client: client}, nil
+}
+
+func (p *Publisher) Name() string {
+	return PlatformName
+}
+
+// Publish saves the article with the publish_status in the csdn meta
+func (p *Publisher) Publish(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMark(mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	return p.save(ctx, params, platform.SaveTypeArticle)
+}
+
+// SaveDraft saves the article as a draft, csdn drafts share the id with articles
+func (p *Publisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMark(mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	params.PubStatus = PublishStatusDraft
+	params.Status = ArticleStatusDraft
+	return p.save(ctx, params, platform.SaveTypeDraft)
+}
+
+func (p *Publisher) save(ctx context.Context, params *SaveArticleParams, saveType platform.SaveType) (*platform.Result, error) {
+	isCreate := params.ID == ""
+	if err := p.Client.SaveArticleContext(ctx, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &platform.Result{
+		Platform:  PlatformName,
+		SaveType:  saveType,
+		ArticleID: params.ID,
+		URL:       params.URL,
+		IsCreate:  isCreate,
+	}, nil
+}
+
+func (p *Publisher) DeleteArticle(ctx context.Context, id string) error {
+	return errors.Trace(p.Client.DeleteArticleContext(ctx, id, false))
+}
+
+func (p *Publisher) WriteBack(mark *markdown.Mark, result *platform.Result) error {
+	params := &SaveArticleParams{
+		ID: result.ArticleID,
+	}
+	UpdateMeta(mark, params, result.IsCreate)
+	return nil
+}
+
+// FindArticles returns the articles and the drafts, which share the ids with articles
+func (p *Publisher) FindArticles(ctx context.Context, keyword string) ([]*platform.Article, error) {
+	req := &ListArticlesRequest{
+		Status:  ListArticleStatusAll,
+		Keyword: keyword,
+	}
+	articles, _, err := p.Client.ListArticlesContext(ctx, req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*platform.Article, 0, len(articles))
+	for _, a := range articles {
+		article := &platform.Article{
+			ID:    a.ID,
+			Title: a.Title,
+			URL:   p.Client.BuildArticleURL(a.ID),
+		}
+		if t, err := time.ParseInLocation(PostTimeLayout, a.PostTime, Location); err == nil {
+			article.CreateTime = t
+		}
+		result = append(result, article)
+	}
+	return result, nil
+}
+
+func (p *Publisher) UploadImage(ctx context.Context, path string) (string, error) {
+	imageURL, err := p.Client.UploadImageContext(ctx, path)
+	return imageURL, errors.Trace(err)
+}
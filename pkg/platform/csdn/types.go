@@ -106,9 +106,44 @@ type ListArticlesResponse struct {
 	BaseResponse
 }
 
+type ArticleDetail struct {
+	ID               string          `json:"article_id"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	Content          string          `json:"content"`
+	MarkdownContent  string          `json:"markdowncontent"`
+	Tags             string          `json:"tags"`
+	Categories       string          `json:"categories"`
+	Type             SaveArticleType `json:"type"`
+	Status           ArticleStatus   `json:"status"`
+	ReadType         ReadType        `json:"read_type"`
+	OriginalURL      string          `json:"original_link"`
+	AuthorizedStatus bool            `json:"authorized_status"`
+	CoverImages      []string        `json:"cover_images"`
+	CoverType        CoverType       `json:"cover_type"`
+}
+
+type GetArticleResponse struct {
+	Data *ArticleDetail `json:"data"`
+	BaseResponse
+}
+
+// ListArticleStatus filters articles by status
+type ListArticleStatus string
+
+const (
+	ListArticleStatusAll     ListArticleStatus = "all"
+	ListArticleStatusEnable  ListArticleStatus = "enable"  // 全部可见
+	ListArticleStatusPrivate ListArticleStatus = "private" // 仅我可见
+	ListArticleStatusAudit   ListArticleStatus = "audit"   // 审核
+	ListArticleStatusDraft   ListArticleStatus = "draft"   // 草稿箱
+	ListArticleStatusDeleted ListArticleStatus = "deleted" // 回收站
+)
+
 type ListArticlesRequest struct {
 	Page        int
 	PageSize    int
+	Status      ListArticleStatus
 	ArticleType ListArticleType
 	ColumnID    int
 	Year        int
@@ -140,12 +175,18 @@ func (req *ListArticlesRequest) IntoQuery() url.Values {
 	query := make(url.Values)
 	query.Set("page", strconv.Itoa(req.Page))
 	query.Set("pageSize", strconv.Itoa(req.PageSize))
+	if req.Status != "" && req.Status != ListArticleStatusAll {
+		query.Set("status", string(req.Status))
+	}
 	if req.ArticleType != 0 {
 		query.Set("type", strconv.Itoa(int(req.ArticleType)))
 	}
 	if req.ColumnID != 0 {
 		query.Set("column", strconv.Itoa(req.ColumnID))
 	}
+	if req.Year > 0 {
+		query.Set("year", strconv.Itoa(req.Year))
+	}
 	month := req.GetMonth()
 	if month != "" {
 		query.Set("month", month)
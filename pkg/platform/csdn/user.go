@@ -1,6 +1,7 @@
 package csdn
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/juju/errors"
 	sign "github.com/k8scat/aliyun-api-gateway-sign-golang"
@@ -9,7 +10,11 @@ import (
 )
 
 func (c *Client) GetAuthInfo() (info *AuthInfo, err error) {
-	rawurl := BuildBizAPIURL("/community-personal/v1/get-personal-info")
+	return c.GetAuthInfoContext(context.Background())
+}
+
+func (c *Client) GetAuthInfoContext(ctx context.Context) (info *AuthInfo, err error) {
+	rawurl := c.BuildBizAPIURL("/community-personal/v1/get-personal-info")
 	var resp *http.Response
 	apiGateway := &sign.APIGateway{
 		Key:    UserAppKey,
@@ -22,7 +27,7 @@ func (c *Client) GetAuthInfo() (info *AuthInfo, err error) {
 		}
 	}
 
-	resp, err = c.Get(rawurl, nil, apiGateway, UserGateway)
+	resp, err = c.GetContext(ctx, rawurl, nil, apiGateway, UserGateway)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -0,0 +1,84 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+// Article is an article of the logged in user found in a platform
+type Article struct {
+	ID      string
+	DraftID string
+	Title   string
+	URL     string
+	// CreateTime is the time the article is created in the platform, zero if unknown
+	CreateTime time.Time
+}
+
+// ArticleFinder is implemented by the publishers of platforms which can search the articles by title
+type ArticleFinder interface {
+	// FindArticles returns the articles whose titles contain keyword
+	FindArticles(ctx context.Context, keyword string) ([]*Article, error)
+}
+
+// Title returns the title of mark published to the named platform
+func Title(mark *markdown.Mark, name string) string {
+	if title := mark.Meta.GetString(name + ".title"); title != "" {
+		return title
+	}
+	return mark.Meta.GetString("title")
+}
+
+// FindDuplicate returns the article with the same title in the platform of p, which is likely
+// created before but whose id is not written back. nil is returned if p is not an ArticleFinder.
+func FindDuplicate(ctx context.Context, p Publisher, title string) (*Article, error) {
+	a, err := FindCreated(ctx, p, title, time.Time{})
+	return a, errors.Trace(err)
+}
+
+// FindCreated returns the article with the same title created at or after since in the platform of p,
+// the articles whose create time is unknown are ignored unless since is zero.
+func FindCreated(ctx context.Context, p Publisher, title string, since time.Time) (*Article, error) {
+	finder, ok := p.(ArticleFinder)
+	title = strings.TrimSpace(title)
+	if !ok || title == "" {
+		return nil, nil
+	}
+	articles, err := finder.FindArticles(ctx, title)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, a := range articles {
+		if strings.TrimSpace(a.Title) != title {
+			continue
+		}
+		if since.IsZero() || !a.CreateTime.Before(since) {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+// CheckDuplicate returns an already exists error if mark has no article id of the platform of p
+// but an article with the same title is found, which is likely created by an interrupted publish.
+func CheckDuplicate(ctx context.Context, p Publisher, mark *markdown.Mark) error {
+	name := p.Name()
+	if mark.Meta.GetString(name+".article_id") != "" {
+		return nil
+	}
+	a, err := FindDuplicate(ctx, p, Title(mark, name))
+	if err != nil {
+		return errors.Annotate(err, "check duplicate failed")
+	}
+	if a == nil {
+		return nil
+	}
+	return errors.NewAlreadyExists(nil, fmt.Sprintf("%s article %q exists: %s, set %s.article_id: %q to update it",
+		name, a.Title, a.URL, name, a.ID))
+}
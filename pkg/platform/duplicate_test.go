@@ -0,0 +1,66 @@
+package platform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+type fakeFinder struct {
+	fakePublisher
+	articles []*Article
+}
+
+func (p *fakeFinder) FindArticles(ctx context.Context, keyword string) ([]*Article, error) {
+	return p.articles, nil
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	ctx := context.Background()
+	mark := &markdown.Mark{}
+	mark.Meta = mark.Meta.Set("title", "Go")
+	mark.Meta = mark.Meta.Set("fake", markdown.Meta{}.Set("title", "Go Modules"))
+	assert.Equal(t, "Go Modules", Title(mark, "fake"))
+
+	// The publishers which can not search the articles never find duplicates
+	assert.Nil(t, CheckDuplicate(ctx, &fakePublisher{}, mark))
+
+	p := &fakeFinder{articles: []*Article{
+		{ID: "1", Title: "Go Modules 101"},
+		{ID: "2", Title: "Go Modules", URL: "https://fake.com/2"},
+	}}
+	a, err := FindDuplicate(ctx, p, "Go Modules")
+	assert.Nil(t, err)
+	if assert.NotNil(t, a) {
+		assert.Equal(t, "2", a.ID)
+	}
+	a, err = FindDuplicate(ctx, p, "Go")
+	assert.Nil(t, err)
+	assert.Nil(t, a)
+
+	// The articles created before or whose create time is unknown are not found since a time
+	now := time.Now()
+	a, err = FindCreated(ctx, p, "Go Modules", now)
+	assert.Nil(t, err)
+	assert.Nil(t, a)
+	p.articles[1].CreateTime = now.Add(-time.Hour)
+	p.articles = append(p.articles, &Article{ID: "3", Title: "Go Modules", CreateTime: now})
+	a, err = FindCreated(ctx, p, "Go Modules", now)
+	assert.Nil(t, err)
+	if assert.NotNil(t, a) {
+		assert.Equal(t, "3", a.ID)
+	}
+
+	err = CheckDuplicate(ctx, p, mark)
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.Contains(t, err.Error(), "https://fake.com/2")
+
+	// The article is updated if the id is known
+	mark.Meta = mark.Meta.Set("fake", markdown.Meta{}.Set("article_id", "2"))
+	assert.Nil(t, CheckDuplicate(ctx, p, mark))
+}
@@ -0,0 +1,496 @@
+package fake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// csdnMaxClockSkew is the max difference between X-Ca-Timestamp and the time of the server,
+// which is the same as the aliyun api gateway
+const csdnMaxClockSkew = 15 * time.Minute
+
+// csdnLocation is the time zone of the times in the responses
+var csdnLocation = time.FixedZone("CST", 8*60*60)
+
+type csdnArticle struct {
+	ID              int64
+	Title           string
+	Description     string
+	Content         string
+	MarkdownContent string
+	Tags            string
+	Categories      string
+	Type            string
+	ReadType        string
+	Status          int
+	OriginalURL     string
+	CoverImages     []string
+	CoverType       int
+	Deleted         bool
+	PostTime        time.Time
+}
+
+// status returns the list status of the article
+func (a *csdnArticle) status() string {
+	switch {
+	case a.Deleted:
+		return "deleted"
+	case a.Status == 2:
+		return "draft"
+	case a.ReadType == "private":
+		return "private"
+	default:
+		return "enable"
+	}
+}
+
+// CSDN is a fake of the csdn bizapi and the image service, the requests to the bizapi must be
+// signed by the api gateway apps which are registered with their keys and secrets.
+type CSDN struct {
+	*httptest.Server
+
+	Cookie   string
+	UserID   string
+	Nickname string
+
+	mu       sync.Mutex
+	secrets  map[string]string
+	nonces   map[string]bool
+	ids      *ids
+	articles map[int64]*csdnArticle
+	images   map[string][]byte
+}
+
+// NewCSDN starts a fake csdn server which accepts Cookie and the requests signed by the apps,
+// secrets maps the app keys to the app secrets. It must be closed after use.
+func NewCSDN(secrets map[string]string) *CSDN {
+	s := &CSDN{
+		Cookie:   Cookie,
+		UserID:   "articli",
+		Nickname: "Articli",
+		secrets:  secrets,
+		nonces:   make(map[string]bool),
+		ids:      newIDs(120000000),
+		articles: make(map[int64]*csdnArticle),
+		images:   make(map[string][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/community-personal/v1/get-personal-info", s.signed(s.getPersonalInfo))
+	mux.HandleFunc("/blog-console-api/v1/article/list", s.signed(s.listArticles))
+	mux.HandleFunc("/blog-console-api/v3/mdeditor/saveArticle", s.signed(s.saveArticle))
+	mux.HandleFunc("/blog-console-api/v3/editor/getArticle", s.signed(s.getArticle))
+	mux.HandleFunc("/blog-console-api/v1/article/del", s.signed(s.deleteArticle))
+	mux.HandleFunc("/direct/v1.0/image/upload", s.requestUpload)
+	mux.HandleFunc("/oss", s.upload)
+	mux.HandleFunc("/img/", s.image)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *CSDN) fail(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code": code,
+		"msg":  msg,
+	})
+}
+
+func (s *CSDN) ok(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code": 200,
+		"msg":  "success",
+		"data": data,
+	})
+}
+
+// signed checks the cookie and the signature of the api gateway
+func (s *CSDN) signed(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != s.Cookie {
+			s.fail(w, http.StatusOK, 400, "请先登录")
+			return
+		}
+		if msg := s.verify(r); msg != "" {
+			w.Header().Set("X-Ca-Error-Message", msg)
+			s.fail(w, http.StatusBadRequest, 400, msg)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// verify returns the error message if the signature of the request is invalid or its nonce is used
+func (s *CSDN) verify(r *http.Request) string {
+	secret, ok := s.secrets[r.Header.Get("X-Ca-Key")]
+	if !ok {
+		return "Invalid AppKey"
+	}
+	ts, err := strconv.ParseInt(r.Header.Get("X-Ca-Timestamp"), 10, 64)
+	if err != nil {
+		return "Invalid Timestamp"
+	}
+	skew := time.Since(time.Unix(0, ts*int64(time.Millisecond)))
+	if skew > csdnMaxClockSkew || skew < -csdnMaxClockSkew {
+		return "Invalid Timestamp"
+	}
+	nonce := r.Header.Get("X-Ca-Nonce")
+	if nonce == "" {
+		return "Invalid Nonce"
+	}
+
+	signature := r.Header.Get("X-Ca-Signature")
+	if signature == "" || signature != csdnSignature(r, secret) {
+		return "Invalid Signature"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nonces[nonce] {
+		return "Nonce Used"
+	}
+	s.nonces[nonce] = true
+	return ""
+}
+
+// csdnSignature computes the signature of the aliyun api gateway:
+// https://help.aliyun.com/document_detail/29475.html
+func csdnSignature(r *http.Request, secret string) string {
+	var buf strings.Builder
+	buf.WriteString(strings.ToUpper(r.Method) + "\n")
+	for _, k := range []string{"Accept", "Content-MD5", "Content-Type", "Date"} {
+		buf.WriteString(r.Header.Get(k) + "\n")
+	}
+
+	headers := make([]string, 0)
+	for _, k := range strings.Split(r.Header.Get("X-Ca-Signature-Headers"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			headers = append(headers, http.CanonicalHeaderKey(k))
+		}
+	}
+	sort.Strings(headers)
+	for _, k := range headers {
+		buf.WriteString(k + ":" + r.Header.Get(k) + "\n")
+	}
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	buf.WriteString(r.URL.Path)
+	if len(keys) > 0 {
+		buf.WriteString("?")
+	}
+	for i, k := range keys {
+		buf.WriteString(k)
+		if v := query.Get(k); v != "" {
+			buf.WriteString("=" + v)
+		}
+		if i != len(keys)-1 {
+			buf.WriteString("&")
+		}
+	}
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(buf.String()))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (s *CSDN) getPersonalInfo(w http.ResponseWriter, _ *http.Request) {
+	s.ok(w, map[string]interface{}{
+		"basic": map[string]interface{}{
+			"id":       s.UserID,
+			"nickname": s.Nickname,
+		},
+		"general": map[string]interface{}{
+			"avatar": s.URL + "/img/avatar.png",
+		},
+	})
+}
+
+func (s *CSDN) listArticles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	status := query.Get("status")
+	keyword := query.Get("keyword")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := map[string]int{}
+	matched := make([]*csdnArticle, 0)
+	for _, a := range s.articles {
+		st := a.status()
+		count[st]++
+		if !a.Deleted {
+			count["all"]++
+		}
+		if status == "" && a.Deleted || status != "" && status != st {
+			continue
+		}
+		if !strings.Contains(a.Title, keyword) {
+			continue
+		}
+		matched = append(matched, a)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID > matched[j].ID
+	})
+	start := (page - 1) * pageSize
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	list := make([]interface{}, 0)
+	for _, a := range matched[start:end] {
+		coverImages := a.CoverImages
+		if coverImages == nil {
+			coverImages = []string{}
+		}
+		list = append(list, map[string]interface{}{
+			"ArticleId":  strconv.FormatInt(a.ID, 10),
+			"Title":      a.Title,
+			"PostTime":   a.PostTime.In(csdnLocation).Format("2006-01-02 15:04:05"),
+			"Status":     strconv.Itoa(a.Status),
+			"Type":       a.Type,
+			"UserName":   s.UserID,
+			"ViewCount":  "0",
+			"coverImage": coverImages,
+		})
+	}
+	s.ok(w, map[string]interface{}{
+		"count": map[string]int{
+			"all":     count["all"],
+			"enable":  count["enable"],
+			"private": count["private"],
+			"draft":   count["draft"],
+			"deleted": count["deleted"],
+		},
+		"list":        list,
+		"list_status": status,
+		"page":        page,
+		"size":        pageSize,
+		"total":       len(matched),
+	})
+}
+
+func (s *CSDN) saveArticle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID              string   `json:"id"`
+		Title           string   `json:"title"`
+		Description     string   `json:"Description"`
+		Content         string   `json:"content"`
+		MarkdownContent string   `json:"markdowncontent"`
+		Tags            string   `json:"tags"`
+		Categories      string   `json:"categories"`
+		Type            string   `json:"type"`
+		ReadType        string   `json:"readType"`
+		Status          int      `json:"status"`
+		OriginalURL     string   `json:"original_url"`
+		CoverImages     []string `json:"cover_images"`
+		CoverType       int      `json:"cover_type"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, http.StatusOK, 400, err.Error())
+		return
+	}
+	if req.Title == "" {
+		s.fail(w, http.StatusOK, 400, "标题不能为空")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var a *csdnArticle
+	if req.ID == "" {
+		id, _ := strconv.ParseInt(s.ids.Next(), 10, 64)
+		a = &csdnArticle{ID: id, PostTime: time.Now()}
+		s.articles[id] = a
+	} else {
+		id, _ := strconv.ParseInt(req.ID, 10, 64)
+		var ok bool
+		if a, ok = s.articles[id]; !ok || a.Deleted {
+			s.fail(w, http.StatusOK, 400, "文章不存在")
+			return
+		}
+	}
+	a.Title = req.Title
+	a.Description = req.Description
+	a.Content = req.Content
+	a.MarkdownContent = req.MarkdownContent
+	a.Tags = req.Tags
+	a.Categories = req.Categories
+	a.Type = req.Type
+	a.ReadType = req.ReadType
+	a.Status = req.Status
+	a.OriginalURL = req.OriginalURL
+	a.CoverImages = req.CoverImages
+	a.CoverType = req.CoverType
+	s.ok(w, map[string]interface{}{
+		"id":          a.ID,
+		"title":       a.Title,
+		"description": a.Description,
+		"url":         s.articleURL(a.ID),
+	})
+}
+
+func (s *CSDN) articleURL(id int64) string {
+	return s.URL + "/" + s.UserID + "/article/details/" + strconv.FormatInt(id, 10)
+}
+
+func (s *CSDN) getArticle(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.articles[id]
+	if !ok || a.Deleted {
+		s.fail(w, http.StatusOK, 400, "文章不存在")
+		return
+	}
+	coverImages := a.CoverImages
+	if coverImages == nil {
+		coverImages = []string{}
+	}
+	s.ok(w, map[string]interface{}{
+		"article_id":      strconv.FormatInt(a.ID, 10),
+		"title":           a.Title,
+		"description":     a.Description,
+		"content":         a.Content,
+		"markdowncontent": a.MarkdownContent,
+		"tags":            a.Tags,
+		"categories":      a.Categories,
+		"type":            a.Type,
+		"status":          a.Status,
+		"read_type":       a.ReadType,
+		"original_link":   a.OriginalURL,
+		"cover_images":    coverImages,
+		"cover_type":      a.CoverType,
+	})
+}
+
+func (s *CSDN) deleteArticle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ArticleID string `json:"article_id"`
+		Deep      string `json:"deep"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, http.StatusOK, 400, err.Error())
+		return
+	}
+	id, _ := strconv.ParseInt(req.ArticleID, 10, 64)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.articles[id]
+	if !ok {
+		s.fail(w, http.StatusOK, 400, "文章不存在")
+		return
+	}
+	if req.Deep == "true" || a.Deleted {
+		delete(s.articles, id)
+	} else {
+		a.Deleted = true
+	}
+	s.ok(w, nil)
+}
+
+func (s *CSDN) requestUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Cookie") != s.Cookie {
+		s.fail(w, http.StatusOK, 401, "请先登录")
+		return
+	}
+	switch strings.ToLower(r.Header.Get("X-Image-Suffix")) {
+	case "jpg", "jpeg", "png", "gif":
+	default:
+		s.fail(w, http.StatusOK, 400, "不支持的图片格式")
+		return
+	}
+
+	id := s.ids.Next()
+	s.ok(w, map[string]interface{}{
+		"accessId":    "LTAIfake",
+		"callbackUrl": base64.StdEncoding.EncodeToString([]byte(`{"callbackUrl":"` + s.URL + `/callback"}`)),
+		"dir":         "direct",
+		"expire":      strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+		"filePath":    "direct/" + id + "." + strings.ToLower(r.Header.Get("X-Image-Suffix")),
+		"host":        s.URL + "/oss",
+		"policy":      "policy-" + id,
+		"signature":   csdnOSSSignature("policy-" + id),
+	})
+}
+
+func csdnOSSSignature(policy string) string {
+	h := hmac.New(sha256.New, []byte("fake-oss-secret"))
+	h.Write([]byte(policy))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// upload mimics the aliyun oss post object api and the callback of csdn
+func (s *CSDN) upload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		s.fail(w, http.StatusBadRequest, 400, err.Error())
+		return
+	}
+	key := r.FormValue("key")
+	if key == "" || r.FormValue("OSSAccessKeyId") == "" ||
+		r.FormValue("signature") != csdnOSSSignature(r.FormValue("policy")) {
+		s.fail(w, http.StatusForbidden, 403, "SignatureDoesNotMatch")
+		return
+	}
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, 400, err.Error())
+		return
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, 400, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.images[key] = b
+	s.mu.Unlock()
+	s.ok(w, map[string]interface{}{
+		"imageUrl": s.URL + "/img/" + url.PathEscape(key),
+	})
+}
+
+func (s *CSDN) image(w http.ResponseWriter, r *http.Request) {
+	key, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/img/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	b, ok := s.images[key]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(b))
+	_, _ = w.Write(b)
+}
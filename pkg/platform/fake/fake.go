@@ -0,0 +1,60 @@
+// Package fake provides in-memory httptest servers which mimic the apis of the supported platforms,
+// so that the platform clients can be tested hermetically without network and real accounts.
+//
+// Every server keeps its state in memory and only implements the endpoints used by the clients,
+// requests without the fake credentials are rejected like the real platforms do.
+package fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Cookie is the cookie accepted by the fake juejin, oschina and csdn servers
+	Cookie = "sessionid=fake"
+	// Token is the token accepted by the fake github and gitlab servers
+	Token = "fake-token"
+)
+
+// ids generates increasing numeric ids, the platforms use numeric strings as ids
+type ids struct {
+	mu   sync.Mutex
+	next int64
+}
+
+func newIDs(start int64) *ids {
+	return &ids{next: start}
+}
+
+func (g *ids) Next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.next++
+	return strconv.FormatInt(g.next, 10)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,285 @@
+package fake
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const githubDefaultBranch = "main"
+
+// GitHub is a fake of the contents api of GitHub, every repository exists and is empty at first.
+type GitHub struct {
+	*httptest.Server
+
+	Token string
+	Login string
+
+	mu    sync.Mutex
+	files map[string][]byte // owner/repo@branch:path => content
+}
+
+// NewGitHub starts a fake github server which accepts Token, it must be closed after use
+func NewGitHub() *GitHub {
+	s := &GitHub{
+		Token: Token,
+		Login: "articli",
+		files: make(map[string][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", s.auth(s.getUser))
+	mux.HandleFunc("/repos/", s.auth(s.contents))
+	mux.HandleFunc("/raw/", s.raw)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// File returns the content of a file in the default branch of a repository
+func (s *GitHub) File(owner, repo, filePath string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.files[githubKey(owner, repo, githubDefaultBranch, filePath)]
+	return b, ok
+}
+
+func githubKey(owner, repo, branch, filePath string) string {
+	return fmt.Sprintf("%s/%s@%s:%s", owner, repo, branch, filePath)
+}
+
+// githubSHA returns the sha of the git blob object
+func githubSHA(b []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(b))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *GitHub) fail(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message":           msg,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+func (s *GitHub) auth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token "+s.Token {
+			s.fail(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *GitHub) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"login":      s.Login,
+		"id":         1000,
+		"avatar_url": s.URL + "/avatars/" + s.Login,
+		"html_url":   s.URL + "/" + s.Login,
+		"name":       s.Login,
+		"type":       "User",
+	})
+}
+
+func (s *GitHub) fileInfo(owner, repo, branch, filePath string, b []byte) map[string]interface{} {
+	api := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", s.URL, owner, repo, filePath, branch)
+	info := map[string]interface{}{
+		"name":     path.Base(filePath),
+		"path":     filePath,
+		"url":      api,
+		"html_url": fmt.Sprintf("%s/%s/%s/blob/%s/%s", s.URL, owner, repo, branch, filePath),
+	}
+	if b == nil {
+		info["type"] = "dir"
+		info["size"] = 0
+		info["sha"] = githubSHA([]byte(filePath))
+		return info
+	}
+	info["type"] = "file"
+	info["size"] = len(b)
+	info["sha"] = githubSHA(b)
+	info["download_url"] = fmt.Sprintf("%s/raw/%s/%s/%s/%s", s.URL, owner, repo, branch, filePath)
+	return info
+}
+
+// contents serves /repos/{owner}/{repo}/contents/{path}
+func (s *GitHub) contents(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/"), "/", 4)
+	if len(parts) < 3 || parts[2] != "contents" {
+		s.fail(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	owner, repo := parts[0], parts[1]
+	filePath := ""
+	if len(parts) == 4 {
+		filePath = strings.Trim(parts[3], "/")
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		branch := r.URL.Query().Get("ref")
+		if branch == "" {
+			branch = githubDefaultBranch
+		}
+		s.getContents(w, owner, repo, branch, filePath)
+	case http.MethodPut:
+		s.putFile(w, r, owner, repo, filePath)
+	case http.MethodDelete:
+		s.deleteFile(w, r, owner, repo, filePath)
+	default:
+		s.fail(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *GitHub) getContents(w http.ResponseWriter, owner, repo, branch, filePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.files[githubKey(owner, repo, branch, filePath)]; ok {
+		info := s.fileInfo(owner, repo, branch, filePath, b)
+		info["encoding"] = "base64"
+		info["content"] = base64.StdEncoding.EncodeToString(b)
+		writeJSON(w, http.StatusOK, info)
+		return
+	}
+
+	// List the direct children of the directory
+	prefix := githubKey(owner, repo, branch, "")
+	if filePath != "" {
+		prefix += filePath + "/"
+	}
+	children := make(map[string][]byte)
+	for k, b := range s.files {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(k, prefix)
+		if i := strings.Index(rest, "/"); i >= 0 {
+			children[rest[:i]] = nil
+		} else {
+			children[rest] = b
+		}
+	}
+	if len(children) == 0 {
+		s.fail(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	infos := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		infos = append(infos, s.fileInfo(owner, repo, branch, path.Join(filePath, name), children[name]))
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *GitHub) commit(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"sha":     githubSHA([]byte(message)),
+		"message": message,
+	}
+}
+
+func (s *GitHub) putFile(w http.ResponseWriter, r *http.Request, owner, repo, filePath string) {
+	var req struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		SHA     string `json:"sha"`
+		Branch  string `json:"branch"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	b, err := base64.StdEncoding.DecodeString(req.Content)
+	if err != nil || req.Message == "" || filePath == "" {
+		s.fail(w, http.StatusUnprocessableEntity, "Invalid request.")
+		return
+	}
+	if req.Branch == "" {
+		req.Branch = githubDefaultBranch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := githubKey(owner, repo, req.Branch, filePath)
+	status := http.StatusCreated
+	if old, ok := s.files[key]; ok {
+		if req.SHA == "" {
+			s.fail(w, http.StatusUnprocessableEntity, "Invalid request.\n\n\"sha\" wasn't supplied.")
+			return
+		}
+		if req.SHA != githubSHA(old) {
+			s.fail(w, http.StatusConflict, fmt.Sprintf("%s does not match %s", filePath, req.SHA))
+			return
+		}
+		status = http.StatusOK
+	}
+	s.files[key] = b
+	writeJSON(w, status, map[string]interface{}{
+		"content": s.fileInfo(owner, repo, req.Branch, filePath, b),
+		"commit":  s.commit(req.Message),
+	})
+}
+
+func (s *GitHub) deleteFile(w http.ResponseWriter, r *http.Request, owner, repo, filePath string) {
+	var req struct {
+		Message string `json:"message"`
+		SHA     string `json:"sha"`
+		Branch  string `json:"branch"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if req.Branch == "" {
+		req.Branch = githubDefaultBranch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := githubKey(owner, repo, req.Branch, filePath)
+	old, ok := s.files[key]
+	if !ok {
+		s.fail(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if req.SHA != githubSHA(old) {
+		s.fail(w, http.StatusConflict, fmt.Sprintf("%s does not match %s", filePath, req.SHA))
+		return
+	}
+	delete(s.files, key)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content": nil,
+		"commit":  s.commit(req.Message),
+	})
+}
+
+// raw serves /raw/{owner}/{repo}/{branch}/{path} like raw.githubusercontent.com
+func (s *GitHub) raw(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/raw/"), "/", 4)
+	if len(parts) != 4 {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	b, ok := s.files[githubKey(parts[0], parts[1], parts[2], parts[3])]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(b))
+	_, _ = w.Write(b)
+}
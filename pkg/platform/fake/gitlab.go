@@ -0,0 +1,359 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const gitlabDefaultBranch = "main"
+
+// GitLabProject is a project of the fake gitlab server
+type GitLabProject struct {
+	ID                int
+	PathWithNamespace string
+	Visibility        string
+	DefaultBranch     string
+
+	files map[string][]byte // branch:path => content
+}
+
+// GitLab is a fake of the v4 api of GitLab, which serves the user, the projects and the repository files.
+type GitLab struct {
+	*httptest.Server
+
+	Token    string
+	Username string
+
+	mu       sync.Mutex
+	nextID   int
+	projects []*GitLabProject
+}
+
+// NewGitLab starts a fake gitlab server which accepts Token, it must be closed after use
+func NewGitLab() *GitLab {
+	s := &GitLab{
+		Token:    Token,
+		Username: "articli",
+		nextID:   15000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", s.auth(s.getUser))
+	mux.HandleFunc("/api/v4/projects/", s.auth(s.project))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddProject creates an empty project with the path with namespace, e.g. group/project
+func (s *GitLab) AddProject(pathWithNamespace, visibility string) *GitLabProject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	p := &GitLabProject{
+		ID:                s.nextID,
+		PathWithNamespace: pathWithNamespace,
+		Visibility:        visibility,
+		DefaultBranch:     gitlabDefaultBranch,
+		files:             make(map[string][]byte),
+	}
+	s.projects = append(s.projects, p)
+	return p
+}
+
+// AddFile creates or replaces a file in the branch of the project
+func (s *GitLab) AddFile(p *GitLabProject, branch, filePath string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.files[branch+":"+filePath] = content
+}
+
+// File returns the content of a file in the branch of the project
+func (s *GitLab) File(p *GitLabProject, branch, filePath string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := p.files[branch+":"+filePath]
+	return b, ok
+}
+
+func (s *GitLab) fail(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message": msg,
+	})
+}
+
+func (s *GitLab) auth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("PRIVATE-TOKEN")
+		if token == "" {
+			token = r.URL.Query().Get("private_token")
+		}
+		if token != s.Token {
+			s.fail(w, http.StatusUnauthorized, "401 Unauthorized")
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *GitLab) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":       1000,
+		"username": s.Username,
+		"name":     s.Username,
+		"email":    s.Username + "@example.com",
+		"state":    "active",
+	})
+}
+
+// findProject finds a project by the id or the path with namespace
+func (s *GitLab) findProject(id string) *GitLabProject {
+	for _, p := range s.projects {
+		if strconv.Itoa(p.ID) == id || p.PathWithNamespace == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *GitLab) projectJSON(p *GitLabProject) map[string]interface{} {
+	name := path.Base(p.PathWithNamespace)
+	return map[string]interface{}{
+		"id":                  p.ID,
+		"default_branch":      p.DefaultBranch,
+		"visibility":          p.Visibility,
+		"path":                name,
+		"path_with_namespace": p.PathWithNamespace,
+		"name":                name,
+		"name_with_namespace": strings.Replace(p.PathWithNamespace, "/", " / ", -1),
+	}
+}
+
+// project serves /api/v4/projects/{id} and the repository apis of the project,
+// the id and the file path are url encoded segments
+func (s *GitLab) project(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/"), "/")
+	for i, seg := range segments {
+		v, err := url.PathUnescape(seg)
+		if err != nil {
+			s.fail(w, http.StatusBadRequest, "400 Bad Request")
+			return
+		}
+		segments[i] = v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findProject(segments[0])
+	if p == nil {
+		s.fail(w, http.StatusNotFound, "404 Project Not Found")
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.projectJSON(p))
+	case len(segments) == 3 && segments[1] == "repository" && segments[2] == "tree":
+		s.listTree(w, r, p)
+	case len(segments) == 4 && segments[1] == "repository" && segments[2] == "files":
+		s.file(w, r, p, segments[3])
+	case len(segments) == 5 && segments[1] == "repository" && segments[2] == "files" && segments[4] == "raw":
+		s.rawFile(w, r, p, segments[3])
+	default:
+		s.fail(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *GitLab) file(w http.ResponseWriter, r *http.Request, p *GitLabProject, filePath string) {
+	if r.Method == http.MethodGet {
+		s.getFile(w, r, p, filePath)
+		return
+	}
+
+	var req struct {
+		Branch        string `json:"branch"`
+		Content       string `json:"content"`
+		Encoding      string `json:"encoding"`
+		CommitMessage string `json:"commit_message"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Branch == "" {
+		s.fail(w, http.StatusBadRequest, "branch is missing")
+		return
+	}
+	if req.CommitMessage == "" {
+		s.fail(w, http.StatusBadRequest, "commit_message is missing")
+		return
+	}
+	content := []byte(req.Content)
+	if req.Encoding == "base64" {
+		var err error
+		if content, err = base64.StdEncoding.DecodeString(req.Content); err != nil {
+			s.fail(w, http.StatusBadRequest, "content is invalid")
+			return
+		}
+	}
+
+	key := req.Branch + ":" + filePath
+	_, exists := p.files[key]
+	switch r.Method {
+	case http.MethodPost:
+		if exists {
+			s.fail(w, http.StatusBadRequest, "A file with this name already exists")
+			return
+		}
+		p.files[key] = content
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"file_path": filePath,
+			"branch":    req.Branch,
+		})
+	case http.MethodPut:
+		if !exists {
+			s.fail(w, http.StatusBadRequest, "A file with this name doesn't exist")
+			return
+		}
+		p.files[key] = content
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"file_path": filePath,
+			"branch":    req.Branch,
+		})
+	case http.MethodDelete:
+		if !exists {
+			s.fail(w, http.StatusBadRequest, "A file with this name doesn't exist")
+			return
+		}
+		delete(p.files, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.fail(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+	}
+}
+
+func (s *GitLab) getFile(w http.ResponseWriter, r *http.Request, p *GitLabProject, filePath string) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "ref is missing"})
+		return
+	}
+	b, ok := p.files[ref+":"+filePath]
+	if !ok {
+		s.fail(w, http.StatusNotFound, "404 File Not Found")
+		return
+	}
+	sum := sha256.Sum256(b)
+	blobID := githubSHA(b)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"file_name":      path.Base(filePath),
+		"file_path":      filePath,
+		"size":           len(b),
+		"encoding":       "base64",
+		"content":        base64.StdEncoding.EncodeToString(b),
+		"content_sha256": hex.EncodeToString(sum[:]),
+		"ref":            ref,
+		"blob_id":        blobID,
+		"commit_id":      blobID,
+		"last_commit_id": blobID,
+	})
+}
+
+func (s *GitLab) rawFile(w http.ResponseWriter, r *http.Request, p *GitLabProject, filePath string) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		ref = p.DefaultBranch
+	}
+	b, ok := p.files[ref+":"+filePath]
+	if !ok {
+		s.fail(w, http.StatusNotFound, "404 File Not Found")
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(b))
+	_, _ = w.Write(b)
+}
+
+func (s *GitLab) listTree(w http.ResponseWriter, r *http.Request, p *GitLabProject) {
+	query := r.URL.Query()
+	ref := query.Get("ref")
+	if ref == "" {
+		ref = p.DefaultBranch
+	}
+	dir := strings.Trim(query.Get("path"), "/")
+	recursive := query.Get("recursive") == "true"
+
+	nodes := make(map[string]string) // path => type
+	for k := range p.files {
+		if !strings.HasPrefix(k, ref+":") {
+			continue
+		}
+		filePath := strings.TrimPrefix(k, ref+":")
+		rest := filePath
+		if dir != "" {
+			if !strings.HasPrefix(filePath, dir+"/") {
+				continue
+			}
+			rest = strings.TrimPrefix(filePath, dir+"/")
+		}
+		parts := strings.Split(rest, "/")
+		if !recursive {
+			parts = parts[:1]
+		}
+		for i := range parts {
+			nodePath := path.Join(dir, strings.Join(parts[:i+1], "/"))
+			if nodePath == filePath {
+				nodes[nodePath] = "blob"
+			} else {
+				nodes[nodePath] = "tree"
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(nodes))
+	for nodePath := range nodes {
+		paths = append(paths, nodePath)
+	}
+	sort.Strings(paths)
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	if perPage <= 0 {
+		perPage = 20
+	}
+	start := (page - 1) * perPage
+	if start > len(paths) {
+		start = len(paths)
+	}
+	end := start + perPage
+	if end > len(paths) {
+		end = len(paths)
+	}
+	result := make([]interface{}, 0)
+	for _, nodePath := range paths[start:end] {
+		mode := "100644"
+		if nodes[nodePath] == "tree" {
+			mode = "040000"
+		}
+		result = append(result, map[string]interface{}{
+			"id":   githubSHA([]byte(nodePath)),
+			"name": path.Base(nodePath),
+			"type": nodes[nodePath],
+			"path": nodePath,
+			"mode": mode,
+		})
+	}
+	if end < len(paths) {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
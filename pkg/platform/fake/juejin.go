@@ -0,0 +1,690 @@
+package fake
+
+import (
+	"encoding/hex"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	juejinStorePrefix = "tos-cn-i-k3u1fbpfcp"
+
+	juejinAccessKeyID  = "AKLTfake"
+	juejinSecretKey    = "fake-secret"
+	juejinSessionToken = "STSfake"
+)
+
+// JuejinCategory is a category of the fake juejin server
+type JuejinCategory struct {
+	ID   string
+	Name string
+}
+
+// JuejinTag is a tag of the fake juejin server
+type JuejinTag struct {
+	ID   string
+	Name string
+}
+
+type juejinDraft struct {
+	ID          string
+	ArticleID   string
+	Title       string
+	Brief       string
+	Content     string
+	CoverImage  string
+	CategoryID  string
+	TagIDs      []string
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	PublishTime time.Time
+}
+
+type juejinUpload struct {
+	storeURI string
+	auth     string
+	data     []byte
+}
+
+// Juejin is a fake of the juejin api, including the imagex api and the upload host of the images.
+type Juejin struct {
+	*httptest.Server
+
+	Cookie   string
+	UserID   string
+	UserName string
+
+	// TagPageSize is the number of tags returned by a page of query_tag_list
+	TagPageSize int
+
+	mu         sync.Mutex
+	ids        *ids
+	categories []*JuejinCategory
+	tags       []*JuejinTag
+	drafts     map[string]*juejinDraft
+	articles   map[string]string // article id => draft id
+	uploads    map[string]*juejinUpload
+	images     map[string][]byte
+}
+
+// NewJuejin starts a fake juejin server which accepts Cookie, it must be closed after use
+func NewJuejin() *Juejin {
+	s := &Juejin{
+		Cookie:      Cookie,
+		UserID:      "1000",
+		UserName:    "articli",
+		TagPageSize: 20,
+		ids:         newIDs(7000000000000000000),
+		categories: []*JuejinCategory{
+			{ID: "6809637769959178254", Name: "后端"},
+			{ID: "6809637767543259144", Name: "前端"},
+			{ID: "6809635626879549454", Name: "开发工具"},
+		},
+		tags: []*JuejinTag{
+			{ID: "6809640364677267469", Name: "Go"},
+			{ID: "6809640357354012685", Name: "Docker"},
+			{ID: "6809640385980137480", Name: "Linux"},
+			{ID: "6809640407484334093", Name: "程序员"},
+		},
+		drafts:   make(map[string]*juejinDraft),
+		articles: make(map[string]string),
+		uploads:  make(map[string]*juejinUpload),
+		images:   make(map[string][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user_api/v1/user/get", s.auth(s.getUser))
+	mux.HandleFunc("/tag_api/v1/query_category_list", s.auth(s.listCategories))
+	mux.HandleFunc("/tag_api/v1/query_tag_list", s.auth(s.listTags))
+	mux.HandleFunc("/content_api/v1/article_draft/create", s.auth(s.saveDraft))
+	mux.HandleFunc("/content_api/v1/article_draft/update", s.auth(s.saveDraft))
+	mux.HandleFunc("/content_api/v1/article_draft/detail", s.auth(s.getDraft))
+	mux.HandleFunc("/content_api/v1/article_draft/delete", s.auth(s.deleteDraft))
+	mux.HandleFunc("/content_api/v1/article_draft/list_by_user", s.auth(s.listDrafts))
+	mux.HandleFunc("/content_api/v1/article_draft/query_list", s.auth(s.listDrafts))
+	mux.HandleFunc("/content_api/v1/article/publish", s.auth(s.publishArticle))
+	mux.HandleFunc("/content_api/v1/article/detail", s.auth(s.getArticle))
+	mux.HandleFunc("/content_api/v1/article/delete", s.auth(s.deleteArticle))
+	mux.HandleFunc("/content_api/v1/article/list_by_user", s.auth(s.listArticles))
+	mux.HandleFunc("/imagex/gen_token", s.auth(s.genToken))
+	mux.HandleFunc("/imagex/get_img_url", s.auth(s.getImageURL))
+	mux.HandleFunc("/", s.imagex)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Image returns the content of an uploaded image by the store uri
+func (s *Juejin) Image(uri string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.images[uri]
+	return b, ok
+}
+
+func (s *Juejin) fail(w http.ResponseWriter, errNo int, msg string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"err_no":  errNo,
+		"err_msg": msg,
+	})
+}
+
+func (s *Juejin) ok(w http.ResponseWriter, data interface{}, extra ...map[string]interface{}) {
+	resp := map[string]interface{}{
+		"err_no":  0,
+		"err_msg": "success",
+		"data":    data,
+	}
+	for _, m := range extra {
+		for k, v := range m {
+			resp[k] = v
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Juejin) auth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != s.Cookie {
+			s.fail(w, 403, "must login")
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *Juejin) getUser(w http.ResponseWriter, _ *http.Request) {
+	s.ok(w, map[string]interface{}{
+		"user_id":   s.UserID,
+		"user_name": s.UserName,
+		"level":     1,
+	})
+}
+
+func (s *Juejin) categoryJSON(id string) map[string]interface{} {
+	for _, c := range s.categories {
+		if c.ID == id {
+			return map[string]interface{}{
+				"category_id":   c.ID,
+				"category_name": c.Name,
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Juejin) tagJSON(t *JuejinTag) map[string]interface{} {
+	id, _ := strconv.Atoi(t.ID)
+	return map[string]interface{}{
+		"id":       id,
+		"tag_id":   t.ID,
+		"tag_name": t.Name,
+	}
+}
+
+func (s *Juejin) listCategories(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := make([]interface{}, 0, len(s.categories))
+	for _, c := range s.categories {
+		data = append(data, map[string]interface{}{
+			"category_id": c.ID,
+			"category":    s.categoryJSON(c.ID),
+		})
+	}
+	s.ok(w, data)
+}
+
+func (s *Juejin) listTags(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		KeyWord string `json:"key_word"`
+		Cursor  string `json:"cursor"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matched := make([]*JuejinTag, 0)
+	for _, t := range s.tags {
+		if strings.Contains(strings.ToLower(t.Name), strings.ToLower(req.KeyWord)) {
+			matched = append(matched, t)
+		}
+	}
+	start, _ := strconv.Atoi(req.Cursor)
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + s.TagPageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	data := make([]interface{}, 0)
+	for _, t := range matched[start:end] {
+		data = append(data, map[string]interface{}{
+			"tag_id": t.ID,
+			"tag":    s.tagJSON(t),
+		})
+	}
+	s.ok(w, data, map[string]interface{}{
+		"cursor":   strconv.Itoa(end),
+		"has_more": end < len(matched),
+		"count":    len(matched),
+	})
+}
+
+func (s *Juejin) saveDraft(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID           string   `json:"id"`
+		Title        string   `json:"title"`
+		MarkContent  string   `json:"mark_content"`
+		BriefContent string   `json:"brief_content"`
+		CoverImage   string   `json:"cover_image"`
+		CategoryID   string   `json:"category_id"`
+		TagIDs       []string `json:"tag_ids"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	create := strings.HasSuffix(r.URL.Path, "/create")
+	var d *juejinDraft
+	if create {
+		d = &juejinDraft{ID: s.ids.Next(), CreateTime: now}
+		s.drafts[d.ID] = d
+	} else {
+		var ok bool
+		if d, ok = s.drafts[req.ID]; !ok {
+			s.fail(w, 404, "draft not found")
+			return
+		}
+	}
+	d.Title = req.Title
+	d.Content = req.MarkContent
+	d.Brief = req.BriefContent
+	d.CoverImage = req.CoverImage
+	d.TagIDs = req.TagIDs
+	if req.CategoryID != "" {
+		d.CategoryID = req.CategoryID
+	}
+	d.ModifyTime = now
+	s.ok(w, s.draftJSON(d))
+}
+
+func (s *Juejin) draftJSON(d *juejinDraft) map[string]interface{} {
+	tagIDs := make([]int64, 0, len(d.TagIDs))
+	for _, id := range d.TagIDs {
+		i, _ := strconv.ParseInt(id, 10, 64)
+		tagIDs = append(tagIDs, i)
+	}
+	return map[string]interface{}{
+		"id":            d.ID,
+		"article_id":    d.ArticleID,
+		"title":         d.Title,
+		"mark_content":  d.Content,
+		"brief_content": d.Brief,
+		"cover_image":   d.CoverImage,
+		"category_id":   d.CategoryID,
+		"tag_ids":       tagIDs,
+		"user_id":       s.UserID,
+		"ctime":         unix(d.CreateTime),
+		"mtime":         unix(d.ModifyTime),
+	}
+}
+
+func (s *Juejin) tagsJSON(ids []string) []interface{} {
+	tags := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		for _, t := range s.tags {
+			if t.ID == id {
+				tags = append(tags, s.tagJSON(t))
+			}
+		}
+	}
+	return tags
+}
+
+func (s *Juejin) getDraft(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DraftID string `json:"draft_id"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.drafts[req.DraftID]
+	if !ok {
+		s.fail(w, 404, "draft not found")
+		return
+	}
+	s.ok(w, map[string]interface{}{
+		"article_draft": s.draftJSON(d),
+		"category":      s.categoryJSON(d.CategoryID),
+		"tags":          s.tagsJSON(d.TagIDs),
+	})
+}
+
+func (s *Juejin) deleteDraft(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DraftID string `json:"draft_id"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.drafts[req.DraftID]; !ok {
+		s.fail(w, 404, "draft not found")
+		return
+	}
+	delete(s.drafts, req.DraftID)
+	s.ok(w, nil)
+}
+
+type juejinPage struct {
+	Keyword  string `json:"keyword"`
+	PageNo   int    `json:"page_no"`
+	PageSize int    `json:"page_size"`
+}
+
+// bounds returns the bounds of the page in a list of size n
+func (p *juejinPage) bounds(n int) (int, int) {
+	if p.PageNo < 1 {
+		p.PageNo = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = 10
+	}
+	start := (p.PageNo - 1) * p.PageSize
+	if start > n {
+		start = n
+	}
+	end := start + p.PageSize
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func (s *Juejin) listDrafts(w http.ResponseWriter, r *http.Request) {
+	var req juejinPage
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	drafts := make([]*juejinDraft, 0)
+	for _, d := range s.drafts {
+		if strings.Contains(d.Title, req.Keyword) {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].ID > drafts[j].ID
+	})
+	start, end := req.bounds(len(drafts))
+	data := make([]interface{}, 0)
+	for _, d := range drafts[start:end] {
+		data = append(data, s.draftJSON(d))
+	}
+	s.ok(w, data, map[string]interface{}{"count": len(drafts)})
+}
+
+func (s *Juejin) publishArticle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DraftID string `json:"draft_id"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.drafts[req.DraftID]
+	if !ok {
+		s.fail(w, 404, "draft not found")
+		return
+	}
+	if d.Title == "" || d.CategoryID == "" || len(d.TagIDs) == 0 {
+		s.fail(w, 1, "title, category and tags are required")
+		return
+	}
+	if d.ArticleID == "" {
+		d.ArticleID = s.ids.Next()
+		d.PublishTime = time.Now()
+		s.articles[d.ArticleID] = d.ID
+	}
+	s.ok(w, map[string]interface{}{
+		"article_id": d.ArticleID,
+		"draft_id":   d.ID,
+	})
+}
+
+func (s *Juejin) articleJSON(id string) map[string]interface{} {
+	d := s.drafts[s.articles[id]]
+	return map[string]interface{}{
+		"article_id": id,
+		"article_info": map[string]interface{}{
+			"article_id":    id,
+			"draft_id":      d.ID,
+			"title":         d.Title,
+			"mark_content":  d.Content,
+			"brief_content": d.Brief,
+			"cover_image":   d.CoverImage,
+			"category_id":   d.CategoryID,
+			"audit_status":  2,
+			"user_id":       s.UserID,
+			"ctime":         unix(d.PublishTime),
+			"mtime":         unix(d.ModifyTime),
+		},
+		"category": s.categoryJSON(d.CategoryID),
+		"tags":     s.tagsJSON(d.TagIDs),
+	}
+}
+
+func (s *Juejin) getArticle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ArticleID string `json:"article_id"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.articles[req.ArticleID]; !ok {
+		s.fail(w, 404, "article not found")
+		return
+	}
+	s.ok(w, s.articleJSON(req.ArticleID))
+}
+
+func (s *Juejin) deleteArticle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ArticleID string `json:"article_id"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	draftID, ok := s.articles[req.ArticleID]
+	if !ok {
+		s.fail(w, 404, "article not found")
+		return
+	}
+	delete(s.articles, req.ArticleID)
+	delete(s.drafts, draftID)
+	s.ok(w, nil)
+}
+
+func (s *Juejin) listArticles(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		juejinPage
+		AuditStatus *int `json:"audit_status"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		s.fail(w, 2, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0)
+	for id, draftID := range s.articles {
+		// All the articles of the fake server are published
+		if req.AuditStatus != nil && *req.AuditStatus != 2 {
+			continue
+		}
+		if strings.Contains(s.drafts[draftID].Title, req.Keyword) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	start, end := req.bounds(len(ids))
+	data := make([]interface{}, 0)
+	for _, id := range ids[start:end] {
+		data = append(data, s.articleJSON(id))
+	}
+	s.ok(w, data, map[string]interface{}{"count": len(ids)})
+}
+
+func (s *Juejin) genToken(w http.ResponseWriter, _ *http.Request) {
+	s.ok(w, map[string]interface{}{
+		"token": map[string]interface{}{
+			"AccessKeyID":     juejinAccessKeyID,
+			"SecretAccessKey": juejinSecretKey,
+			"SessionToken":    juejinSessionToken,
+		},
+	})
+}
+
+func (s *Juejin) getImageURL(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get("uri")
+	s.mu.Lock()
+	_, ok := s.images[uri]
+	s.mu.Unlock()
+	if !ok {
+		s.fail(w, 404, "image not found")
+		return
+	}
+	s.ok(w, map[string]interface{}{
+		"main_url":   s.URL + "/" + uri,
+		"backup_url": s.URL + "/" + uri,
+	})
+}
+
+func (s *Juejin) imagexError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"ResponseMetadata": map[string]interface{}{
+			"Error": map[string]interface{}{
+				"Code":    code,
+				"Message": msg,
+			},
+		},
+	})
+}
+
+// imagex serves the imagex api, the upload host and the uploaded images
+func (s *Juejin) imagex(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/" && r.URL.Query().Get("Action") != "":
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+juejinAccessKeyID+"/") ||
+			r.Header.Get("X-Amz-Security-Token") != juejinSessionToken {
+			s.imagexError(w, http.StatusUnauthorized, "InvalidAuthorization", "invalid authorization")
+			return
+		}
+		switch r.URL.Query().Get("Action") {
+		case "ApplyImageUpload":
+			s.applyImageUpload(w, r)
+		case "CommitImageUpload":
+			s.commitImageUpload(w, r)
+		default:
+			s.imagexError(w, http.StatusBadRequest, "InvalidAction", "invalid action")
+		}
+	case strings.HasPrefix(r.URL.Path, "/"+juejinStorePrefix+"/"):
+		if r.Method == http.MethodPost {
+			s.upload(w, r)
+			return
+		}
+		s.mu.Lock()
+		b, ok := s.images[strings.TrimPrefix(r.URL.Path, "/")]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", http.DetectContentType(b))
+		_, _ = w.Write(b)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Juejin) applyImageUpload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.ids.Next()
+	upload := &juejinUpload{
+		storeURI: juejinStorePrefix + "/" + id,
+		auth:     "SpaceKey/k3u1fbpfcp/" + id,
+	}
+	sessionKey := "session-" + id
+	s.uploads[sessionKey] = upload
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Result": map[string]interface{}{
+			"UploadAddress": map[string]interface{}{
+				"StoreInfos": []interface{}{
+					map[string]interface{}{
+						"StoreUri": upload.storeURI,
+						"Auth":     upload.auth,
+					},
+				},
+				"UploadHosts": []string{r.Host},
+				"SessionKey":  sessionKey,
+			},
+		},
+	})
+}
+
+func (s *Juejin) upload(w http.ResponseWriter, r *http.Request) {
+	storeURI := strings.TrimPrefix(r.URL.Path, "/")
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": -1, "error": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var upload *juejinUpload
+	for _, u := range s.uploads {
+		if u.storeURI == storeURI {
+			upload = u
+		}
+	}
+	if upload == nil || r.Header.Get("Authorization") != upload.auth {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"success": -1, "error": "invalid authorization"})
+		return
+	}
+	sum := crc32.ChecksumIEEE(b)
+	crc := hex.EncodeToString([]byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)})
+	if r.Header.Get("Content-Crc32") != crc {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": -1, "error": "crc32 mismatch"})
+		return
+	}
+	upload.data = b
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": 0})
+}
+
+func (s *Juejin) commitImageUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.imagexError(w, http.StatusMethodNotAllowed, "InvalidMethod", "method not allowed")
+		return
+	}
+	sessionKey := r.URL.Query().Get("SessionKey")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[sessionKey]
+	if !ok || upload.data == nil {
+		s.imagexError(w, http.StatusBadRequest, "InvalidSessionKey", "invalid session key")
+		return
+	}
+	delete(s.uploads, sessionKey)
+	s.images[upload.storeURI] = upload.data
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Result": map[string]interface{}{
+			"Results": []interface{}{
+				map[string]interface{}{
+					"Uri":       upload.storeURI,
+					"UriStatus": 2000,
+				},
+			},
+		},
+	})
+}
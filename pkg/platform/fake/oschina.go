@@ -0,0 +1,410 @@
+package fake
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const oschinaPageSize = 20
+
+// OSChinaCategory is a category or a technical field of the fake oschina server
+type OSChinaCategory struct {
+	ID   string
+	Name string
+}
+
+type oschinaBlog struct {
+	ID          string
+	Title       string
+	Content     string
+	Category    string
+	Groups      string
+	OriginalURL string
+	Privacy     string
+	Top         string
+	DenyComment string
+	Download    string
+	Type        string
+}
+
+var oschinaTemplate = template.Must(template.New("home").Parse(`<!DOCTYPE html>
+<html><head><title>OSCHINA</title></head><body>
+{{if .Login}}<val data-name="g_user_url" data-value="{{.BaseURL}}"></val>
+<val data-name="g_user_name" data-value="{{.UserName}}"></val>
+<val data-name="g_user_code" data-value="{{.UserCode}}"></val>
+<val data-name="g_user_id" data-value="{{.UserID}}"></val>{{end}}
+</body></html>
+{{define "space"}}<!DOCTYPE html>
+<html><body><val data-name="space_user_id" data-value="{{.}}"></val></body></html>
+{{end}}
+{{define "blogs"}}<div class="ui relaxed divided items list-container space-list-container">
+{{range .Blogs}}<div class="item"><div class="content"><a class="header" href="{{$.BaseURL}}/blog/{{.ID}}">
+  {{.Title}}
+</a></div></div>
+{{end}}</div>
+{{if .HasNext}}<p class="pagination"><a class="pagination__next" href="?p={{.Next}}">下一页</a></p>{{end}}
+{{end}}
+{{define "drafts"}}<!DOCTYPE html>
+<html><body><div class="ui relaxed divided items list-container">
+{{range .Blogs}}<div class="item"><a class="header" href="{{$.BaseURL}}/blog/write/draft/{{.ID}}">{{.Title}}</a></div>
+{{end}}</div>
+{{if .HasNext}}<div class="ui pagination menu "><a class="item next-item" href="?p={{.Next}}">下一页</a></div>{{end}}
+</body></html>
+{{end}}
+{{define "write"}}<!DOCTYPE html>
+<html><body>
+{{with .Blog}}<form class="ui write-article form">
+<input type="text" name="title" value="{{.Title}}">
+<textarea name="body">{{.Content}}</textarea>
+<input type="text" name="origin_url" value="{{.OriginalURL}}">
+<input type="checkbox" name="privacy" value="{{.Privacy}}">
+<input type="checkbox" name="as_top" value="{{.Top}}">
+<input type="checkbox" name="deny_comment" value="{{.DenyComment}}">
+<input type="checkbox" name="downloadImg" value="{{.Download}}">
+<input type="radio" name="type" value="1" {{if ne .Type "4"}}checked{{end}}>
+<input type="radio" name="type" value="4" {{if eq .Type "4"}}checked{{end}}>
+</form>{{end}}
+<select id="catalogDropdown">{{range .Categories}}<option value="{{.ID}}" {{if eq .ID $.Selected}}selected{{end}}>{{.Name}}</option>{{end}}</select>
+<div class="inline fields write-card-field-bt"><div class="menu">{{range .Fields}}<div class="item" data-value="{{.ID}}">{{.Name}}</div>{{end}}</div></div>
+</body></html>
+{{end}}`))
+
+// OSChina is a fake of the oschina website, the pages only contain the elements parsed by the client.
+type OSChina struct {
+	*httptest.Server
+
+	Cookie   string
+	UserID   string
+	UserName string
+	UserCode string
+	SpaceID  string
+
+	mu         sync.Mutex
+	ids        *ids
+	categories []*OSChinaCategory
+	fields     []*OSChinaCategory
+	drafts     map[string]*oschinaBlog
+	articles   map[string]*oschinaBlog
+}
+
+// NewOSChina starts a fake oschina server which accepts Cookie, it must be closed after use
+func NewOSChina() *OSChina {
+	s := &OSChina{
+		Cookie:   Cookie,
+		UserID:   "1000",
+		UserName: "articli",
+		UserCode: "fakeUserCode",
+		SpaceID:  "1000",
+		ids:      newIDs(5000000),
+		categories: []*OSChinaCategory{
+			{ID: "7000001", Name: "工作日志"},
+			{ID: "7000002", Name: "日常记录"},
+		},
+		fields: []*OSChinaCategory{
+			{ID: "1", Name: "大前端"},
+			{ID: "2", Name: "后端"},
+			{ID: "3", Name: "云计算"},
+		},
+		drafts:   make(map[string]*oschinaBlog),
+		articles: make(map[string]*oschinaBlog),
+	}
+
+	base := s.basePath()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.home)
+	mux.HandleFunc(base, s.auth(s.space))
+	mux.HandleFunc(base+"/widgets/_space_index_newest_blog", s.auth(s.listArticles))
+	mux.HandleFunc(base+"/admin/drafts", s.auth(s.listDrafts))
+	mux.HandleFunc(base+"/blog/write", s.auth(s.write))
+	mux.HandleFunc(base+"/blog/write/draft/", s.auth(s.write))
+	mux.HandleFunc(base+"/blog/write/edit/", s.auth(s.write))
+	mux.HandleFunc(base+"/blog/save_draft", s.post(s.saveDraft))
+	mux.HandleFunc(base+"/blog/delete_draft", s.post(s.deleteDraft))
+	mux.HandleFunc(base+"/blog/save", s.post(s.saveArticle))
+	mux.HandleFunc(base+"/blog/edit", s.post(s.editArticle))
+	mux.HandleFunc(base+"/blog/delete", s.post(s.deleteArticle))
+	mux.HandleFunc(base+"/blog/quick_add_blog_catalog", s.post(s.addCategory))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// BaseURL returns the url of the user space
+func (s *OSChina) BaseURL() string {
+	return s.URL + s.basePath()
+}
+
+func (s *OSChina) basePath() string {
+	return "/u/" + s.UserID
+}
+
+func (s *OSChina) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := oschinaTemplate.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *OSChina) fail(w http.ResponseWriter, msg string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":    0,
+		"message": msg,
+	})
+}
+
+func (s *OSChina) ok(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":    1,
+		"message": "success",
+		"result":  result,
+	})
+}
+
+func (s *OSChina) login(r *http.Request) bool {
+	return r.Header.Get("Cookie") == s.Cookie
+}
+
+func (s *OSChina) auth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.login(r) {
+			http.Redirect(w, r, s.URL+"/home/login", http.StatusFound)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// post checks the login status and the user code of the forms
+func (s *OSChina) post(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !s.login(r) {
+			s.fail(w, "请先登录")
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			s.fail(w, err.Error())
+			return
+		}
+		if r.PostForm.Get("user_code") != s.UserCode {
+			s.fail(w, "非法请求")
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *OSChina) home(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, "home", map[string]interface{}{
+		"Login":    s.login(r),
+		"BaseURL":  s.BaseURL(),
+		"UserName": s.UserName,
+		"UserCode": s.UserCode,
+		"UserID":   s.UserID,
+	})
+}
+
+func (s *OSChina) space(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.basePath() {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, "space", s.SpaceID)
+}
+
+// page returns the blogs of the page p sorted by id desc, and whether there is a next page
+func (s *OSChina) page(blogs map[string]*oschinaBlog, keyword string, p int) ([]*oschinaBlog, bool) {
+	result := make([]*oschinaBlog, 0)
+	for _, b := range blogs {
+		if strings.Contains(b.Title, keyword) {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID > result[j].ID
+	})
+	if p < 1 {
+		p = 1
+	}
+	start := (p - 1) * oschinaPageSize
+	if start > len(result) {
+		start = len(result)
+	}
+	end := start + oschinaPageSize
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[start:end], end < len(result)
+}
+
+func (s *OSChina) listArticles(w http.ResponseWriter, r *http.Request) {
+	p, _ := strconv.Atoi(r.URL.Query().Get("p"))
+	s.mu.Lock()
+	blogs, hasNext := s.page(s.articles, r.URL.Query().Get("q"), p)
+	s.mu.Unlock()
+	s.render(w, "blogs", map[string]interface{}{
+		"BaseURL": s.BaseURL(),
+		"Blogs":   blogs,
+		"HasNext": hasNext,
+		"Next":    p + 1,
+	})
+}
+
+func (s *OSChina) listDrafts(w http.ResponseWriter, r *http.Request) {
+	p, _ := strconv.Atoi(r.URL.Query().Get("p"))
+	s.mu.Lock()
+	blogs, hasNext := s.page(s.drafts, "", p)
+	s.mu.Unlock()
+	s.render(w, "drafts", map[string]interface{}{
+		"BaseURL": s.BaseURL(),
+		"Blogs":   blogs,
+		"HasNext": hasNext,
+		"Next":    p + 1,
+	})
+}
+
+// write renders the editor page, which edits a draft or an article if the id is in the path
+func (s *OSChina) write(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var blog *oschinaBlog
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	switch {
+	case strings.Contains(r.URL.Path, "/blog/write/draft/"):
+		blog = s.drafts[id]
+	case strings.Contains(r.URL.Path, "/blog/write/edit/"):
+		blog = s.articles[id]
+	}
+	if blog == nil && r.URL.Path != s.basePath()+"/blog/write" {
+		http.NotFound(w, r)
+		return
+	}
+	selected := ""
+	if blog != nil {
+		selected = blog.Category
+	}
+	s.render(w, "write", map[string]interface{}{
+		"Blog":       blog,
+		"Categories": s.categories,
+		"Selected":   selected,
+		"Fields":     s.fields,
+	})
+}
+
+func newOSChinaBlog(id string, r *http.Request) *oschinaBlog {
+	flag := func(key string) string {
+		if r.PostForm.Get(key) == "1" {
+			return "1"
+		}
+		return "0"
+	}
+	return &oschinaBlog{
+		ID:          id,
+		Title:       r.PostForm.Get("title"),
+		Content:     r.PostForm.Get("content"),
+		Category:    r.PostForm.Get("catalog"),
+		Groups:      r.PostForm.Get("groups"),
+		OriginalURL: r.PostForm.Get("origin_url"),
+		Privacy:     flag("privacy"),
+		Top:         flag("as_top"),
+		DenyComment: flag("deny_comment"),
+		Download:    flag("downloadImg"),
+		Type:        r.PostForm.Get("type"),
+	}
+}
+
+func (s *OSChina) saveDraft(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PostForm.Get("draft")
+	if id == "" {
+		id = s.ids.Next()
+	} else if _, ok := s.drafts[id]; !ok {
+		s.fail(w, "草稿不存在")
+		return
+	}
+	s.drafts[id] = newOSChinaBlog(id, r)
+	s.ok(w, map[string]interface{}{"draft": id})
+}
+
+func (s *OSChina) deleteDraft(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PostForm.Get("id")
+	if _, ok := s.drafts[id]; !ok {
+		s.fail(w, "草稿不存在")
+		return
+	}
+	delete(s.drafts, id)
+	s.ok(w, nil)
+}
+
+// saveArticle publishes a draft as a new article
+func (s *OSChina) saveArticle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	draftID := r.PostForm.Get("draft")
+	if _, ok := s.drafts[draftID]; !ok {
+		s.fail(w, "草稿不存在")
+		return
+	}
+	delete(s.drafts, draftID)
+	id := s.ids.Next()
+	s.articles[id] = newOSChinaBlog(id, r)
+	s.ok(w, map[string]interface{}{"id": id})
+}
+
+func (s *OSChina) editArticle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PostForm.Get("id")
+	if _, ok := s.articles[id]; !ok {
+		s.fail(w, "博客不存在")
+		return
+	}
+	s.articles[id] = newOSChinaBlog(id, r)
+	s.ok(w, map[string]interface{}{"id": id})
+}
+
+func (s *OSChina) deleteArticle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PostForm.Get("id")
+	if _, ok := s.articles[id]; !ok {
+		s.fail(w, "博客不存在")
+		return
+	}
+	delete(s.articles, id)
+	s.ok(w, nil)
+}
+
+func (s *OSChina) addCategory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.PostForm.Get("space") != s.SpaceID {
+		s.fail(w, "非法请求")
+		return
+	}
+	name := strings.TrimSpace(r.PostForm.Get("name"))
+	for _, c := range s.categories {
+		if c.Name == name {
+			s.fail(w, "分类已存在")
+			return
+		}
+	}
+	c := &OSChinaCategory{ID: s.ids.Next(), Name: name}
+	s.categories = append(s.categories, c)
+	s.ok(w, map[string]interface{}{"id": c.ID, "name": c.Name})
+}
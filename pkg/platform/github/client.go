@@ -2,6 +2,7 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/juju/errors"
@@ -9,9 +10,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
 const (
+	// PlatformName is the key of the rate limit and the metrics of the client
+	PlatformName   = "github"
 	DefaultBaseAPI = "https://api.github.com"
 )
 
@@ -19,9 +25,39 @@ type Client struct {
 	Token   string
 	User    *User
 	BaseAPI string
+	// HTTP sends all the requests of the client, the user agent is sent if not empty
+	transport.HTTP
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithBaseAPI sets the base url of the api, e.g. the api of a GitHub Enterprise Server
+func WithBaseAPI(api string) Option {
+	return func(c *Client) {
+		c.BaseAPI = strings.TrimSuffix(api, "/")
+	}
+}
+
+// WithHTTP applies the http options shared by the clients of the platforms, e.g. transport.WithTimeout
+func WithHTTP(opts ...transport.HTTPOption) Option {
+	return func(c *Client) {
+		c.Apply(opts...)
+	}
 }
 
-func NewClient(token string) (*Client, error) {
+// WithOptions applies the http settings in opts, the base url overrides the base api,
+// the token in opts is passed to NewClient by the caller
+func WithOptions(opts *platform.Options) Option {
+	return func(c *Client) {
+		if opts.BaseURL != "" {
+			WithBaseAPI(opts.BaseURL)(c)
+		}
+		c.Apply(opts.HTTPOptions()...)
+	}
+}
+
+func NewClient(token string, opts ...Option) (*Client, error) {
 	token = strings.TrimSpace(token)
 	if token == "" {
 		return nil, errors.New("token is required")
@@ -29,7 +65,12 @@ func NewClient(token string) (*Client, error) {
 	client := &Client{
 		Token:   token,
 		BaseAPI: DefaultBaseAPI,
+		HTTP:    transport.HTTP{HTTPClient: http.DefaultClient},
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.Wrap(PlatformName)
 	var err error
 	client.User, err = client.GetAuthenticatedUser()
 	if err != nil {
@@ -39,6 +80,10 @@ func NewClient(token string) (*Client, error) {
 }
 
 func (c *Client) Request(method, path string, body interface{}, query url.Values) (*http.Response, error) {
+	return c.RequestContext(context.Background(), method, path, body, query)
+}
+
+func (c *Client) RequestContext(ctx context.Context, method, path string, body interface{}, query url.Values) (*http.Response, error) {
 	var r io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -52,7 +97,7 @@ func (c *Client) Request(method, path string, body interface{}, query url.Values
 		path = fmt.Sprintf("/%s", path)
 	}
 	rawurl := fmt.Sprintf("%s%s", c.BaseAPI, path)
-	req, err := http.NewRequest(method, rawurl, r)
+	req, err := http.NewRequestWithContext(ctx, method, rawurl, r)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -60,10 +105,13 @@ func (c *Client) Request(method, path string, body interface{}, query url.Values
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("token %s", c.Token))
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
 	if query != nil {
 		req.URL.RawQuery = query.Encode()
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	return resp, errors.Trace(err)
 }
@@ -1,12 +1,61 @@
 package github
 
 import (
-	"github.com/stretchr/testify/assert"
-	"os"
+	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
+// newTestClient returns a client of a fake github server which is closed when the test finishes
+func newTestClient(t *testing.T) (*Client, *fake.GitHub) {
+	server := fake.NewGitHub()
+	t.Cleanup(server.Close)
+
+	// The fake server is not rate limited
+	client, err := NewClient(fake.Token, WithBaseAPI(server.URL), WithHTTP(transport.WithRateLimit(-1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
 func TestNewClient(t *testing.T) {
-	_, err := NewClient(os.Getenv("ARTICLI_GITHUB_TOKEN"))
-	assert.Nil(t, err)
+	client, server := newTestClient(t)
+	assert.Equal(t, server.Login, client.User.GetUsername())
+
+	_, err := NewClient("invalid", WithBaseAPI(server.URL))
+	assert.NotNil(t, err)
+}
+
+func TestClientWithOptions(t *testing.T) {
+	server := fake.NewGitHub()
+	t.Cleanup(server.Close)
+
+	var userAgent string
+	server.Config.Handler = wrapHandler(server.Config.Handler, func(r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	})
+	opts := &platform.Options{
+		Token:     fake.Token,
+		BaseURL:   server.URL,
+		UserAgent: "articli-enterprise",
+	}
+	client, err := NewClient(opts.Token, WithOptions(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, server.URL, client.BaseAPI)
+	assert.Equal(t, "articli-enterprise", userAgent)
+}
+
+func wrapHandler(h http.Handler, f func(r *http.Request)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f(r)
+		h.ServeHTTP(w, r)
+	})
 }
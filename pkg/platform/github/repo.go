@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -109,6 +110,10 @@ type UploadFileResponse struct {
 // UploadFile Creates a new file or replaces an existing file in a repository.
 // https://docs.github.com/en/rest/reference/repos#create-or-update-file-contents
 func (c *Client) UploadFile(owner, repo, path string, req *UploadFileRequest) (*UploadFileResponse, error) {
+	return c.UploadFileContext(context.Background(), owner, repo, path, req)
+}
+
+func (c *Client) UploadFileContext(ctx context.Context, owner, repo, path string, req *UploadFileRequest) (*UploadFileResponse, error) {
 	if owner == "" {
 		return nil, errors.New("owner is required")
 	}
@@ -131,9 +136,10 @@ func (c *Client) UploadFile(owner, repo, path string, req *UploadFileRequest) (*
 		}
 	}
 
+	filePath := path
 	path = fmt.Sprintf("/repos/%s/%s/contents/%s", owner, repo, path)
 
-	resp, err := c.Request(http.MethodPut, path, req, nil)
+	resp, err := c.RequestContext(ctx, http.MethodPut, path, req, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -143,6 +149,10 @@ func (c *Client) UploadFile(owner, repo, path string, req *UploadFileRequest) (*
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The sha of the existing file is required to replace it
+	if resp.StatusCode == http.StatusUnprocessableEntity && req.SHA == "" && strings.Contains(string(b), `\"sha\"`) {
+		return nil, errors.AlreadyExistsf("file %s", filePath)
+	}
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
 		return nil, errors.Errorf("unexpected status code %d, body: %s", resp.StatusCode, b)
 	}
@@ -155,6 +165,10 @@ func (c *Client) UploadFile(owner, repo, path string, req *UploadFileRequest) (*
 // DeleteFile Deletes a file in a repository.
 // https://docs.github.com/en/rest/reference/repos#delete-a-file
 func (c *Client) DeleteFile(owner, repo, path string, req *DeleteFileRequest) error {
+	return c.DeleteFileContext(context.Background(), owner, repo, path, req)
+}
+
+func (c *Client) DeleteFileContext(ctx context.Context, owner, repo, path string, req *DeleteFileRequest) error {
 	if owner == "" {
 		return errors.New("owner is required")
 	}
@@ -172,7 +186,7 @@ func (c *Client) DeleteFile(owner, repo, path string, req *DeleteFileRequest) er
 		return errors.Trace(err)
 	}
 	path = fmt.Sprintf("/repos/%s/%s/contents/%s", owner, repo, path)
-	resp, err := c.Request(http.MethodDelete, path, req, nil)
+	resp, err := c.RequestContext(ctx, http.MethodDelete, path, req, nil)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -202,6 +216,10 @@ func (f *FileInfo) GetHumanSize() string {
 }
 
 func (c *Client) GetContent(owner, repo, path string, refs ...string) ([]*FileInfo, error) {
+	return c.GetContentContext(context.Background(), owner, repo, path, refs...)
+}
+
+func (c *Client) GetContentContext(ctx context.Context, owner, repo, path string, refs ...string) ([]*FileInfo, error) {
 	if owner == "" {
 		return nil, errors.New("owner is required")
 	}
@@ -217,7 +235,7 @@ func (c *Client) GetContent(owner, repo, path string, refs ...string) ([]*FileIn
 
 	// 注意: path 后面需要带上 /
 	path = fmt.Sprintf("/repos/%s/%s/contents/%s/", owner, repo, path)
-	resp, err := c.Request(http.MethodGet, path, nil, query)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, query)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -251,7 +269,11 @@ func (c *Client) GetContent(owner, repo, path string, refs ...string) ([]*FileIn
 }
 
 func (c *Client) GetFile(owner, repo, path string, refs ...string) (f *FileInfo, isDir bool, err error) {
-	files, err := c.GetContent(owner, repo, path)
+	return c.GetFileContext(context.Background(), owner, repo, path, refs...)
+}
+
+func (c *Client) GetFileContext(ctx context.Context, owner, repo, path string, refs ...string) (f *FileInfo, isDir bool, err error) {
+	files, err := c.GetContentContext(ctx, owner, repo, path, refs...)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -3,19 +3,18 @@ package github
 import (
 	"encoding/base64"
 	"fmt"
-	"github.com/stretchr/testify/assert"
 	"io/ioutil"
-	"os"
 	"testing"
 	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
 )
 
+const testImage = "../../../images/go.png"
+
 func TestUploadFile(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_GITHUB_TOKEN"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, server := newTestClient(t)
 
 	cases := []struct {
 		owner string
@@ -29,7 +28,7 @@ func TestUploadFile(t *testing.T) {
 			path:  fmt.Sprintf("testdir/%d.png", time.Now().Unix()),
 			req: &UploadFileRequest{
 				Message: fmt.Sprintf("new file uploaded at %s", time.Now().Format("2006-01-02 15:04:05")),
-				Path:    "./images/go.png",
+				Path:    testImage,
 			},
 		},
 		{
@@ -38,23 +37,39 @@ func TestUploadFile(t *testing.T) {
 			path:  fmt.Sprintf("testdir/%d.md", time.Now().Unix()),
 			req: &UploadFileRequest{
 				Message: fmt.Sprintf("update file1 at %s", time.Now().Format("2006-01-02 15:04:05")),
-				Path:    "./images/go.png",
+				Path:    testImage,
 			},
 		},
 	}
 
+	want, err := ioutil.ReadFile(testImage)
+	assert.Nil(t, err)
 	for _, c := range cases {
-		_, err := client.UploadFile(c.owner, c.repo, c.path, c.req)
+		resp, err := client.UploadFile(c.owner, c.repo, c.path, c.req)
 		assert.Nil(t, err)
+		assert.Equal(t, c.path, resp.Content.Path)
+
+		b, ok := server.File(c.owner, c.repo, c.path)
+		assert.True(t, ok)
+		assert.Equal(t, want, b)
+
+		// The existing file is not replaced without its sha
+		_, err = client.UploadFile(c.owner, c.repo, c.path, c.req)
+		assert.True(t, errors.IsAlreadyExists(err))
 	}
+
+	fileInfos, err := client.GetContent("k8scat", "testrepo", "testdir")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fileInfos))
+
+	f, isDir, err := client.GetFile("k8scat", "testrepo", "testdir")
+	assert.Nil(t, err)
+	assert.True(t, isDir)
+	assert.Equal(t, ContentTypeFile, f.Type)
 }
 
 func TestUpdateFile(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_GITHUB_TOKEN"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
 	cases := []struct {
 		owner string
@@ -69,8 +84,7 @@ func TestUpdateFile(t *testing.T) {
 			req: &UploadFileRequest{
 				Message: fmt.Sprintf("new file uploaded at %s", time.Now().Format("2006-01-02 15:04:05")),
 				Content: func() string {
-					filename := "./images/go.png"
-					b, err := ioutil.ReadFile(filename)
+					b, err := ioutil.ReadFile(testImage)
 					if err != nil {
 						return ""
 					}
@@ -84,20 +98,17 @@ func TestUpdateFile(t *testing.T) {
 			path:  "testdir2/file2",
 			req: &UploadFileRequest{
 				Message: fmt.Sprintf("update file at %s", time.Now().Format("2006-01-02 15:04:05")),
-				Content: func() string {
-					filename := "./images/go.png"
-					b, err := ioutil.ReadFile(filename)
-					if err != nil {
-						return ""
-					}
-					return base64.StdEncoding.EncodeToString(b)
-				}(),
+				Content: base64.StdEncoding.EncodeToString([]byte("updated")),
 			},
 		},
 	}
 
 	for i, c := range cases {
 		if i == 1 {
+			// The file can not be updated without the sha
+			_, err := client.UploadFile(c.owner, c.repo, c.path, c.req)
+			assert.NotNil(t, err)
+
 			c.req.SHA = func() string {
 				fileInfos, err := client.GetContent(c.owner, c.repo, c.path)
 				if err != nil {
@@ -112,4 +123,33 @@ func TestUpdateFile(t *testing.T) {
 		_, err := client.UploadFile(c.owner, c.repo, c.path, c.req)
 		assert.Nil(t, err)
 	}
+
+	f, isDir, err := client.GetFile("k8scat", "testrepo", "testdir2/file2")
+	assert.Nil(t, err)
+	assert.False(t, isDir)
+	assert.Equal(t, 7, f.Size)
+}
+
+func TestDeleteFile(t *testing.T) {
+	client, server := newTestClient(t)
+
+	resp, err := client.UploadFile("k8scat", "testrepo", "file", &UploadFileRequest{
+		Message: "add file",
+		Content: base64.StdEncoding.EncodeToString([]byte("content")),
+	})
+	assert.Nil(t, err)
+
+	err = client.DeleteFile("k8scat", "testrepo", "file", &DeleteFileRequest{
+		Message: "delete file",
+		SHA:     "invalid",
+	})
+	assert.NotNil(t, err)
+
+	err = client.DeleteFile("k8scat", "testrepo", "file", &DeleteFileRequest{
+		Message: "delete file",
+		SHA:     resp.Content.SHA,
+	})
+	assert.Nil(t, err)
+	_, ok := server.File("k8scat", "testrepo", "file")
+	assert.False(t, ok)
 }
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/juju/errors"
 	"io/ioutil"
@@ -21,8 +22,12 @@ func (u *User) GetUsername() string {
 }
 
 func (c *Client) GetAuthenticatedUser() (*User, error) {
+	return c.GetAuthenticatedUserContext(context.Background())
+}
+
+func (c *Client) GetAuthenticatedUserContext(ctx context.Context) (*User, error) {
 	path := "/user"
-	resp, err := c.Request(http.MethodGet, path, nil, nil)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -2,17 +2,22 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
 
 	"github.com/juju/errors"
 
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/transport"
 	"github.com/k8scat/articli/pkg/utils"
 )
 
 const (
-	APIVersion = "/api/v4"
+	// PlatformName is the key of the rate limit and the metrics of the client
+	PlatformName = "gitlab"
+	APIVersion   = "/api/v4"
 
 	BaseURLJihuLab   = "https://jihulab.com"
 	BaseURLGitLabURL = "https://gitlab.com"
@@ -22,13 +27,38 @@ type Client struct {
 	BaseURL string
 	Token   string
 	User    *User
+	// HTTP sends all the requests of the client, the user agent is sent if not empty
+	transport.HTTP
 }
 
-func NewClient(baseURL string, token string) (*Client, error) {
+// Option configures a Client
+type Option func(c *Client)
+
+// WithHTTP applies the http options shared by the clients of the platforms, e.g. transport.WithTimeout
+func WithHTTP(opts ...transport.HTTPOption) Option {
+	return func(c *Client) {
+		c.Apply(opts...)
+	}
+}
+
+// WithOptions applies the http settings in opts except the base url and the token,
+// which are always passed to NewClient
+func WithOptions(opts *platform.Options) Option {
+	return func(c *Client) {
+		c.Apply(opts.HTTPOptions()...)
+	}
+}
+
+func NewClient(baseURL string, token string, opts ...Option) (*Client, error) {
 	client := &Client{
 		BaseURL: baseURL,
 		Token:   token,
+		HTTP:    transport.HTTP{HTTPClient: http.DefaultClient},
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	client.Wrap(PlatformName)
 	var err error
 	client.User, err = client.GetCurrentAuthenticatedUser()
 	if err != nil {
@@ -38,13 +68,17 @@ func NewClient(baseURL string, token string) (*Client, error) {
 }
 
 func (c *Client) Request(method, path string, headers http.Header, data interface{}, params url.Values) (*http.Response, error) {
+	return c.RequestContext(context.Background(), method, path, headers, data, params)
+}
+
+func (c *Client) RequestContext(ctx context.Context, method, path string, headers http.Header, data interface{}, params url.Values) (*http.Response, error) {
 	api := c.BuildAPI(path)
 	b, err := json.Marshal(data)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	body := bytes.NewReader(b)
-	req, err := http.NewRequest(method, api, body)
+	req, err := http.NewRequestWithContext(ctx, method, api, body)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -55,8 +89,11 @@ func (c *Client) Request(method, path string, headers http.Header, data interfac
 		req.Header = headers
 	}
 	req.Header.Set("PRIVATE-TOKEN", c.Token)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	return resp, errors.Trace(err)
 }
 
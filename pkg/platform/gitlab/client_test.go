@@ -1,19 +1,31 @@
 package gitlab
 
-import "os"
+import (
+	"testing"
 
-var (
-	token  = ""
-	client *Client
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
-func setupClient() {
-	if token == "" {
-		token = os.Getenv("ARTICLI_GITLAB_TOKEN")
-	}
-	var err error
-	client, err = NewClient(BaseURLJihuLab, token)
+// newTestClient returns a client of a fake gitlab server which is closed when the test finishes
+func newTestClient(t *testing.T) (*Client, *fake.GitLab) {
+	server := fake.NewGitLab()
+	t.Cleanup(server.Close)
+
+	// The fake server is not rate limited
+	client, err := NewClient(server.URL, fake.Token, WithHTTP(transport.WithRateLimit(-1)))
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
+	return client, server
+}
+
+func TestNewClient(t *testing.T) {
+	client, server := newTestClient(t)
+	assert.Equal(t, server.Username, client.User.Username)
+
+	_, err := NewClient(server.URL, "invalid")
+	assert.NotNil(t, err)
 }
@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -25,8 +26,12 @@ func (p *Project) IsPrivate() bool {
 }
 
 func (c *Client) GetProject(id string) (*Project, error) {
+	return c.GetProjectContext(context.Background(), id)
+}
+
+func (c *Client) GetProjectContext(ctx context.Context, id string) (*Project, error) {
 	path := fmt.Sprintf("/projects/%s", URLEncoded(id))
-	resp, err := c.Request(http.MethodGet, path, nil, nil, nil)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1,11 +1,13 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/google/go-querystring/query"
@@ -66,6 +68,10 @@ type CreateFileResponse struct {
 // CreateFile
 // https://docs.gitlab.com/ee/api/repository_files.html#create-new-file-in-repository
 func (c *Client) CreateFile(data *CreateFileData) (*CreateFileResponse, error) {
+	return c.CreateFileContext(context.Background(), data)
+}
+
+func (c *Client) CreateFileContext(ctx context.Context, data *CreateFileData) (*CreateFileResponse, error) {
 	if err := data.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -73,7 +79,7 @@ func (c *Client) CreateFile(data *CreateFileData) (*CreateFileResponse, error) {
 	headers := http.Header{
 		"Content-Type": {"application/json"},
 	}
-	resp, err := c.Request(http.MethodPost, path, headers, data, nil)
+	resp, err := c.RequestContext(ctx, http.MethodPost, path, headers, data, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -82,6 +88,9 @@ func (c *Client) CreateFile(data *CreateFileData) (*CreateFileResponse, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(b), "already exists") {
+		return nil, errors.AlreadyExistsf("file %s", data.FilePath)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return nil, errors.Errorf("unexpected response: %s", b)
 	}
@@ -110,6 +119,10 @@ type UpdateFileResponse CreateFileResponse
 // UpdateFile
 // https://docs.gitlab.com/ee/api/repository_files.html#update-existing-file-in-repository
 func (c *Client) UpdateFile(data *UpdateFileData) (*UpdateFileResponse, error) {
+	return c.UpdateFileContext(context.Background(), data)
+}
+
+func (c *Client) UpdateFileContext(ctx context.Context, data *UpdateFileData) (*UpdateFileResponse, error) {
 	if err := data.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -117,7 +130,7 @@ func (c *Client) UpdateFile(data *UpdateFileData) (*UpdateFileResponse, error) {
 	headers := http.Header{
 		"Content-Type": {"application/json"},
 	}
-	resp, err := c.Request(http.MethodPut, path, headers, data, nil)
+	resp, err := c.RequestContext(ctx, http.MethodPut, path, headers, data, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -170,11 +183,15 @@ func (d *DeleteFileData) GetFilePath() string {
 }
 
 func (c *Client) DeleteFile(data *DeleteFileData) error {
+	return c.DeleteFileContext(context.Background(), data)
+}
+
+func (c *Client) DeleteFileContext(ctx context.Context, data *DeleteFileData) error {
 	path := fmt.Sprintf("/projects/%s/repository/files/%s", data.GetProjectID(), data.GetFilePath())
 	headers := http.Header{
 		"Content-Type": {"application/json"},
 	}
-	resp, err := c.Request(http.MethodDelete, path, headers, data, nil)
+	resp, err := c.RequestContext(ctx, http.MethodDelete, path, headers, data, nil)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -222,6 +239,10 @@ func (p *ListRepoTreeParams) IntoValues() (url.Values, error) {
 // ListRepoTree
 // https://docs.gitlab.com/ee/api/repositories.html#list-repository-tree
 func (c *Client) ListRepoTree(projectID string, params *ListRepoTreeParams) ([]*FileNode, error) {
+	return c.ListRepoTreeContext(context.Background(), projectID, params)
+}
+
+func (c *Client) ListRepoTreeContext(ctx context.Context, projectID string, params *ListRepoTreeParams) ([]*FileNode, error) {
 	if projectID == "" {
 		return nil, errors.New("projectID is required")
 	}
@@ -230,7 +251,7 @@ func (c *Client) ListRepoTree(projectID string, params *ListRepoTreeParams) ([]*
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	resp, err := c.Request(http.MethodGet, path, nil, nil, values)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, nil, values)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -267,6 +288,10 @@ func (f *FileInfo) GetHumanSize() string {
 // GetFile
 // https://docs.gitlab.com/ee/api/repository_files.html#get-file-from-repository
 func (c *Client) GetFile(projectID, filePath, ref string) (*FileInfo, error) {
+	return c.GetFileContext(context.Background(), projectID, filePath, ref)
+}
+
+func (c *Client) GetFileContext(ctx context.Context, projectID, filePath, ref string) (*FileInfo, error) {
 	if projectID == "" {
 		return nil, errors.New("projectID is required")
 	}
@@ -280,7 +305,7 @@ func (c *Client) GetFile(projectID, filePath, ref string) (*FileInfo, error) {
 	params := url.Values{
 		"ref": {ref},
 	}
-	resp, err := c.Request(http.MethodGet, path, nil, nil, params)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, nil, params)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
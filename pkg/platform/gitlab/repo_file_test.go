@@ -1,10 +1,22 @@
 package gitlab
 
 import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strconv"
 	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetFile(t *testing.T) {
+	client, server := newTestClient(t)
+	project := server.AddProject("kube/kube", "public")
+	server.AddFile(project, "main", "README.md", []byte("# kube"))
+	server.AddFile(project, "main", "test12/test", []byte("test"))
+
 	type args struct {
 		projectId string
 		filePath  string
@@ -19,7 +31,7 @@ func TestGetFile(t *testing.T) {
 		{
 			name: "Get file",
 			args: args{
-				projectId: "15242",
+				projectId: strconv.Itoa(project.ID),
 				filePath:  "README.md",
 				ref:       "main",
 			},
@@ -55,8 +67,17 @@ func TestGetFile(t *testing.T) {
 			want:    "test",
 			wantErr: false,
 		},
+		{
+			name: "Get missing file",
+			args: args{
+				projectId: "kube/kube",
+				filePath:  "missing",
+				ref:       "main",
+			},
+			want:    "",
+			wantErr: true,
+		},
 	}
-	setupClient()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := client.GetFile(tt.args.projectId, tt.args.filePath, tt.args.ref)
@@ -70,3 +91,77 @@ func TestGetFile(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateFile(t *testing.T) {
+	client, server := newTestClient(t)
+	project := server.AddProject("kube/images", "private")
+
+	data := &CreateFileData{
+		ProjectID:     "kube/images",
+		FilePath:      "2022/go.png",
+		Branch:        "main",
+		Content:       base64.StdEncoding.EncodeToString([]byte("png")),
+		Encoding:      ContentEncodingBase64,
+		CommitMessage: "upload go.png",
+	}
+	resp, err := client.CreateFile(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "2022/go.png", resp.FilePath)
+	b, ok := server.File(project, "main", "2022/go.png")
+	assert.True(t, ok)
+	assert.Equal(t, "png", string(b))
+
+	// The file exists already
+	_, err = client.CreateFile(data)
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	p, err := client.GetProject("kube/images")
+	assert.Nil(t, err)
+	assert.True(t, p.IsPrivate())
+	res, err := http.Get(client.BuildFileDownloadURL("kube/images", "2022/go.png", "main", p.IsPrivate()))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	b, err = ioutil.ReadAll(res.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "png", string(b))
+
+	nodes, err := client.ListRepoTree("kube/images", &ListRepoTreeParams{Recursive: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(nodes))
+	assert.Equal(t, FileNodeTypeTree, nodes[0].Type)
+	assert.Equal(t, FileNodeTypeBlob, nodes[1].Type)
+}
+
+func TestUpdateFile(t *testing.T) {
+	client, server := newTestClient(t)
+	project := server.AddProject("kube/kube", "public")
+
+	data := &UpdateFileData{
+		CreateFileData: CreateFileData{
+			ProjectID:     "kube/kube",
+			FilePath:      "README.md",
+			Branch:        "main",
+			Content:       "# kube",
+			CommitMessage: "update README.md",
+		},
+	}
+	// The file does not exist
+	_, err := client.UpdateFile(data)
+	assert.NotNil(t, err)
+
+	server.AddFile(project, "main", "README.md", []byte("# README"))
+	_, err = client.UpdateFile(data)
+	assert.Nil(t, err)
+	b, _ := server.File(project, "main", "README.md")
+	assert.Equal(t, "# kube", string(b))
+
+	err = client.DeleteFile(&DeleteFileData{
+		ProjectID:     "kube/kube",
+		FilePath:      "README.md",
+		Branch:        "main",
+		CommitMessage: "delete README.md",
+	})
+	assert.Nil(t, err)
+	_, ok := server.File(project, "main", "README.md")
+	assert.False(t, ok)
+}
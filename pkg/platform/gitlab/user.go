@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -17,8 +18,12 @@ type User struct {
 }
 
 func (c *Client) GetCurrentAuthenticatedUser() (*User, error) {
+	return c.GetCurrentAuthenticatedUserContext(context.Background())
+}
+
+func (c *Client) GetCurrentAuthenticatedUserContext(ctx context.Context) (*User, error) {
 	path := "/user"
-	resp, err := c.Request(http.MethodGet, path, nil, nil, nil)
+	resp, err := c.RequestContext(ctx, http.MethodGet, path, nil, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
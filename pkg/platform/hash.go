@@ -0,0 +1,69 @@
+package platform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+// ContentHashKey is the key of the content hash in the platform meta, which is one of markdown.StateKeys
+const ContentHashKey = "content_hash"
+
+// ContentHash returns the sha256 of everything which is published to the named platform:
+// the markdown content, the common meta and the platform meta except markdown.StateKeys.
+// extra is what else is published along with them, e.g. the hashes of the local images.
+func ContentHash(mark *markdown.Mark, name string, extra ...string) (string, error) {
+	platforms := make(map[string]bool)
+	for _, n := range Names() {
+		platforms[n] = true
+	}
+	platforms[name] = true
+
+	common := make(markdown.Meta, 0, len(mark.Meta))
+	for _, item := range mark.Meta {
+		if k, ok := item.Key.(string); ok && platforms[k] {
+			continue
+		}
+		common = append(common, item)
+	}
+
+	meta, _ := mark.Meta.Get(name).(markdown.Meta)
+	own := make(markdown.Meta, 0, len(meta))
+	for _, item := range meta {
+		if k, ok := item.Key.(string); ok && markdown.IsStateKey(k) {
+			continue
+		}
+		own = append(own, item)
+	}
+
+	h := sha256.New()
+	for _, m := range []markdown.Meta{common, own} {
+		b, err := yaml.Marshal(m)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		h.Write(b)
+	}
+	h.Write([]byte(strings.TrimSpace(mark.Content)))
+	for _, e := range extra {
+		h.Write([]byte("\n" + e))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetContentHash returns the content hash stored in the named platform meta
+func GetContentHash(mark *markdown.Mark, name string) string {
+	return mark.Meta.GetString(name + "." + ContentHashKey)
+}
+
+// SetContentHash stores hash in the named platform meta
+func SetContentHash(mark *markdown.Mark, name, hash string) {
+	meta, _ := mark.Meta.Get(name).(markdown.Meta)
+	meta = meta.Set(ContentHashKey, hash)
+	mark.Meta = mark.Meta.Set(name, meta)
+}
@@ -0,0 +1,54 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+func TestContentHash(t *testing.T) {
+	s := `
+title: hello
+juejin:
+  category: 后端
+  tags:
+  - Go
+`
+	var meta markdown.Meta
+	err := yaml.Unmarshal([]byte(s), &meta)
+	assert.Nil(t, err)
+	mark := &markdown.Mark{Meta: meta, Content: "content\n"}
+
+	hash, err := ContentHash(mark, "juejin")
+	assert.Nil(t, err)
+	assert.Len(t, hash, 64)
+
+	// Generated fields do not change the hash
+	juejin, _ := mark.Meta.Get("juejin").(markdown.Meta)
+	juejin = juejin.Set("article_id", "1")
+	mark.Meta = mark.Meta.Set("juejin", juejin)
+	SetContentHash(mark, "juejin", hash)
+	h, err := ContentHash(mark, "juejin")
+	assert.Nil(t, err)
+	assert.Equal(t, hash, h)
+	assert.Equal(t, hash, GetContentHash(mark, "juejin"))
+
+	// Content and common meta change the hash
+	mark.Content = "new content\n"
+	h, err = ContentHash(mark, "juejin")
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, h)
+
+	mark.Content = "content\n"
+	h, err = ContentHash(mark, "juejin", "image")
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, h)
+
+	mark.Meta = mark.Meta.Set("title", "world")
+	h, err = ContentHash(mark, "juejin")
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, h)
+}
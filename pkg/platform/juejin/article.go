@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/juju/errors"
@@ -35,26 +36,34 @@ type SaveArticleParams struct {
 
 // SaveArticle create an article if id is empty, otherwise update the article
 func (c *Client) SaveArticle(params *SaveArticleParams) error {
+	return c.SaveArticleContext(context.Background(), params)
+}
+
+func (c *Client) SaveArticleContext(ctx context.Context, params *SaveArticleParams) error {
 	if params.ArticleID != "" && params.DraftID == "" {
 		var article *Article
-		article, err := c.GetArticle(params.ArticleID)
+		article, err := c.GetArticleContext(ctx, params.ArticleID)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		params.DraftID = article.Info.DraftID
 	}
 
-	if err := c.SaveDraft(params); err != nil {
+	if err := c.SaveDraftContext(ctx, params); err != nil {
 		return errors.Trace(err)
 	}
 
 	var err error
-	params.ArticleID, err = c.PublishArticle(params.DraftID, params.SyncToOrg)
+	params.ArticleID, err = c.PublishArticleContext(ctx, params.DraftID, params.SyncToOrg)
 	return errors.Trace(err)
 }
 
 // ListArticles list articles by keyword
 func (c *Client) ListArticles(keyword string, page, pageSize int, status AuditStatus) (articles []*Article, count int, err error) {
+	return c.ListArticlesContext(context.Background(), keyword, page, pageSize, status)
+}
+
+func (c *Client) ListArticlesContext(ctx context.Context, keyword string, page, pageSize int, status AuditStatus) (articles []*Article, count int, err error) {
 	if pageSize > MaxPageSize {
 		pageSize = MaxPageSize
 	}
@@ -70,7 +79,7 @@ func (c *Client) ListArticles(keyword string, page, pageSize int, status AuditSt
 	}
 
 	var raw string
-	raw, err = c.Post(endpoint, payload)
+	raw, err = c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -87,11 +96,15 @@ func (c *Client) ListArticles(keyword string, page, pageSize int, status AuditSt
 
 // GetArticle get article detail
 func (c *Client) GetArticle(id string) (*Article, error) {
+	return c.GetArticleContext(context.Background(), id)
+}
+
+func (c *Client) GetArticleContext(ctx context.Context, id string) (*Article, error) {
 	endpoint := buildArticleEndpoint("detail")
 	payload := map[string]interface{}{
 		"article_id": id,
 	}
-	raw, err := c.Post(endpoint, payload)
+	raw, err := c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -102,22 +115,30 @@ func (c *Client) GetArticle(id string) (*Article, error) {
 }
 
 func (c *Client) DeleteArticle(id string) error {
+	return c.DeleteArticleContext(context.Background(), id)
+}
+
+func (c *Client) DeleteArticleContext(ctx context.Context, id string) error {
 	endpoint := buildArticleEndpoint("delete")
 	payload := map[string]interface{}{
 		"article_id": id,
 	}
-	_, err := c.Post(endpoint, payload)
+	_, err := c.PostContext(ctx, endpoint, payload)
 	return errors.Trace(err)
 }
 
 // PublishArticle publish a draft
 func (c *Client) PublishArticle(draftID string, syncToOrg bool) (string, error) {
+	return c.PublishArticleContext(context.Background(), draftID, syncToOrg)
+}
+
+func (c *Client) PublishArticleContext(ctx context.Context, draftID string, syncToOrg bool) (string, error) {
 	endpoint := buildArticleEndpoint("publish")
 	payload := map[string]interface{}{
 		"draft_id":    draftID,
 		"sync_to_org": syncToOrg,
 	}
-	raw, err := c.Post(endpoint, payload)
+	raw, err := c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -1,19 +1,97 @@
 package juejin
 
 import (
-	"os"
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func TestSaveArticle(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &SaveArticleParams{
+		Title:      "Title",
+		Content:    "# Title",
+		CategoryID: "6809637769959178254",
+		TagIDs:     []string{"6809640364677267469"},
+	}
+	err := client.SaveArticle(params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", params.ArticleID)
+	assert.NotEqual(t, "", params.DraftID)
+
+	article, err := client.GetArticle(params.ArticleID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", article.Info.Title)
+	assert.Equal(t, params.DraftID, article.Info.DraftID)
+	assert.Equal(t, "后端", article.Category.Name)
+	assert.Equal(t, "Go", article.Tags[0].Name)
+
+	// Update the article by the article id only
+	articleID := params.ArticleID
+	params = &SaveArticleParams{
+		ArticleID:  articleID,
+		Title:      "New Title",
+		Content:    "# New Title",
+		CategoryID: "6809637769959178254",
+		TagIDs:     []string{"6809640357354012685"},
+	}
+	err = client.SaveArticle(params)
+	assert.Nil(t, err)
+	assert.Equal(t, articleID, params.ArticleID)
+
+	articles, count, err := client.ListArticles("New", 1, MaxPageSize, AuditStatusAll)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "New Title", articles[0].Info.Title)
+	assert.Equal(t, "Docker", articles[0].Tags[0].Name)
+}
+
 func TestDeleteArticle(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
+	client, _ := newTestClient(t)
+
+	params := &SaveArticleParams{
+		Title:      "Title",
+		Content:    "# Title",
+		CategoryID: "6809637769959178254",
+		TagIDs:     []string{"6809640364677267469"},
 	}
+	err := client.SaveArticle(params)
+	assert.Nil(t, err)
+
+	err = client.DeleteArticle(params.ArticleID)
+	assert.Nil(t, err)
+
+	_, err = client.GetArticle(params.ArticleID)
+	assert.NotNil(t, err)
+	err = client.DeleteArticle(params.ArticleID)
+	assert.NotNil(t, err)
+}
 
-	err = client.DeleteArticle("6947311736529633294")
+func TestFindArticles(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &SaveArticleParams{
+		Title:      "Title",
+		Content:    "# Title",
+		CategoryID: "6809637769959178254",
+		TagIDs:     []string{"6809640364677267469"},
+	}
+	assert.Nil(t, client.SaveArticle(params))
+
+	p := &Publisher{Client: client}
+	articles, err := p.FindArticles(context.Background(), "Title")
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(articles)) {
+		assert.Equal(t, params.ArticleID, articles[0].ID)
+		assert.Equal(t, params.DraftID, articles[0].DraftID)
+		assert.Equal(t, "Title", articles[0].Title)
+		assert.Equal(t, BuildArticleURL(params.ArticleID), articles[0].URL)
+		assert.WithinDuration(t, time.Now(), articles[0].CreateTime, time.Minute)
+	}
+	articles, err = p.FindArticles(context.Background(), "Unknown")
 	assert.Nil(t, err)
+	assert.Equal(t, 0, len(articles))
 }
@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/juju/errors"
 	"github.com/tidwall/gjson"
@@ -15,8 +16,12 @@ type CategoryItem struct {
 
 // ListCategories list all categories
 func (c *Client) ListCategories() ([]*CategoryItem, error) {
+	return c.ListCategoriesContext(context.Background())
+}
+
+func (c *Client) ListCategoriesContext(ctx context.Context) ([]*CategoryItem, error) {
 	endpoint := "/tag_api/v1/query_category_list"
-	raw, err := c.Post(endpoint, nil)
+	raw, err := c.PostContext(ctx, endpoint, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -27,16 +32,16 @@ func (c *Client) ListCategories() ([]*CategoryItem, error) {
 	return categories, errors.Trace(err)
 }
 
+// GetCategoryByName returns the category by name with the index of client
 func GetCategoryByName(client *Client, name string) (*CategoryItem, error) {
-	categories, err := client.ListCategories()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
+	return GetCategoryByNameContext(context.Background(), client, name)
+}
 
-	for _, category := range categories {
-		if category.Category.Name == name {
-			return category, nil
-		}
-	}
-	return nil, errors.NotFoundf("category '%q'", name)
+func GetCategoryByNameContext(ctx context.Context, client *Client, name string) (*CategoryItem, error) {
+	var category *CategoryItem
+	err := client.resolve(ctx, func(index *Index) (err error) {
+		category, err = index.FindCategory(name)
+		return err
+	})
+	return category, errors.Trace(err)
 }
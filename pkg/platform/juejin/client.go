@@ -2,38 +2,125 @@ package juejin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	browser "github.com/EDDYCJY/fake-useragent"
 	"github.com/juju/errors"
 	"github.com/tidwall/gjson"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
 const (
-	DefaultBaseAPI = "https://api.juejin.cn"
-	MaxPageSize    = 20
+	DefaultBaseAPI   = "https://api.juejin.cn"
+	DefaultImageXAPI = "https://imagex.bytedanceapi.com"
+	DefaultTimeout   = time.Minute
+	MaxPageSize      = 20
 )
 
 type Client struct {
-	Cookie  string
-	User    *User
-	BaseAPI string
+	Cookie    string
+	User      *User
+	BaseAPI   string
+	ImageXAPI string
+	// HTTP sends all the requests of the client including the image uploads,
+	// a random user agent of browsers is sent if the user agent is empty
+	transport.HTTP
+	// IndexFile caches the tags and categories, they are only cached in memory if empty
+	IndexFile string
+	// IndexTTL is how long the cached index is used, DefaultIndexTTL is used if 0
+	IndexTTL time.Duration
+	// TagAliases maps the tag names in articles to the names in juejin
+	TagAliases map[string]string
+
+	indexMu sync.Mutex
+	index   *Index
+	// indexSynced reports whether index is listed by the client instead of loaded from IndexFile
+	indexSynced bool
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithBaseAPI sets the base url of the juejin api, e.g. a fake server in tests
+func WithBaseAPI(api string) Option {
+	return func(c *Client) {
+		c.BaseAPI = strings.TrimSuffix(api, "/")
+	}
+}
+
+// WithImageXAPI sets the base url of the imagex api which is used to upload images
+func WithImageXAPI(api string) Option {
+	return func(c *Client) {
+		c.ImageXAPI = strings.TrimSuffix(api, "/")
+	}
+}
+
+// WithHTTP applies the http options shared by the clients of the platforms, e.g. transport.WithTimeout
+func WithHTTP(opts ...transport.HTTPOption) Option {
+	return func(c *Client) {
+		c.Apply(opts...)
+	}
+}
+
+// WithIndexFile sets the file which caches the tags and categories, and how long it is used
+func WithIndexFile(file string, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.IndexFile = file
+		c.IndexTTL = ttl
+	}
+}
+
+// WithTagAliases sets the aliases of the tag names, e.g. golang: Go
+func WithTagAliases(aliases map[string]string) Option {
+	return func(c *Client) {
+		c.TagAliases = aliases
+	}
 }
 
-func NewClient(cookie string) (*Client, error) {
+// WithOptions applies the urls, the http settings and the tag settings in opts,
+// the cookie in opts is passed to NewClient by the caller
+func WithOptions(opts *platform.Options) Option {
+	return func(c *Client) {
+		if opts.BaseURL != "" {
+			WithBaseAPI(opts.BaseURL)(c)
+		}
+		if opts.ImageURL != "" {
+			WithImageXAPI(opts.ImageURL)(c)
+		}
+		if opts.CacheDir != "" {
+			WithIndexFile(IndexFilePath(opts.CacheDir), opts.CacheTTL)(c)
+		}
+		if opts.TagAliases != nil {
+			c.TagAliases = opts.TagAliases
+		}
+		c.Apply(opts.HTTPOptions()...)
+	}
+}
+
+func NewClient(cookie string, opts ...Option) (*Client, error) {
 	if cookie == "" {
 		return nil, errors.New("empty cookie")
 	}
 	c := &Client{
-		BaseAPI: DefaultBaseAPI,
-		Cookie:  cookie,
+		BaseAPI:   DefaultBaseAPI,
+		ImageXAPI: DefaultImageXAPI,
+		Cookie:    cookie,
+		HTTP:      transport.HTTP{HTTPClient: &http.Client{Timeout: DefaultTimeout}},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.Wrap(PlatformName)
 	var err error
 	c.User, err = c.GetUser()
 	if err != nil {
@@ -44,24 +131,25 @@ func NewClient(cookie string) (*Client, error) {
 
 // Get request and return raw body
 func (c *Client) Get(endpoint string, query *url.Values) (string, error) {
+	return c.GetContext(context.Background(), endpoint, query)
+}
+
+func (c *Client) GetContext(ctx context.Context, endpoint string, query *url.Values) (string, error) {
 	if endpoint == "" {
 		return "", errors.New("empty request endpoint")
 	}
 	path := fmt.Sprintf("%s%s", c.BaseAPI, endpoint)
-	req, err := http.NewRequest(http.MethodGet, path, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	req.Header.Set("Cookie", c.Cookie)
-	req.Header.Set("User-Agent", browser.Computer())
+	req.Header.Set("User-Agent", c.BrowserUserAgent())
 	if query != nil {
 		req.URL.RawQuery = query.Encode()
 	}
 
-	client := &http.Client{
-		Timeout: time.Minute,
-	}
-	res, err := client.Do(req)
+	res, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -69,8 +157,23 @@ func (c *Client) Get(endpoint string, query *url.Values) (string, error) {
 	return raw, errors.Trace(err)
 }
 
+// readEndpoints are the POST endpoints which only read the data, so they are retried like the GET requests
+var readEndpoints = map[string]bool{
+	buildArticleEndpoint("detail"):       true,
+	buildArticleEndpoint("list_by_user"): true,
+	buildDraftEndpoint("detail"):         true,
+	buildDraftEndpoint("list_by_user"):   true,
+	buildDraftEndpoint("query_list"):     true,
+	"/tag_api/v1/query_tag_list":         true,
+	"/tag_api/v1/query_category_list":    true,
+}
+
 // Post request and return raw body
 func (c *Client) Post(endpoint string, body interface{}) (string, error) {
+	return c.PostContext(context.Background(), endpoint, body)
+}
+
+func (c *Client) PostContext(ctx context.Context, endpoint string, body interface{}) (string, error) {
 	if endpoint == "" {
 		return "", errors.New("empty request endpoint")
 	}
@@ -84,16 +187,19 @@ func (c *Client) Post(endpoint string, body interface{}) (string, error) {
 		r = bytes.NewReader(b)
 	}
 
+	if readEndpoints[endpoint] {
+		ctx = transport.WithRetryable(ctx)
+	}
 	path := fmt.Sprintf("%s%s", c.BaseAPI, endpoint)
-	req, err := http.NewRequest(http.MethodPost, path, r)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, r)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	req.Header.Add("Cookie", c.Cookie)
-	req.Header.Add("User-Agent", browser.Computer())
+	req.Header.Add("User-Agent", c.BrowserUserAgent())
 	req.Header.Add("Content-Type", "application/json")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
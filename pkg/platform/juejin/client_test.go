@@ -1,13 +1,159 @@
 package juejin
 
 import (
-	"os"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
+// newTestClient returns a client of a fake juejin server which is closed when the test finishes
+func newTestClient(t *testing.T) (*Client, *fake.Juejin) {
+	server := fake.NewJuejin()
+	t.Cleanup(server.Close)
+
+	// The fake server is not rate limited
+	client, err := NewClient(fake.Cookie, WithBaseAPI(server.URL), WithImageXAPI(server.URL), WithHTTP(transport.WithRateLimit(-1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
 func TestNewClient(t *testing.T) {
-	_, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
+	client, server := newTestClient(t)
+	assert.Equal(t, server.UserID, client.User.ID)
+	assert.Equal(t, server.UserName, client.User.Name)
+
+	_, err := NewClient("sessionid=invalid", WithBaseAPI(server.URL))
+	assert.NotNil(t, err)
+}
+
+// recordTransport records the user agents of the requests
+type recordTransport struct {
+	userAgents []string
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.userAgents = append(t.userAgents, req.Header.Get("User-Agent"))
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientOptions(t *testing.T) {
+	server := fake.NewJuejin()
+	t.Cleanup(server.Close)
+
+	rt := &recordTransport{}
+	opts := &platform.Options{
+		Cookie:    fake.Cookie,
+		BaseURL:   server.URL + "/",
+		ImageURL:  server.URL,
+		UserAgent: "articli-test",
+		Timeout:   10 * time.Second,
+		RateLimit: 100,
+		CacheDir:  t.TempDir(),
+	}
+	client, err := NewClient(opts.Cookie, WithHTTP(transport.WithTransport(rt)), WithOptions(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, server.URL, client.BaseAPI)
+	assert.Equal(t, server.URL, client.ImageXAPI)
+	assert.Equal(t, 10*time.Second, client.HTTPClient.Timeout)
+	assert.Equal(t, IndexFilePath(opts.CacheDir), client.IndexFile)
+	if tr, ok := client.HTTPClient.Transport.(*transport.Transport); assert.True(t, ok) {
+		assert.Equal(t, rt, tr.Base)
+		// The rate limit of the client does not change the limit shared by the other clients
+		assert.NotEqual(t, transport.LimiterFor(PlatformName), tr.Limiter)
+	}
+	assert.Equal(t, []string{"articli-test"}, rt.userAgents)
+
+	hc := &http.Client{}
+	client, err = NewClient(fake.Cookie, WithBaseAPI(server.URL), WithHTTP(transport.WithHTTPClient(hc), transport.WithTimeout(time.Second)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, time.Second, client.HTTPClient.Timeout)
+	assert.Equal(t, time.Duration(0), hc.Timeout)
+}
+
+// flakyTransport fails the first request of each path with 503 Service Unavailable
+type flakyTransport struct {
+	failed map[string]bool
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.failed[req.URL.Path] {
+		t.failed[req.URL.Path] = true
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Header:     http.Header{},
+			Request:    req,
+		}, nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRetryReadEndpoints(t *testing.T) {
+	client, _ := newTestClient(t)
+	tr := client.HTTPClient.Transport.(*transport.Transport)
+	tr.Base = &flakyTransport{failed: make(map[string]bool)}
+	tr.MinBackoff = time.Millisecond
+	tr.MaxBackoff = time.Millisecond
+
+	// The reads are retried
+	_, _, err := client.ListArticles("", 1, 10, AuditStatusAll)
+	assert.Nil(t, err)
+	_, _, err = client.ListTags("", "")
+	assert.Nil(t, err)
+
+	// The writes are never retried, which may be applied twice
+	err = client.DeleteArticle("1")
+	assert.NotNil(t, err)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := fake.NewJuejin()
+	defer server.Close()
+
+	dir := t.TempDir()
+	transport.RecordDir = dir
+	client, err := NewClient(fake.Cookie, WithBaseAPI(server.URL), WithHTTP(transport.WithRateLimit(-1)))
+	transport.RecordDir = ""
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	replayer, err := transport.NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := NewClient(fake.Cookie, WithHTTP(transport.WithTransport(replayer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, client.User, replayed.User)
+}
+
+func TestContextCanceled(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetUserContext(ctx)
+	assert.NotNil(t, err)
+
+	user, err := client.GetUserContext(context.Background())
 	assert.Nil(t, err)
+	assert.Equal(t, client.User.ID, user.ID)
 }
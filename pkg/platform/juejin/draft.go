@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/juju/errors"
@@ -37,6 +38,10 @@ type Draft struct {
 
 // SaveDraft create a draft if id is empty, otherwise update the draft
 func (c *Client) SaveDraft(params *SaveArticleParams) error {
+	return c.SaveDraftContext(context.Background(), params)
+}
+
+func (c *Client) SaveDraftContext(ctx context.Context, params *SaveArticleParams) error {
 	var endpoint string
 	if params.DraftID == "" {
 		endpoint = "/content_api/v1/article_draft/create"
@@ -58,7 +63,7 @@ func (c *Client) SaveDraft(params *SaveArticleParams) error {
 	if params.CategoryID != "" {
 		payload["category_id"] = params.CategoryID
 	}
-	data, err := c.Post(endpoint, payload)
+	data, err := c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -79,6 +84,10 @@ type ListDraftsResponse struct {
 }
 
 func (c *Client) ListDrafts(keyword string, page, pageSize int) (drafts []*Draft, count int, err error) {
+	return c.ListDraftsContext(context.Background(), keyword, page, pageSize)
+}
+
+func (c *Client) ListDraftsContext(ctx context.Context, keyword string, page, pageSize int) (drafts []*Draft, count int, err error) {
 	if pageSize > MaxPageSize {
 		pageSize = MaxPageSize
 	}
@@ -90,7 +99,7 @@ func (c *Client) ListDrafts(keyword string, page, pageSize int) (drafts []*Draft
 		"page_size": pageSize,
 	}
 	var raw string
-	raw, err = c.Post(endpoint, payload)
+	raw, err = c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -108,8 +117,12 @@ func (c *Client) ListDrafts(keyword string, page, pageSize int) (drafts []*Draft
 // ListAllDrafts list all drafts
 // Deprecated
 func (c *Client) ListAllDrafts() ([]string, error) {
+	return c.ListAllDraftsContext(context.Background())
+}
+
+func (c *Client) ListAllDraftsContext(ctx context.Context) ([]string, error) {
 	endpoint := buildDraftEndpoint("query_list")
-	data, err := c.Post(endpoint, nil)
+	data, err := c.PostContext(ctx, endpoint, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -120,12 +133,47 @@ func (c *Client) ListAllDrafts() ([]string, error) {
 	return ids, nil
 }
 
+type DraftDetail struct {
+	Draft    *Draft    `json:"article_draft"`
+	Category *Category `json:"category"`
+	Tags     []*Tag    `json:"tags"`
+}
+
+// GetDraft get draft detail
+func (c *Client) GetDraft(id string) (*DraftDetail, error) {
+	return c.GetDraftContext(context.Background(), id)
+}
+
+func (c *Client) GetDraftContext(ctx context.Context, id string) (*DraftDetail, error) {
+	endpoint := buildDraftEndpoint("detail")
+	payload := map[string]interface{}{
+		"draft_id": id,
+	}
+	raw, err := c.PostContext(ctx, endpoint, payload)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data := gjson.Get(raw, "data").String()
+	var detail *DraftDetail
+	if err = json.Unmarshal([]byte(data), &detail); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if detail == nil || detail.Draft == nil {
+		return nil, errors.NotFoundf("draft %s", id)
+	}
+	return detail, nil
+}
+
 func (c *Client) DeleteDraft(id string) error {
+	return c.DeleteDraftContext(context.Background(), id)
+}
+
+func (c *Client) DeleteDraftContext(ctx context.Context, id string) error {
 	endpoint := buildDraftEndpoint("delete")
 	payload := map[string]string{
 		"draft_id": id,
 	}
-	_, err := c.Post(endpoint, payload)
+	_, err := c.PostContext(ctx, endpoint, payload)
 	return errors.Trace(err)
 }
 
@@ -1,19 +1,69 @@
 package juejin
 
 import (
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func TestListAllDrafts(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
+func TestSaveDraft(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &SaveArticleParams{
+		Title:      "Draft",
+		Content:    "# Draft",
+		CoverImage: "https://cdn.com/cover.png",
+		CategoryID: "6809637767543259144",
+		TagIDs:     []string{"6809640385980137480", "6809640407484334093"},
+	}
+	err := client.SaveDraft(params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", params.DraftID)
+
+	params.Title = "New Draft"
+	err = client.SaveDraft(params)
+	assert.Nil(t, err)
+
+	detail, err := client.GetDraft(params.DraftID)
+	assert.Nil(t, err)
+	assert.Equal(t, "New Draft", detail.Draft.Title)
+	assert.Equal(t, "https://cdn.com/cover.png", detail.Draft.CoverImage)
+	assert.Equal(t, "前端", detail.Category.Name)
+	assert.Equal(t, 2, len(detail.Tags))
+
+	mark := NewDraftMark(detail)
+	assert.Equal(t, "New Draft", mark.Meta.GetString("title"))
+	assert.Equal(t, []string{"Linux", "程序员"}, mark.Meta.Get("juejin.tags"))
+}
+
+func TestListDrafts(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	for _, title := range []string{"Go", "Docker", "Go Modules"} {
+		err := client.SaveDraft(&SaveArticleParams{Title: title, Content: title})
+		assert.Nil(t, err)
 	}
 
-	_, err = client.ListAllDrafts()
+	drafts, count, err := client.ListDrafts("Go", 1, MaxPageSize)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "Go Modules", drafts[0].Title)
+
+	ids, err := client.ListAllDrafts()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(ids))
+}
+
+func TestDeleteDraft(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &SaveArticleParams{Title: "Draft", Content: "# Draft"}
+	err := client.SaveDraft(params)
 	assert.Nil(t, err)
+
+	err = client.DeleteDraft(params.DraftID)
+	assert.Nil(t, err)
+
+	_, err = client.GetDraft(params.DraftID)
+	assert.NotNil(t, err)
 }
@@ -2,6 +2,7 @@ package juejin
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -29,7 +30,6 @@ const (
 	serviceName              = "imagex"
 	serviceID                = "k3u1fbpfcp"
 	version                  = "2018-08-01"
-	uploadURLFormat          = "https://%s/%s"
 
 	RegionCNNorth = "cn-north-1"
 
@@ -61,7 +61,11 @@ type UploadToken struct {
 }
 
 func (c *Client) UploadImage(region, path string) (string, error) {
-	uploadToken, err := c.GetUploadToken()
+	return c.UploadImageContext(context.Background(), region, path)
+}
+
+func (c *Client) UploadImageContext(ctx context.Context, region, path string) (string, error) {
+	uploadToken, err := c.GetUploadTokenContext(ctx)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -71,9 +75,11 @@ func (c *Client) UploadImage(region, path string) (string, error) {
 		SecretKey: uploadToken.SecretAccessKey,
 		Token:     uploadToken.SessionToken,
 		Region:    region,
+		BaseURL:   c.ImageXAPI,
+		Client:    c.HTTPClient,
 	}
 
-	applyRes, err := ix.ApplyImageUpload()
+	applyRes, err := ix.ApplyImageUploadContext(ctx)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -82,17 +88,17 @@ func (c *Client) UploadImage(region, path string) (string, error) {
 	storeURI := storeInfo.Get("StoreUri").String()
 	storeAuth := storeInfo.Get("Auth").String()
 	uploadHost := gjson.Get(applyRes, "Result.UploadAddress.UploadHosts.0").String()
-	uploadURL := fmt.Sprintf(uploadURLFormat, uploadHost, storeURI)
-	if err := ix.Upload(uploadURL, path, storeAuth); err != nil {
+	uploadURL := ix.buildUploadURL(uploadHost, storeURI)
+	if err := ix.UploadContext(ctx, uploadURL, path, storeAuth); err != nil {
 		return "", errors.Trace(err)
 	}
 
 	sessionKey := gjson.Get(applyRes, "Result.UploadAddress.SessionKey").String()
-	if _, err = ix.CommitImageUpload(sessionKey); err != nil {
+	if _, err = ix.CommitImageUploadContext(ctx, sessionKey); err != nil {
 		return "", errors.Trace(err)
 	}
 
-	imageURL, err := c.GetImageURL(storeURI)
+	imageURL, err := c.GetImageURLContext(ctx, storeURI)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -105,11 +111,15 @@ type ImageURL struct {
 }
 
 func (c *Client) GetImageURL(uri string) (*ImageURL, error) {
+	return c.GetImageURLContext(context.Background(), uri)
+}
+
+func (c *Client) GetImageURLContext(ctx context.Context, uri string) (*ImageURL, error) {
 	endpoint := "/imagex/get_img_url"
 	params := &url.Values{
 		"uri": []string{uri},
 	}
-	raw, err := c.Get(endpoint, params)
+	raw, err := c.GetContext(ctx, endpoint, params)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -123,11 +133,15 @@ func (c *Client) GetImageURL(uri string) (*ImageURL, error) {
 }
 
 func (c *Client) GetUploadToken() (*UploadToken, error) {
+	return c.GetUploadTokenContext(context.Background())
+}
+
+func (c *Client) GetUploadTokenContext(ctx context.Context) (*UploadToken, error) {
 	endpoint := "/imagex/gen_token"
 	params := &url.Values{
 		"client": []string{"web"},
 	}
-	raw, err := c.Get(endpoint, params)
+	raw, err := c.GetContext(ctx, endpoint, params)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -137,9 +151,13 @@ func (c *Client) GetUploadToken() (*UploadToken, error) {
 }
 
 func (ix *ImageX) ApplyImageUpload() (string, error) {
-	rawurl := fmt.Sprintf("https://imagex.bytedanceapi.com/?Action=%s&Version=%s&ServiceId=%s",
-		actionApplyImageUpload, version, serviceID)
-	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	return ix.ApplyImageUploadContext(context.Background())
+}
+
+func (ix *ImageX) ApplyImageUploadContext(ctx context.Context) (string, error) {
+	rawurl := fmt.Sprintf("%s/?Action=%s&Version=%s&ServiceId=%s",
+		ix.getBaseURL(), actionApplyImageUpload, version, serviceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -165,9 +183,13 @@ func (ix *ImageX) ApplyImageUpload() (string, error) {
 }
 
 func (ix *ImageX) CommitImageUpload(sessionKey string) (string, error) {
-	rawurl := fmt.Sprintf("https://imagex.bytedanceapi.com/?Action=%s&Version=%s&SessionKey=%s&ServiceId=%s",
-		actionCommitImageUpload, version, sessionKey, serviceID)
-	req, err := http.NewRequest(http.MethodPost, rawurl, nil)
+	return ix.CommitImageUploadContext(context.Background(), sessionKey)
+}
+
+func (ix *ImageX) CommitImageUploadContext(ctx context.Context, sessionKey string) (string, error) {
+	rawurl := fmt.Sprintf("%s/?Action=%s&Version=%s&SessionKey=%s&ServiceId=%s",
+		ix.getBaseURL(), actionCommitImageUpload, version, sessionKey, serviceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawurl, nil)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -199,6 +221,22 @@ func (ix *ImageX) getClient() *http.Client {
 	return ix.Client
 }
 
+func (ix *ImageX) getBaseURL() string {
+	if ix.BaseURL == "" {
+		return DefaultImageXAPI
+	}
+	return ix.BaseURL
+}
+
+// buildUploadURL returns the url of the upload host, which uses the same scheme as the imagex api
+func (ix *ImageX) buildUploadURL(host, storeURI string) string {
+	scheme := "https"
+	if u, err := url.Parse(ix.getBaseURL()); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, host, storeURI)
+}
+
 func (ix *ImageX) signKeys(t time.Time) []byte {
 	h := makeHMac([]byte("AWS4"+ix.SecretKey), []byte(t.Format(shortTimeFormat)))
 	h = makeHMac(h, []byte(ix.Region))
@@ -364,9 +402,17 @@ func makeHMac(key []byte, data []byte) []byte {
 
 // Upload upload image to ByteDance Storage, support local file and web resource
 func (ix *ImageX) Upload(uploadURL, path, auth string) error {
+	return ix.UploadContext(context.Background(), uploadURL, path, auth)
+}
+
+func (ix *ImageX) UploadContext(ctx context.Context, uploadURL, path, auth string) error {
 	var data []byte
 	if utils.IsValidURL(path) {
-		resp, err := http.Get(path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		resp, err := ix.getClient().Do(req)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -388,14 +434,14 @@ func (ix *ImageX) Upload(uploadURL, path, auth string) error {
 		return errors.Trace(err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, uploadURL, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewBuffer(data))
 	if err != nil {
 		return errors.Trace(err)
 	}
 	req.Header.Add("authorization", auth)
 	req.Header.Add("Content-Type", "application/octet-stream")
 	req.Header.Add("content-crc32", crc32)
-	res, err := http.DefaultClient.Do(req)
+	res, err := ix.getClient().Do(req)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -405,7 +451,7 @@ func (ix *ImageX) Upload(uploadURL, path, auth string) error {
 		return errors.Trace(err)
 	}
 	raw := string(b)
-	if gjson.Get(raw, "success").Int() != 0 {
+	if res.StatusCode != http.StatusOK || gjson.Get(raw, "success").Int() != 0 {
 		return errors.Errorf("raw: %s, response: %+v", raw, res)
 	}
 	return nil
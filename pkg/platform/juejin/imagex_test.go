@@ -1,19 +1,24 @@
 package juejin
 
 import (
-	"os"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestUploadImage(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, server := newTestClient(t)
 
-	_, err = client.UploadImage(RegionCNNorth, "./images/go.png")
+	path := "../../../images/go.png"
+	imageURL, err := client.UploadImage(RegionCNNorth, path)
 	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(imageURL, server.URL))
+
+	b, ok := server.Image(strings.TrimPrefix(imageURL, server.URL+"/"))
+	assert.True(t, ok)
+	want, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, want, b)
 }
@@ -0,0 +1,297 @@
+package juejin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// DefaultIndexTTL is how long the cached index is used by default
+	DefaultIndexTTL = 24 * time.Hour
+	// MaxSuggestions is the max number of the similar names suggested for an unknown name
+	MaxSuggestions = 3
+
+	indexFileName = "juejin_index.json"
+)
+
+// Index is all the tags and categories of juejin, which is cached in the config dir,
+// so that the names in articles are resolved without listing all the tags every time.
+type Index struct {
+	Tags       []*TagItem      `json:"tags"`
+	Categories []*CategoryItem `json:"categories"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// IndexFilePath returns the path of the index in the cache dir
+func IndexFilePath(dir string) string {
+	return filepath.Join(dir, indexFileName)
+}
+
+// LoadIndex loads the index from file, nil is returned if file does not exist
+func LoadIndex(file string) (*Index, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	index := new(Index)
+	if err = json.Unmarshal(b, index); err != nil {
+		return nil, errors.Errorf("invalid index data: %s", file)
+	}
+	return index, nil
+}
+
+// Save writes the index to file
+func (i *Index) Save(file string) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = ioutil.WriteFile(file, b, 0644)
+	return errors.Trace(err)
+}
+
+// Expired reports whether the index is older than ttl
+func (i *Index) Expired(ttl time.Duration) bool {
+	return time.Since(i.UpdatedAt) > ttl
+}
+
+// TagNames returns the names of all the tags
+func (i *Index) TagNames() []string {
+	names := make([]string, 0, len(i.Tags))
+	for _, t := range i.Tags {
+		if t.Tag != nil {
+			names = append(names, t.Tag.Name)
+		}
+	}
+	return names
+}
+
+// CategoryNames returns the names of all the categories
+func (i *Index) CategoryNames() []string {
+	names := make([]string, 0, len(i.Categories))
+	for _, c := range i.Categories {
+		if c.Category != nil {
+			names = append(names, c.Category.Name)
+		}
+	}
+	return names
+}
+
+// FindTag returns the tag by name, the name is replaced by its alias first and matched case-insensitively,
+// a not found error with the similar names is returned if no tag matches.
+func (i *Index) FindTag(name string, aliases map[string]string) (*TagItem, error) {
+	for k, v := range aliases {
+		if strings.EqualFold(k, name) {
+			name = v
+			break
+		}
+	}
+	names := i.TagNames()
+	n := match(names, name)
+	if n == "" {
+		return nil, notFound("tag", name, names)
+	}
+	for _, t := range i.Tags {
+		if t.Tag != nil && t.Tag.Name == n {
+			return t, nil
+		}
+	}
+	return nil, notFound("tag", name, names)
+}
+
+// FindCategory returns the category by name, the name is matched case-insensitively,
+// a not found error with the similar names is returned if no category matches.
+func (i *Index) FindCategory(name string) (*CategoryItem, error) {
+	names := i.CategoryNames()
+	n := match(names, name)
+	for _, c := range i.Categories {
+		if n != "" && c.Category != nil && c.Category.Name == n {
+			return c, nil
+		}
+	}
+	return nil, notFound("category", name, names)
+}
+
+// match returns the name which equals to s, or equals to s case-insensitively
+func match(names []string, s string) string {
+	for _, n := range names {
+		if n == s {
+			return n
+		}
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, s) {
+			return n
+		}
+	}
+	return ""
+}
+
+func notFound(kind, name string, names []string) error {
+	suggestions := Suggest(names, name)
+	if len(suggestions) == 0 {
+		return errors.NotFoundf("%s %q", kind, name)
+	}
+	return errors.NewNotFound(nil, fmt.Sprintf("%s %q not found, did you mean %s?", kind, name, strings.Join(suggestions, ", ")))
+}
+
+// Suggest returns at most MaxSuggestions names which are similar to s, the most similar one first
+func Suggest(names []string, s string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	s = strings.ToLower(s)
+	candidates := make([]*candidate, 0)
+	for _, n := range names {
+		l := strings.ToLower(n)
+		d := distance(l, s)
+		// A typo is allowed for every 3 characters, and the names containing each other are similar
+		if d > len([]rune(s))/3+1 && !(s != "" && (strings.Contains(l, s) || strings.Contains(s, l))) {
+			continue
+		}
+		candidates = append(candidates, &candidate{name: n, distance: d})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	suggestions := make([]string, 0, MaxSuggestions)
+	for _, c := range candidates {
+		if len(suggestions) == MaxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// distance is the levenshtein distance between a and b in characters
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(n int, others ...int) int {
+	for _, o := range others {
+		if o < n {
+			n = o
+		}
+	}
+	return n
+}
+
+// SyncIndex lists all the tags and categories, and caches them in IndexFile
+func (c *Client) SyncIndex() (*Index, error) {
+	return c.SyncIndexContext(context.Background())
+}
+
+func (c *Client) SyncIndexContext(ctx context.Context) (*Index, error) {
+	tags, err := c.ListAllTagsContext(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	categories, err := c.ListCategoriesContext(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	index := &Index{
+		Tags:       tags,
+		Categories: categories,
+		UpdatedAt:  time.Now(),
+	}
+	if c.IndexFile != "" {
+		if err = index.Save(c.IndexFile); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	c.indexMu.Lock()
+	c.index, c.indexSynced = index, true
+	c.indexMu.Unlock()
+	return index, nil
+}
+
+// Index returns the index in memory, or the one cached in IndexFile if it is not expired,
+// otherwise all the tags and categories are listed again.
+func (c *Client) Index() (*Index, error) {
+	return c.IndexContext(context.Background())
+}
+
+func (c *Client) IndexContext(ctx context.Context) (*Index, error) {
+	c.indexMu.Lock()
+	index := c.index
+	c.indexMu.Unlock()
+	if index != nil {
+		return index, nil
+	}
+
+	if c.IndexFile != "" {
+		index, err := LoadIndex(c.IndexFile)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ttl := c.IndexTTL
+		if ttl == 0 {
+			ttl = DefaultIndexTTL
+		}
+		if index != nil && !index.Expired(ttl) {
+			c.indexMu.Lock()
+			c.index = index
+			c.indexMu.Unlock()
+			return index, nil
+		}
+	}
+	index, err := c.SyncIndexContext(ctx)
+	return index, errors.Trace(err)
+}
+
+// resolve calls find with the index, the index is synced and find is called again
+// if the name is not found in the cached index, e.g. a new tag.
+func (c *Client) resolve(ctx context.Context, find func(index *Index) error) error {
+	index, err := c.IndexContext(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = find(index)
+	if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	c.indexMu.Lock()
+	synced := c.indexSynced
+	c.indexMu.Unlock()
+	if synced {
+		return errors.Trace(err)
+	}
+	if index, err = c.SyncIndexContext(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(find(index))
+}
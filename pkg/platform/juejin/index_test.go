@@ -0,0 +1,118 @@
+package juejin
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
+)
+
+// countTransport counts the requests by path
+type countTransport struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (t *countTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.counts[req.URL.Path]++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *countTransport) count(path string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.counts[path]
+}
+
+func newIndexClient(t *testing.T, file string) (*Client, *countTransport) {
+	server := fake.NewJuejin()
+	t.Cleanup(server.Close)
+
+	rt := &countTransport{counts: make(map[string]int)}
+	client, err := NewClient(fake.Cookie, WithBaseAPI(server.URL), WithHTTP(transport.WithTransport(rt), transport.WithRateLimit(-1)),
+		WithIndexFile(file, time.Hour), WithTagAliases(map[string]string{"golang": "Go"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, rt
+}
+
+func TestIndexCache(t *testing.T) {
+	file := filepath.Join(t.TempDir(), indexFileName)
+	client, rt := newIndexClient(t, file)
+
+	ids, err := ConvertTagNamesToIDs(client, []string{"GOLANG", "linux", "Go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"6809640364677267469", "6809640385980137480"}, ids)
+	category, err := GetCategoryByName(client, "后端")
+	assert.Nil(t, err)
+	assert.Equal(t, "6809637769959178254", category.ID)
+	assert.Equal(t, 1, rt.count("/tag_api/v1/query_tag_list"))
+	assert.Equal(t, 1, rt.count("/tag_api/v1/query_category_list"))
+
+	// A new client uses the cached index without listing the tags
+	client, rt = newIndexClient(t, file)
+	_, err = ConvertTagNamesToIDs(client, []string{"Docker"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, rt.count("/tag_api/v1/query_tag_list"))
+
+	// The unknown names in the cached index are listed again once
+	_, err = GetCategoryByName(client, "Unknown")
+	assert.True(t, errors.IsNotFound(err))
+	_, err = GetCategoryByName(client, "Unknown")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, 1, rt.count("/tag_api/v1/query_category_list"))
+
+	// The expired index is listed again
+	index, err := LoadIndex(file)
+	assert.Nil(t, err)
+	index.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	assert.Nil(t, index.Save(file))
+	client, rt = newIndexClient(t, file)
+	_, err = client.Index()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rt.count("/tag_api/v1/query_tag_list"))
+}
+
+func TestSuggest(t *testing.T) {
+	index := &Index{
+		Tags: []*TagItem{
+			{ID: "1", Tag: &Tag{Name: "Docker"}},
+			{ID: "2", Tag: &Tag{Name: "Go"}},
+			{ID: "3", Tag: &Tag{Name: "Kubernetes"}},
+			{ID: "4", Tag: &Tag{Name: "Docker Compose"}},
+		},
+		Categories: []*CategoryItem{
+			{ID: "1", Category: &Category{Name: "后端"}},
+		},
+	}
+
+	tag, err := index.FindTag("docker", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", tag.ID)
+
+	_, err = index.FindTag("Dokcer", nil)
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, `tag "Dokcer" not found, did you mean Docker?`, err.Error())
+
+	_, err = index.FindTag("kubernete", nil)
+	assert.True(t, strings.HasSuffix(err.Error(), "did you mean Kubernetes?"))
+
+	_, err = index.FindTag("Rust", nil)
+	assert.Equal(t, `tag "Rust" not found`, err.Error())
+
+	assert.Equal(t, []string{"Docker", "Docker Compose"}, Suggest(index.TagNames(), "docker"))
+
+	_, err = index.FindCategory("人工智能")
+	assert.Equal(t, `category "人工智能" not found`, err.Error())
+}
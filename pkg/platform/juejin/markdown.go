@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -14,10 +15,19 @@ type SaveType string
 const (
 	SaveTypeArticle SaveType = "article"
 	SaveTypeDraft   SaveType = "draft"
+
+	// The brief content out of the range is replaced by the beginning of the content
+	MinBriefLength = 50
+	MaxBriefLength = 100
 )
 
 // ParseMark parse mark to article params
 func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err error) {
+	return c.ParseMarkContext(context.Background(), mark)
+}
+
+// ParseMarkContext parse mark to article params, the tags and the category are resolved with ctx
+func (c *Client) ParseMarkContext(ctx context.Context, mark *markdown.Mark) (params *SaveArticleParams, err error) {
 	v := mark.Meta.Get("juejin")
 	if v == nil {
 		err = errors.New("juejin meta not found")
@@ -55,10 +65,10 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 		params.Brief = mark.Brief
 	}
 	briefContentLen := len([]rune(params.Brief))
-	if briefContentLen > 100 {
+	if briefContentLen > MaxBriefLength {
 		s := compressContent(params.Brief)
 		params.Brief = string([]rune(s)[:80])
-	} else if briefContentLen < 50 {
+	} else if briefContentLen < MinBriefLength {
 		s := compressContent(params.Content)
 		params.Brief = string([]rune(s)[:80])
 	}
@@ -73,7 +83,7 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 	}
 
 	tags := meta.GetStringSlice("tags")
-	params.TagIDs, err = ConvertTagNamesToIDs(c, tags)
+	params.TagIDs, err = ConvertTagNamesToIDsContext(ctx, c, tags)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -81,7 +91,7 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 
 	category := meta.GetString("category")
 	var categoryItem *CategoryItem
-	categoryItem, err = GetCategoryByName(c, category)
+	categoryItem, err = GetCategoryByNameContext(ctx, c, category)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -93,7 +103,15 @@ func (c *Client) ParseMark(mark *markdown.Mark) (params *SaveArticleParams, err
 	return
 }
 
+// WriteBack update the juejin meta of mark and write it back to the markdown file
 func WriteBack(saveType SaveType, mark *markdown.Mark, params *SaveArticleParams, isCreate bool) error {
+	UpdateMeta(saveType, mark, params, isCreate)
+	err := mark.WriteFile(mark.File)
+	return errors.Trace(err)
+}
+
+// UpdateMeta update the juejin meta of mark without writing the markdown file
+func UpdateMeta(saveType SaveType, mark *markdown.Mark, params *SaveArticleParams, isCreate bool) {
 	now := time.Now().Format("2006-01-02 15:04:05")
 	v := mark.Meta.Get("juejin")
 	meta, _ := v.(markdown.Meta)
@@ -110,8 +128,6 @@ func WriteBack(saveType SaveType, mark *markdown.Mark, params *SaveArticleParams
 		meta = meta.Set("article_id", params.ArticleID)
 	}
 	mark.Meta = mark.Meta.Set("juejin", meta)
-	err := mark.WriteFile(mark.File)
-	return errors.Trace(err)
 }
 
 func compressContent(s string) string {
@@ -120,3 +136,50 @@ func compressContent(s string) string {
 	s = strings.Replace(s, "\t", "", -1)
 	return s
 }
+
+// NewMark converts an article into mark, the juejin meta is pre-filled with the article
+func NewMark(article *Article) *markdown.Mark {
+	info := article.Info
+	return newMark(info.Title, info.MarkContent, info.BriefContent, info.CoverImage,
+		article.Category, article.Tags, article.ID, info.DraftID)
+}
+
+// NewDraftMark converts a draft into mark, the juejin meta is pre-filled with the draft
+func NewDraftMark(detail *DraftDetail) *markdown.Mark {
+	d := detail.Draft
+	return newMark(d.Title, d.MarkContent, d.BriefContent, d.CoverImage,
+		detail.Category, detail.Tags, d.ArticleID, d.ID)
+}
+
+func newMark(title, content, brief, coverImage string, category *Category, tags []*Tag, articleID, draftID string) *markdown.Mark {
+	var meta markdown.Meta
+	if category != nil {
+		meta = meta.Set("category", category.Name)
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, t := range tags {
+		tagNames = append(tagNames, t.Name)
+	}
+	meta = meta.Set("tags", tagNames)
+	if coverImage != "" {
+		meta = meta.Set("cover_image", coverImage)
+	}
+	if brief != "" {
+		meta = meta.Set("brief_content", brief)
+	}
+	if articleID != "" && articleID != "0" {
+		meta = meta.Set("article_id", articleID)
+	}
+	if draftID != "" {
+		meta = meta.Set("draft_id", draftID)
+	}
+
+	var root markdown.Meta
+	root = root.Set("title", title)
+	root = root.Set("juejin", meta)
+	return &markdown.Mark{
+		Meta:    root,
+		Content: markdown.EnsureNewline(content),
+		Brief:   brief,
+	}
+}
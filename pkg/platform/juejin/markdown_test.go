@@ -0,0 +1,54 @@
+package juejin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+func TestNewMark(t *testing.T) {
+	article := &Article{
+		ID: "1",
+		Info: &ArticleInfo{
+			Title:       "Title",
+			DraftID:     "2",
+			CoverImage:  "https://cdn.com/cover.png",
+			MarkContent: "# Title",
+		},
+		Category: &Category{Name: "后端"},
+		Tags:     []*Tag{{Name: "Go"}, {Name: "Linux"}},
+	}
+	mark := NewMark(article)
+	assert.Equal(t, "Title", mark.Meta.GetString("title"))
+	assert.Equal(t, "后端", mark.Meta.GetString("juejin.category"))
+	assert.Equal(t, []string{"Go", "Linux"}, mark.Meta.Get("juejin.tags"))
+	assert.Equal(t, "https://cdn.com/cover.png", mark.Meta.GetString("juejin.cover_image"))
+	assert.Equal(t, "1", mark.Meta.GetString("juejin.article_id"))
+	assert.Equal(t, "2", mark.Meta.GetString("juejin.draft_id"))
+	assert.Equal(t, "# Title\n", mark.Content)
+}
+
+func TestParseMarkContext(t *testing.T) {
+	client, _ := newTestClient(t)
+	juejin := markdown.Meta{}.Set("tags", []interface{}{"Go"}).Set("category", "后端")
+	mark := &markdown.Mark{
+		Meta:    markdown.Meta{}.Set("title", "Title").Set("juejin", juejin),
+		Content: strings.Repeat("content ", 20),
+	}
+
+	// The tags and the category are not listed after ctx is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.ParseMarkContext(ctx, mark)
+	assert.NotNil(t, err)
+
+	params, err := client.ParseMarkContext(context.Background(), mark)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"6809640364677267469"}, params.TagIDs)
+		assert.Equal(t, "6809637769959178254", params.CategoryID)
+	}
+}
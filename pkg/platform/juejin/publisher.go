@@ -0,0 +1,122 @@
+package juejin
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+const PlatformName = "juejin"
+
+func init() {
+	platform.Register(PlatformName, NewPublisher)
+}
+
+// Publisher implements platform.Publisher for juejin.cn
+type Publisher struct {
+	Client *Client
+}
+
+var (
+	_ platform.Publisher     = (*Publisher)(nil)
+	_ platform.ImageUploader = (*Publisher)(nil)
+	_ platform.ArticleFinder = (*Publisher)(nil)
+)
+
+// NewPublisher creates a Publisher with the cookie and the settings in opts
+func NewPublisher(opts *platform.Options) (platform.Publisher, error) {
+	client, err := NewClient(opts.Cookie, WithOptions(opts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Publisher{Client: client}, nil
+}
+
+func (p *Publisher) Name() string {
+	return PlatformName
+}
+
+func (p *Publisher) Publish(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMarkContext(ctx, mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	isCreate := params.ArticleID == ""
+	if err = p.Client.SaveArticleContext(ctx, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &platform.Result{
+		Platform:  PlatformName,
+		SaveType:  platform.SaveTypeArticle,
+		ArticleID: params.ArticleID,
+		DraftID:   params.DraftID,
+		URL:       BuildArticleURL(params.ArticleID),
+		IsCreate:  isCreate,
+	}, nil
+}
+
+func (p *Publisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMarkContext(ctx, mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	isCreate := params.DraftID == ""
+	if err = p.Client.SaveDraftContext(ctx, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &platform.Result{
+		Platform:  PlatformName,
+		SaveType:  platform.SaveTypeDraft,
+		ArticleID: params.ArticleID,
+		DraftID:   params.DraftID,
+		URL:       BuildDraftEditorURL(params.DraftID),
+		IsCreate:  isCreate,
+	}, nil
+}
+
+func (p *Publisher) DeleteArticle(ctx context.Context, id string) error {
+	return errors.Trace(p.Client.DeleteArticleContext(ctx, id))
+}
+
+func (p *Publisher) WriteBack(mark *markdown.Mark, result *platform.Result) error {
+	params := &SaveArticleParams{
+		ArticleID: result.ArticleID,
+		DraftID:   result.DraftID,
+	}
+	UpdateMeta(SaveType(result.SaveType), mark, params, result.IsCreate)
+	return nil
+}
+
+func (p *Publisher) FindArticles(ctx context.Context, keyword string) ([]*platform.Article, error) {
+	articles, _, err := p.Client.ListArticlesContext(ctx, keyword, 1, MaxPageSize, AuditStatusAll)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*platform.Article, 0, len(articles))
+	for _, a := range articles {
+		if a.Info == nil {
+			continue
+		}
+		article := &platform.Article{
+			ID:      a.ID,
+			DraftID: a.Info.DraftID,
+			Title:   a.Info.Title,
+			URL:     BuildArticleURL(a.ID),
+		}
+		if ctime, err := strconv.ParseInt(a.Info.CreateTime, 10, 64); err == nil {
+			article.CreateTime = time.Unix(ctime, 0)
+		}
+		result = append(result, article)
+	}
+	return result, nil
+}
+
+func (p *Publisher) UploadImage(ctx context.Context, path string) (string, error) {
+	imageURL, err := p.Client.UploadImageContext(ctx, RegionCNNorth, path)
+	return imageURL, errors.Trace(err)
+}
@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/fatih/color"
 	"github.com/juju/errors"
@@ -17,13 +18,17 @@ type TagItem struct {
 
 // ListTags list tags by keyword
 func (c *Client) ListTags(key string, cursor string) (tags []*TagItem, nextCursor string, err error) {
+	return c.ListTagsContext(context.Background(), key, cursor)
+}
+
+func (c *Client) ListTagsContext(ctx context.Context, key string, cursor string) (tags []*TagItem, nextCursor string, err error) {
 	endpoint := "/tag_api/v1/query_tag_list"
 	payload := map[string]interface{}{
 		"key_word": key,
 		"cursor":   cursor,
 	}
 	var raw string
-	raw, err = c.Post(endpoint, payload)
+	raw, err = c.PostContext(ctx, endpoint, payload)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -40,10 +45,14 @@ func (c *Client) ListTags(key string, cursor string) (tags []*TagItem, nextCurso
 }
 
 func (c *Client) ListAllTags() (result []*TagItem, err error) {
+	return c.ListAllTagsContext(context.Background())
+}
+
+func (c *Client) ListAllTagsContext(ctx context.Context) (result []*TagItem, err error) {
 	cursor := StartCursor
 	for {
 		var tags []*TagItem
-		tags, cursor, err = c.ListTags("", cursor)
+		tags, cursor, err = c.ListTagsContext(ctx, "", cursor)
 		if err != nil {
 			err = errors.Trace(err)
 			return
@@ -56,23 +65,38 @@ func (c *Client) ListAllTags() (result []*TagItem, err error) {
 	return
 }
 
+// ConvertTagNamesToIDs converts the tag names to ids with the index of client,
+// the unknown tags are ignored with a warning of the similar names.
 func ConvertTagNamesToIDs(client *Client, names []string) (ids []string, err error) {
-	tags, err := client.ListAllTags()
-	if err != nil {
-		err = errors.Trace(err)
-		return
-	}
+	return ConvertTagNamesToIDsContext(context.Background(), client, names)
+}
 
-	nameIDMap := make(map[string]string)
-	for _, t := range tags {
-		nameIDMap[t.Tag.Name] = t.ID
-	}
-	for _, name := range names {
-		if id, ok := nameIDMap[name]; ok {
-			ids = append(ids, id)
-		} else {
-			color.Yellow("! Tag '%s' not found!", name)
+func ConvertTagNamesToIDsContext(ctx context.Context, client *Client, names []string) (ids []string, err error) {
+	var missing []error
+	err = client.resolve(ctx, func(index *Index) error {
+		ids, missing = nil, nil
+		seen := make(map[string]bool)
+		for _, name := range names {
+			t, err := index.FindTag(name, client.TagAliases)
+			if err != nil {
+				missing = append(missing, err)
+				continue
+			}
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				ids = append(ids, t.ID)
+			}
 		}
+		if len(missing) > 0 {
+			return missing[0]
+		}
+		return nil
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
 	}
-	return
+	for _, e := range missing {
+		color.Yellow("! %s", e)
+	}
+	return ids, nil
 }
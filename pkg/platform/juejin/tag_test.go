@@ -1,33 +1,42 @@
 package juejin
 
 import (
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestListTags(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
-	tags, count, err := client.ListTags("docker", StartCursor)
+	tags, cursor, err := client.ListTags("docker", StartCursor)
 	assert.Nil(t, err)
-	assert.NotEqual(t, 0, len(tags))
-	assert.NotEqual(t, 0, count)
+	assert.Equal(t, 1, len(tags))
+	assert.Equal(t, "Docker", tags[0].Tag.Name)
+	assert.Equal(t, "", cursor)
+}
+
+func TestListAllTags(t *testing.T) {
+	client, server := newTestClient(t)
+	server.TagPageSize = 1
+
+	tags, err := client.ListAllTags()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(tags))
+
+	ids, err := ConvertTagNamesToIDs(client, []string{"Go", "Unknown", "Linux"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"6809640364677267469", "6809640385980137480"}, ids)
 }
 
 func TestListAllCategories(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_JUEJIN_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
 	categories, err := client.ListCategories()
 	assert.Nil(t, err)
 	assert.NotEqual(t, 0, len(categories))
+
+	category, err := GetCategoryByName(client, "后端")
+	assert.Nil(t, err)
+	assert.Equal(t, "6809637769959178254", category.ID)
 }
@@ -1,6 +1,7 @@
 package juejin
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/juju/errors"
@@ -65,8 +66,12 @@ type Badge struct {
 }
 
 func (c *Client) GetUser() (*User, error) {
+	return c.GetUserContext(context.Background())
+}
+
+func (c *Client) GetUserContext(ctx context.Context) (*User, error) {
 	endpoint := "/user_api/v1/user/get"
-	raw, err := c.Get(endpoint, nil)
+	raw, err := c.GetContext(ctx, endpoint, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
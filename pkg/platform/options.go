@@ -0,0 +1,60 @@
+package platform
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/k8scat/articli/pkg/transport"
+)
+
+// Options are the account and the http settings of a platform client, the zero value uses the defaults
+type Options struct {
+	// Cookie is the cookie of a logged in user of juejin, oschina and csdn
+	Cookie string
+	// Token is the access token of github and gitlab
+	Token string
+	// BaseURL overrides the base url of the platform api, e.g. the api of a GitHub Enterprise Server or a local mock,
+	// it is the url of the home page for oschina
+	BaseURL string
+	// ImageURL overrides the base url of the image service, e.g. the imagex api of juejin
+	ImageURL string
+
+	// HTTPClient sends the requests instead of the default client of the platform
+	HTTPClient *http.Client
+	UserAgent  string
+	Timeout    time.Duration
+	// Proxy is the http proxy, the proxy in the environment variables is used if nil
+	Proxy *url.URL
+	// RateLimit is the requests per second, the default limit of the platform is used if 0,
+	// and the requests are not limited if negative
+	RateLimit float64
+
+	// CacheDir is where the platform data is cached, e.g. the tags of juejin, nothing is cached on disk if empty
+	CacheDir string
+	// CacheTTL is how long the cached data is used, the default ttl of the platform is used if 0
+	CacheTTL time.Duration
+	// TagAliases maps the tag names in articles to the names in the platform
+	TagAliases map[string]string
+}
+
+// HTTPOptions returns the http options of the client which are set in o
+func (o *Options) HTTPOptions() []transport.HTTPOption {
+	var opts []transport.HTTPOption
+	if o.HTTPClient != nil {
+		opts = append(opts, transport.WithHTTPClient(o.HTTPClient))
+	}
+	if o.Timeout > 0 {
+		opts = append(opts, transport.WithTimeout(o.Timeout))
+	}
+	if o.Proxy != nil {
+		opts = append(opts, transport.WithProxy(o.Proxy))
+	}
+	if o.UserAgent != "" {
+		opts = append(opts, transport.WithUserAgent(o.UserAgent))
+	}
+	if o.RateLimit != 0 {
+		opts = append(opts, transport.WithRateLimit(o.RateLimit))
+	}
+	return opts
+}
@@ -1,6 +1,7 @@
 package oschina
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -52,6 +53,7 @@ type ContentParams struct {
 	Type           ArticleType `url:"type"`         // 原创、转载
 	ContentType    string      `url:"content_type"`
 	PublishAsBlog  int         `url:"publish_as_blog"`
+	CategoryName   string      `url:"-"`
 }
 
 func (p *ContentParams) Validate() error {
@@ -72,6 +74,10 @@ func (p *ContentParams) Validate() error {
 
 // SaveArticle create an article if id is empty, otherwise update existed article.
 func (c *Client) SaveArticle(params *ContentParams) error {
+	return c.SaveArticleContext(context.Background(), params)
+}
+
+func (c *Client) SaveArticleContext(ctx context.Context, params *ContentParams) error {
 	if err := params.Validate(); err != nil {
 		return errors.Trace(err)
 	}
@@ -79,7 +85,7 @@ func (c *Client) SaveArticle(params *ContentParams) error {
 	var rawurl string
 	if params.ID == "" {
 		if params.DraftID == "" {
-			if err := c.SaveDraft(params); err != nil {
+			if err := c.SaveDraftContext(ctx, params); err != nil {
 				return errors.Trace(err)
 			}
 		}
@@ -93,7 +99,7 @@ func (c *Client) SaveArticle(params *ContentParams) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	raw, err := c.Post(rawurl, values, DefaultHandler)
+	raw, err := c.PostContext(ctx, rawurl, values, DefaultHandler)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -108,6 +114,10 @@ func (c *Client) SaveArticle(params *ContentParams) error {
 }
 
 func (c *Client) DeleteArticle(id string) error {
+	return c.DeleteArticleContext(context.Background(), id)
+}
+
+func (c *Client) DeleteArticleContext(ctx context.Context, id string) error {
 	if id == "" {
 		return errors.New("article id is required")
 	}
@@ -116,7 +126,7 @@ func (c *Client) DeleteArticle(id string) error {
 		"user_code": []string{c.UserCode},
 		"id":        []string{id},
 	}
-	_, err := c.Post(rawurl, values, DefaultHandler)
+	_, err := c.PostContext(ctx, rawurl, values, DefaultHandler)
 	return err
 }
 
@@ -127,12 +137,16 @@ type Article struct {
 }
 
 func (c *Client) ListArticles(page int, keyword string) (articles []*Article, hasNext bool, err error) {
+	return c.ListArticlesContext(context.Background(), page, keyword)
+}
+
+func (c *Client) ListArticlesContext(ctx context.Context, page int, keyword string) (articles []*Article, hasNext bool, err error) {
 	if page < 1 {
 		page = 1
 	}
 	path := fmt.Sprintf("%s%s", c.BaseURL,
 		fmt.Sprintf("/widgets/_space_index_newest_blog?catalogId=0&q=%s&sortType=time&type=ajax&p=%d", keyword, page))
-	raw, err := c.Get(path, nil, nil)
+	raw, err := c.GetContext(ctx, path, nil, nil)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -176,6 +190,24 @@ func (c *Client) ListArticles(page int, keyword string) (articles []*Article, ha
 	return
 }
 
+// GetArticleDetail returns the content params of an article from the editor page
+func (c *Client) GetArticleDetail(id string) (*ContentParams, error) {
+	return c.GetArticleDetailContext(context.Background(), id)
+}
+
+func (c *Client) GetArticleDetailContext(ctx context.Context, id string) (*ContentParams, error) {
+	result, err := c.getEditorDetail(ctx, c.BuildArticleEditorURL(id))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.ID = id
+	return result, nil
+}
+
+func (c *Client) BuildArticleEditorURL(id string) string {
+	return fmt.Sprintf("%s/blog/write/edit/%s", c.BaseURL, id)
+}
+
 func (c *Client) BuildArticleURL(id string) string {
 	return fmt.Sprintf("%s/blog/%s", c.BaseURL, id)
 }
@@ -1,19 +1,70 @@
 package oschina
 
 import (
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func TestSaveArticle(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &ContentParams{
+		Title:       "Title",
+		Content:     "# Title",
+		Category:    "7000002",
+		OriginalURL: "https://example.com",
+		Privacy:     1,
+	}
+	err := client.SaveArticle(params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", params.ID)
+
+	detail, err := client.GetArticleDetail(params.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", detail.Title)
+	assert.Equal(t, "# Title", detail.Content)
+	assert.Equal(t, "https://example.com", detail.OriginalURL)
+	assert.Equal(t, ArticleTypeReship, detail.Type)
+	assert.Equal(t, 1, detail.Privacy)
+	assert.Equal(t, 0, detail.Top)
+	assert.Equal(t, "日常记录", detail.CategoryName)
+
+	detail.Title = "New Title"
+	err = client.SaveArticle(detail)
+	assert.Nil(t, err)
+
+	drafts, _, err := client.ListDrafts(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(drafts))
+}
+
 func TestListArticles(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
+	client, _ := newTestClient(t)
+
+	for _, title := range []string{"Go", "Docker", "Go Modules"} {
+		err := client.SaveArticle(&ContentParams{Title: title, Content: title})
+		assert.Nil(t, err)
 	}
 
-	_, _, err = client.ListArticles(1, "")
+	articles, hasNext, err := client.ListArticles(1, "Go")
 	assert.Nil(t, err)
+	assert.False(t, hasNext)
+	assert.Equal(t, 2, len(articles))
+	assert.Equal(t, "Go Modules", articles[0].Title)
+	assert.Equal(t, client.BuildArticleURL(articles[0].ID), articles[0].URL)
+}
+
+func TestDeleteArticle(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	params := &ContentParams{Title: "Title", Content: "# Title"}
+	err := client.SaveArticle(params)
+	assert.Nil(t, err)
+
+	err = client.DeleteArticle(params.ID)
+	assert.Nil(t, err)
+
+	_, err = client.GetArticleDetail(params.ID)
+	assert.NotNil(t, err)
 }
@@ -1,6 +1,7 @@
 package oschina
 
 import (
+	"context"
 	"github.com/antchfx/htmlquery"
 	"github.com/juju/errors"
 	"net/url"
@@ -14,8 +15,12 @@ type Category struct {
 
 // ListCategories list all categories
 func (c *Client) ListCategories() ([]*Category, error) {
+	return c.ListCategoriesContext(context.Background())
+}
+
+func (c *Client) ListCategoriesContext(ctx context.Context) ([]*Category, error) {
 	rawurl := c.BuildURL("/blog/write")
-	raw, err := c.Get(rawurl, nil, nil)
+	raw, err := c.GetContext(ctx, rawurl, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -45,6 +50,10 @@ func (c *Client) ListCategories() ([]*Category, error) {
 
 // AddCategory add a new category
 func (c *Client) AddCategory(name string) error {
+	return c.AddCategoryContext(context.Background(), name)
+}
+
+func (c *Client) AddCategoryContext(ctx context.Context, name string) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return errors.New("name is empty")
@@ -56,12 +65,16 @@ func (c *Client) AddCategory(name string) error {
 		"user_code": []string{c.UserCode},
 		"name":      []string{name},
 	}
-	_, err := c.Post(rawurl, values, DefaultHandler)
+	_, err := c.PostContext(ctx, rawurl, values, DefaultHandler)
 	return errors.Trace(err)
 }
 
 func (c *Client) GetCategoryByName(name string) (*Category, error) {
-	categories, err := c.ListCategories()
+	return c.GetCategoryByNameContext(context.Background(), name)
+}
+
+func (c *Client) GetCategoryByNameContext(ctx context.Context, name string) (*Category, error) {
+	categories, err := c.ListCategoriesContext(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1,18 +1,21 @@
 package oschina
 
 import (
-	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestListCategories(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
-	_, err = client.ListCategories()
+	categories, err := client.ListCategories()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(categories))
+
+	err = client.AddCategory("Go")
+	assert.Nil(t, err)
+	category, err := client.GetCategoryByName("Go")
 	assert.Nil(t, err)
+	assert.NotNil(t, category)
 }
@@ -1,6 +1,7 @@
 package oschina
 
 import (
+	"context"
 	"fmt"
 	"github.com/juju/errors"
 	"io"
@@ -10,23 +11,65 @@ import (
 	"regexp"
 	"strings"
 
-	browser "github.com/EDDYCJY/fake-useragent"
 	"github.com/tidwall/gjson"
+
+	"github.com/k8scat/articli/pkg/platform"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
+const DefaultHomeURL = "https://www.oschina.net/"
+
 type Client struct {
+	HomeURL  string
 	BaseURL  string
 	Cookie   string
 	UserCode string
 	UserID   string
 	SpaceID  string
 	UserName string
+	// HTTP sends all the requests of the client, a random user agent of browsers is sent if the user agent is empty
+	transport.HTTP
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithHomeURL sets the url of the home page which the user data is parsed from,
+// the base url of the user space is parsed from the home page too
+func WithHomeURL(homeURL string) Option {
+	return func(c *Client) {
+		c.HomeURL = homeURL
+	}
 }
 
-func NewClient(cookie string) (*Client, error) {
+// WithHTTP applies the http options shared by the clients of the platforms, e.g. transport.WithTimeout
+func WithHTTP(opts ...transport.HTTPOption) Option {
+	return func(c *Client) {
+		c.Apply(opts...)
+	}
+}
+
+// WithOptions applies the http settings in opts, the base url overrides the home url,
+// the cookie in opts is passed to NewClient by the caller
+func WithOptions(opts *platform.Options) Option {
+	return func(c *Client) {
+		if opts.BaseURL != "" {
+			c.HomeURL = opts.BaseURL
+		}
+		c.Apply(opts.HTTPOptions()...)
+	}
+}
+
+func NewClient(cookie string, opts ...Option) (*Client, error) {
 	client := &Client{
-		Cookie: cookie,
+		HomeURL: DefaultHomeURL,
+		Cookie:  cookie,
+		HTTP:    transport.HTTP{HTTPClient: http.DefaultClient},
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.Wrap(PlatformName)
 	err := parseUser(client)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -36,7 +79,7 @@ func NewClient(cookie string) (*Client, error) {
 
 // parseUser parse user data from html
 func parseUser(c *Client) error {
-	raw, err := c.Get("https://www.oschina.net/", nil, nil)
+	raw, err := c.Get(c.HomeURL, nil, nil)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -66,6 +109,7 @@ func parseUser(c *Client) error {
 		r := regexp.MustCompile(`space_user_id" data-value="(\d+)`).FindStringSubmatch(raw)
 		if len(r) != 2 {
 			ch <- errors.Errorf("space id not found: %v", r)
+			return
 		}
 		c.SpaceID = r[1]
 		ch <- nil
@@ -97,16 +141,20 @@ func parseUser(c *Client) error {
 
 // Get request with GET method and support response handler
 func (c *Client) Get(rawurl string, params *url.Values, handler func(r *http.Response) (string, error)) (string, error) {
-	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	return c.GetContext(context.Background(), rawurl, params, handler)
+}
+
+func (c *Client) GetContext(ctx context.Context, rawurl string, params *url.Values, handler func(r *http.Response) (string, error)) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	req.Header.Set("Cookie", c.Cookie)
-	req.Header.Set("User-Agent", browser.Computer())
+	req.Header.Set("User-Agent", c.BrowserUserAgent())
 	if params != nil {
 		req.URL.RawQuery = params.Encode()
 	}
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -122,20 +170,24 @@ func (c *Client) Get(rawurl string, params *url.Values, handler func(r *http.Res
 }
 
 func (c *Client) Post(path string, values url.Values, handler func(r *http.Response) (string, error)) (string, error) {
+	return c.PostContext(context.Background(), path, values, handler)
+}
+
+func (c *Client) PostContext(ctx context.Context, path string, values url.Values, handler func(r *http.Response) (string, error)) (string, error) {
 	var body io.Reader
 	if values != nil {
 		values.Add("user_code", c.UserCode)
 		body = strings.NewReader(values.Encode())
 	}
-	req, err := http.NewRequest(http.MethodPost, path, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	req.Header.Add("Cookie", c.Cookie)
-	req.Header.Add("User-Agent", browser.Computer())
+	req.Header.Add("User-Agent", c.BrowserUserAgent())
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
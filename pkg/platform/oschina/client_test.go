@@ -1,12 +1,35 @@
 package oschina
 
 import (
-	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/platform/fake"
+	"github.com/k8scat/articli/pkg/transport"
 )
 
+// newTestClient returns a client of a fake oschina server which is closed when the test finishes
+func newTestClient(t *testing.T) (*Client, *fake.OSChina) {
+	server := fake.NewOSChina()
+	t.Cleanup(server.Close)
+
+	// The fake server is not rate limited
+	client, err := NewClient(fake.Cookie, WithHomeURL(server.URL+"/"), WithHTTP(transport.WithRateLimit(-1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
 func TestNewClient(t *testing.T) {
-	_, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	assert.Nil(t, err)
+	client, server := newTestClient(t)
+	assert.Equal(t, server.BaseURL(), client.BaseURL)
+	assert.Equal(t, server.UserName, client.UserName)
+	assert.Equal(t, server.UserCode, client.UserCode)
+	assert.Equal(t, server.UserID, client.UserID)
+	assert.Equal(t, server.SpaceID, client.SpaceID)
+
+	_, err := NewClient("sessionid=invalid", WithHomeURL(server.URL+"/"))
+	assert.NotNil(t, err)
 }
@@ -1,6 +1,7 @@
 package oschina
 
 import (
+	"context"
 	"fmt"
 	"github.com/antchfx/htmlquery"
 	"github.com/google/go-querystring/query"
@@ -13,6 +14,10 @@ import (
 
 // SaveDraft create a new draft if id is empty, otherwise update draft
 func (c *Client) SaveDraft(params *ContentParams) error {
+	return c.SaveDraftContext(context.Background(), params)
+}
+
+func (c *Client) SaveDraftContext(ctx context.Context, params *ContentParams) error {
 	if err := params.Validate(); err != nil {
 		return errors.Trace(err)
 	}
@@ -22,7 +27,7 @@ func (c *Client) SaveDraft(params *ContentParams) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	raw, err := c.Post(rawurl, values, DefaultHandler)
+	raw, err := c.PostContext(ctx, rawurl, values, DefaultHandler)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -36,11 +41,15 @@ func (c *Client) SaveDraft(params *ContentParams) error {
 }
 
 func (c *Client) DeleteDraft(id string) error {
+	return c.DeleteDraftContext(context.Background(), id)
+}
+
+func (c *Client) DeleteDraftContext(ctx context.Context, id string) error {
 	rawurl := c.BuildURL("/blog/delete_draft")
 	values := url.Values{
 		"id": {id},
 	}
-	_, err := c.Post(rawurl, values, DefaultHandler)
+	_, err := c.PostContext(ctx, rawurl, values, DefaultHandler)
 	return errors.Trace(err)
 }
 
@@ -51,11 +60,15 @@ type Draft struct {
 }
 
 func (c *Client) ListDrafts(page int) (drafts []*Draft, hasNext bool, err error) {
+	return c.ListDraftsContext(context.Background(), page)
+}
+
+func (c *Client) ListDraftsContext(ctx context.Context, page int) (drafts []*Draft, hasNext bool, err error) {
 	if page < 1 {
 		page = 1
 	}
 	path := fmt.Sprintf("%s%s", c.BaseURL, fmt.Sprintf("/admin/drafts?p=%d", page))
-	raw, err := c.Get(path, nil, nil)
+	raw, err := c.GetContext(ctx, path, nil, nil)
 	if err != nil {
 		err = errors.Trace(err)
 		return
@@ -100,8 +113,22 @@ func (c *Client) ListDrafts(page int) (drafts []*Draft, hasNext bool, err error)
 }
 
 func (c *Client) GetDraftDetail(id string) (*ContentParams, error) {
+	return c.GetDraftDetailContext(context.Background(), id)
+}
+
+func (c *Client) GetDraftDetailContext(ctx context.Context, id string) (*ContentParams, error) {
 	path := fmt.Sprintf("%s%s", c.BaseURL, fmt.Sprintf("/blog/write/draft/%s", id))
-	raw, err := c.Get(path, nil, nil)
+	result, err := c.getEditorDetail(ctx, path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.DraftID = id
+	return result, nil
+}
+
+// getEditorDetail parses the content params from the form of the editor page
+func (c *Client) getEditorDetail(ctx context.Context, path string) (*ContentParams, error) {
+	raw, err := c.GetContext(ctx, path, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -259,12 +286,13 @@ func (c *Client) GetDraftDetail(id string) (*ContentParams, error) {
 		for _, attr := range node.Attr {
 			if attr.Key == "selected" {
 				categoryName := node.LastChild.Data
-				category, err := c.GetCategoryByName(categoryName)
+				category, err := c.GetCategoryByNameContext(ctx, categoryName)
 				if err != nil {
 					return nil, errors.Trace(err)
 				}
 				if category != nil {
 					result.Category = category.ID
+					result.CategoryName = category.Name
 				}
 			}
 		}
@@ -273,13 +301,20 @@ func (c *Client) GetDraftDetail(id string) (*ContentParams, error) {
 }
 
 func (c *Client) PublishDraft(id string) (articleID string, err error) {
+	return c.PublishDraftContext(context.Background(), id)
+}
+
+func (c *Client) PublishDraftContext(ctx context.Context, id string) (articleID string, err error) {
 	var params *ContentParams
-	params, err = c.GetDraftDetail(id)
+	params, err = c.GetDraftDetailContext(ctx, id)
 	if err != nil {
 		err = errors.Trace(err)
 		return
 	}
-	err = c.SaveArticle(params)
-	err = errors.Trace(err)
+	if err = c.SaveArticleContext(ctx, params); err != nil {
+		err = errors.Trace(err)
+		return
+	}
+	articleID = params.ID
 	return
 }
@@ -1,51 +1,78 @@
 package oschina
 
 import (
-	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDeleteDraft(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
-	err = client.DeleteDraft("2686724")
+	params := &ContentParams{Title: "Draft", Content: "# Draft"}
+	err := client.SaveDraft(params)
 	assert.Nil(t, err)
+
+	err = client.DeleteDraft(params.DraftID)
+	assert.Nil(t, err)
+
+	_, err = client.GetDraftDetail(params.DraftID)
+	assert.NotNil(t, err)
 }
 
 func TestListDrafts(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
+	client, _ := newTestClient(t)
+
+	for _, title := range []string{"Draft 1", "Draft 2"} {
+		err := client.SaveDraft(&ContentParams{Title: title, Content: title})
+		assert.Nil(t, err)
 	}
 
-	_, _, err = client.ListDrafts(2)
+	drafts, hasNext, err := client.ListDrafts(1)
 	assert.Nil(t, err)
+	assert.False(t, hasNext)
+	assert.Equal(t, 2, len(drafts))
+	assert.Equal(t, "Draft 2", drafts[0].Title)
+
+	drafts, _, err = client.ListDrafts(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(drafts))
 }
 
 func TestGetDraftDetail(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
+	client, _ := newTestClient(t)
+
+	params := &ContentParams{
+		Title:       "Draft",
+		Content:     "# Draft\n\n<b>bold</b>",
+		Category:    "7000001",
+		DenyComment: 1,
 	}
+	err := client.SaveDraft(params)
+	assert.Nil(t, err)
 
-	_, err = client.GetDraftDetail("2686735")
+	detail, err := client.GetDraftDetail(params.DraftID)
 	assert.Nil(t, err)
+	assert.Equal(t, params.DraftID, detail.DraftID)
+	assert.Equal(t, "Draft", detail.Title)
+	assert.Equal(t, "# Draft\n\n<b>bold</b>", detail.Content)
+	assert.Equal(t, ArticleTypeOriginal, detail.Type)
+	assert.Equal(t, 1, detail.DenyComment)
+	assert.Equal(t, "工作日志", detail.CategoryName)
 }
 
 func TestPublishDraft(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
+
+	params := &ContentParams{Title: "Draft", Content: "# Draft"}
+	err := client.SaveDraft(params)
+	assert.Nil(t, err)
+
+	articleID, err := client.PublishDraft(params.DraftID)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", articleID)
 
-	_, err = client.PublishDraft("2686735")
+	detail, err := client.GetArticleDetail(articleID)
 	assert.Nil(t, err)
+	assert.Equal(t, "Draft", detail.Title)
 }
@@ -1,13 +1,17 @@
 package oschina
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/k8scat/articli/pkg/markdown"
 )
 
+var coverImagePattern = regexp.MustCompile(`^!\[cover_image\]\(([^)]+)\)\n\n`)
+
 type SaveType string
 
 const (
@@ -16,6 +20,10 @@ const (
 )
 
 func (c *Client) ParseMark(mark *markdown.Mark) (*ContentParams, error) {
+	return c.ParseMarkContext(context.Background(), mark)
+}
+
+func (c *Client) ParseMarkContext(ctx context.Context, mark *markdown.Mark) (*ContentParams, error) {
 	v := mark.Meta.Get("oschina")
 	if v == nil {
 		return nil, errors.New("oschina meta not found")
@@ -30,7 +38,7 @@ func (c *Client) ParseMark(mark *markdown.Mark) (*ContentParams, error) {
 	if metaCategory == "" {
 		return nil, errors.New("oschina category is required")
 	}
-	category, err := c.GetCategoryByName(metaCategory)
+	category, err := c.GetCategoryByNameContext(ctx, metaCategory)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -42,7 +50,7 @@ func (c *Client) ParseMark(mark *markdown.Mark) (*ContentParams, error) {
 	var technicalFieldID string
 	metaTechnicalField := meta.GetString("technical_field")
 	if metaTechnicalField != "" {
-		technicalField, err := c.GetTechnicalFieldByName(metaTechnicalField)
+		technicalField, err := c.GetTechnicalFieldByNameContext(ctx, metaTechnicalField)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -95,6 +103,12 @@ func (c *Client) ParseMark(mark *markdown.Mark) (*ContentParams, error) {
 		}
 	}
 	content := mark.Content
+	if prefixContent := meta.GetString("prefix_content"); prefixContent != "" {
+		content = fmt.Sprintf("%s\n\n%s", prefixContent, content)
+	}
+	if suffixContent := meta.GetString("suffix_content"); suffixContent != "" {
+		content = fmt.Sprintf("%s\n\n%s", content, suffixContent)
+	}
 	if coverImage != "" {
 		content = fmt.Sprintf("![cover_image](%s)\n\n%s", coverImage, content)
 	}
@@ -115,7 +129,17 @@ func (c *Client) ParseMark(mark *markdown.Mark) (*ContentParams, error) {
 	return params, nil
 }
 
+// WriteBack update the oschina meta of mark and write it back to the markdown file
 func WriteBack(saveType SaveType, mark *markdown.Mark, params *ContentParams, isCreate bool) error {
+	if err := UpdateMeta(saveType, mark, params, isCreate); err != nil {
+		return errors.Trace(err)
+	}
+	err := mark.WriteFile(mark.File)
+	return errors.Trace(err)
+}
+
+// UpdateMeta update the oschina meta of mark without writing the markdown file
+func UpdateMeta(saveType SaveType, mark *markdown.Mark, params *ContentParams, isCreate bool) error {
 	v := mark.Meta.Get("oschina")
 	if v == nil {
 		return errors.New("oschina meta not found")
@@ -140,6 +164,51 @@ func WriteBack(saveType SaveType, mark *markdown.Mark, params *ContentParams, is
 	}
 
 	mark.Meta = mark.Meta.Set("oschina", meta)
-	err := mark.WriteFile(mark.File)
-	return errors.Trace(err)
+	return nil
+}
+
+// NewMark converts the content params of an article into mark, the oschina meta is pre-filled with params
+func NewMark(params *ContentParams) *markdown.Mark {
+	var meta markdown.Meta
+	if params.CategoryName != "" {
+		meta = meta.Set("category", params.CategoryName)
+	}
+	if params.OriginalURL != "" {
+		meta = meta.Set("original_url", params.OriginalURL)
+	}
+	flags := []struct {
+		key   string
+		value int
+	}{
+		{"privacy", params.Privacy},
+		{"top", params.Top},
+		{"deny_comment", params.DenyComment},
+		{"download_image", params.DownloadImage},
+	}
+	for _, f := range flags {
+		if f.value == 1 {
+			meta = meta.Set(f.key, true)
+		}
+	}
+	if params.ID != "" {
+		meta = meta.Set("article_id", params.ID)
+	}
+	if params.DraftID != "" {
+		meta = meta.Set("draft_id", params.DraftID)
+	}
+
+	// The cover image is prepended to the content by ParseMark
+	content := params.Content
+	if m := coverImagePattern.FindStringSubmatch(content); m != nil {
+		meta = meta.Set("cover_image", m[1])
+		content = content[len(m[0]):]
+	}
+
+	var root markdown.Meta
+	root = root.Set("title", params.Title)
+	root = root.Set("oschina", meta)
+	return &markdown.Mark{
+		Meta:    root,
+		Content: markdown.EnsureNewline(content),
+	}
 }
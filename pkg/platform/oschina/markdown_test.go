@@ -0,0 +1,32 @@
+package oschina
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+func TestParseMark(t *testing.T) {
+	client, _ := newTestClient(t)
+	assert.Nil(t, client.AddCategory("Go"))
+
+	var meta markdown.Meta
+	meta = meta.Set("title", "Title")
+	meta = meta.Set("cover_images", []interface{}{"https://cdn.com/cover.png"})
+	meta = meta.Set("oschina", markdown.Meta{}.
+		Set("category", "Go").
+		Set("prefix_content", "prefix").
+		Set("suffix_content", "suffix"))
+	params, err := client.ParseMark(&markdown.Mark{Meta: meta, Content: "content"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "![cover_image](https://cdn.com/cover.png)\n\nprefix\n\ncontent\n\nsuffix", params.Content)
+
+	// The cover image is taken back from the content
+	mark := NewMark(params)
+	assert.Equal(t, "https://cdn.com/cover.png", mark.Meta.GetString("oschina.cover_image"))
+	assert.Equal(t, "prefix\n\ncontent\n\nsuffix\n", mark.Content)
+}
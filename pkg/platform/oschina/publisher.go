@@ -0,0 +1,87 @@
+package oschina
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+const PlatformName = "oschina"
+
+func init() {
+	platform.Register(PlatformName, NewPublisher)
+}
+
+// Publisher implements platform.Publisher for oschina.net
+type Publisher struct {
+	Client *Client
+}
+
+var _ platform.Publisher = (*Publisher)(nil)
+
+// NewPublisher creates a Publisher with the cookie and the settings in opts
+func NewPublisher(opts *platform.Options) (platform.Publisher, error) {
+	client, err := NewClient(opts.Cookie, WithOptions(opts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Publisher{Client: client}, nil
+}
+
+func (p *Publisher) Name() string {
+	return PlatformName
+}
+
+func (p *Publisher) Publish(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMarkContext(ctx, mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	isCreate := params.ID == ""
+	if err = p.Client.SaveArticleContext(ctx, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &platform.Result{
+		Platform:  PlatformName,
+		SaveType:  platform.SaveTypeArticle,
+		ArticleID: params.ID,
+		DraftID:   params.DraftID,
+		URL:       p.Client.BuildArticleURL(params.ID),
+		IsCreate:  isCreate,
+	}, nil
+}
+
+func (p *Publisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	params, err := p.Client.ParseMarkContext(ctx, mark)
+	if err != nil {
+		return nil, errors.Trace(platform.NotSent(err))
+	}
+	isCreate := params.DraftID == ""
+	if err = p.Client.SaveDraftContext(ctx, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &platform.Result{
+		Platform:  PlatformName,
+		SaveType:  platform.SaveTypeDraft,
+		ArticleID: params.ID,
+		DraftID:   params.DraftID,
+		URL:       p.Client.BuildDraftEditorURL(params.DraftID),
+		IsCreate:  isCreate,
+	}, nil
+}
+
+func (p *Publisher) DeleteArticle(ctx context.Context, id string) error {
+	return errors.Trace(p.Client.DeleteArticleContext(ctx, id))
+}
+
+func (p *Publisher) WriteBack(mark *markdown.Mark, result *platform.Result) error {
+	params := &ContentParams{
+		ID:      result.ArticleID,
+		DraftID: result.DraftID,
+	}
+	err := UpdateMeta(SaveType(result.SaveType), mark, params, result.IsCreate)
+	return errors.Trace(err)
+}
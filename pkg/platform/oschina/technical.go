@@ -1,6 +1,7 @@
 package oschina
 
 import (
+	"context"
 	"fmt"
 	"github.com/antchfx/htmlquery"
 	"github.com/juju/errors"
@@ -14,8 +15,12 @@ type TechnicalField struct {
 
 // ListTechnicalFields list all technical fields
 func (c *Client) ListTechnicalFields() ([]*TechnicalField, error) {
+	return c.ListTechnicalFieldsContext(context.Background())
+}
+
+func (c *Client) ListTechnicalFieldsContext(ctx context.Context) ([]*TechnicalField, error) {
 	path := fmt.Sprintf("%s%s", c.BaseURL, "/blog/write")
-	raw, err := c.Get(path, nil, nil)
+	raw, err := c.GetContext(ctx, path, nil, nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -44,7 +49,11 @@ func (c *Client) ListTechnicalFields() ([]*TechnicalField, error) {
 }
 
 func (c *Client) GetTechnicalFieldByName(name string) (*TechnicalField, error) {
-	fields, err := c.ListTechnicalFields()
+	return c.GetTechnicalFieldByNameContext(context.Background(), name)
+}
+
+func (c *Client) GetTechnicalFieldByNameContext(ctx context.Context, name string) (*TechnicalField, error) {
+	fields, err := c.ListTechnicalFieldsContext(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
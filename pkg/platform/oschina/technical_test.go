@@ -1,19 +1,19 @@
 package oschina
 
 import (
-	"github.com/stretchr/testify/assert"
-	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestListTechnicalFields(t *testing.T) {
-	client, err := NewClient(os.Getenv("ARTICLI_OSCHINA_COOKIE"))
-	if err != nil {
-		t.Fail()
-		return
-	}
+	client, _ := newTestClient(t)
 
 	fields, err := client.ListTechnicalFields()
 	assert.Nil(t, err)
-	assert.Greater(t, 0, len(fields))
+	assert.NotEqual(t, 0, len(fields))
+
+	field, err := client.GetTechnicalFieldByName("后端")
+	assert.Nil(t, err)
+	assert.Equal(t, "2", field.ID)
 }
@@ -0,0 +1,157 @@
+package platform
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+type SaveType string
+
+const (
+	SaveTypeArticle SaveType = "article"
+	SaveTypeDraft   SaveType = "draft"
+)
+
+// Result is the outcome of saving an article or a draft to a platform
+type Result struct {
+	Platform  string
+	SaveType  SaveType
+	ArticleID string
+	DraftID   string
+	URL       string
+	IsCreate  bool
+}
+
+// notSentError is an error which happened before the save request was sent
+type notSentError struct {
+	error
+}
+
+// NotSent marks err as happened before the save request was sent, e.g. an invalid platform meta,
+// so that the save is known to have changed nothing on the platform
+func NotSent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &notSentError{error: err}
+}
+
+// IsNotSent reports whether err is marked by NotSent
+func IsNotSent(err error) bool {
+	_, ok := errors.Cause(err).(*notSentError)
+	return ok
+}
+
+// Publisher is implemented by every platform which can publish articles from markdown files,
+// the requests are canceled when ctx is done
+type Publisher interface {
+	// Name returns the platform name, which is also the key of the platform meta in markdown files
+	Name() string
+	// Publish creates an article if the platform meta has no article id, otherwise updates it.
+	// The errors before the save request is sent are marked by NotSent, and so are they of SaveDraft.
+	Publish(ctx context.Context, mark *markdown.Mark) (*Result, error)
+	// SaveDraft creates a draft if the platform meta has no draft id, otherwise updates it
+	SaveDraft(ctx context.Context, mark *markdown.Mark) (*Result, error)
+	// DeleteArticle deletes the article with the given id
+	DeleteArticle(ctx context.Context, id string) error
+	// WriteBack updates the platform meta of mark with result, the markdown file is not written
+	WriteBack(mark *markdown.Mark, result *Result) error
+}
+
+// Factory creates a Publisher with the account and the http settings in opts
+type Factory func(opts *Options) (Publisher, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes a platform available by the provided name.
+// It panics if Register is called twice with the same name or if factory is nil.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if factory == nil {
+		panic("platform: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("platform: Register called twice for platform " + name)
+	}
+	factories[name] = factory
+}
+
+// New creates a Publisher of the named platform, the defaults of the platform are used if opts is nil
+func New(name string, opts *Options) (Publisher, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, errors.NotFoundf("platform %q", name)
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	p, err := factory(opts)
+	return p, errors.Trace(err)
+}
+
+// Profile returns the profile pinned in the platform meta of mark, or in the meta of mark for all the platforms
+func Profile(mark *markdown.Mark, name string) string {
+	if profile := mark.Meta.GetString(name + ".profile"); profile != "" {
+		return profile
+	}
+	return mark.Meta.GetString("profile")
+}
+
+// Names returns a sorted list of the names of the registered platforms
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasMeta reports whether mark has meta of the named platform
+func HasMeta(mark *markdown.Mark, name string) bool {
+	_, ok := mark.Meta.Get(name).(markdown.Meta)
+	return ok
+}
+
+// Targets returns the registered platforms which have meta in mark, none if mark is a draft.
+// If only is not empty, the platforms not in only are ignored.
+func Targets(mark *markdown.Mark, only []string) []string {
+	if mark.Draft {
+		return nil
+	}
+	filter := make(map[string]bool, len(only))
+	for _, name := range only {
+		filter[name] = true
+	}
+
+	names := Names()
+	targets := make([]string, 0, len(names))
+	for _, name := range names {
+		if len(filter) > 0 && !filter[name] {
+			continue
+		}
+		if HasMeta(mark, name) {
+			targets = append(targets, name)
+		}
+	}
+	return targets
+}
+
+// ImageUploader is implemented by the publishers of platforms which host images
+type ImageUploader interface {
+	// UploadImage uploads a local image and returns the url of it
+	UploadImage(ctx context.Context, path string) (string, error)
+}
@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+)
+
+type fakePublisher struct {
+	cookie string
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(ctx context.Context, mark *markdown.Mark) (*Result, error) {
+	return &Result{Platform: p.Name(), SaveType: SaveTypeArticle, ArticleID: "1", IsCreate: true}, nil
+}
+
+func (p *fakePublisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*Result, error) {
+	return &Result{Platform: p.Name(), SaveType: SaveTypeDraft, DraftID: "1", IsCreate: true}, nil
+}
+
+func (p *fakePublisher) DeleteArticle(ctx context.Context, id string) error { return nil }
+
+func (p *fakePublisher) WriteBack(mark *markdown.Mark, result *Result) error {
+	mark.Meta = mark.Meta.Set(p.Name(), markdown.Meta{}.Set("article_id", result.ArticleID))
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register("fake", func(opts *Options) (Publisher, error) {
+		if opts.Cookie == "" {
+			return nil, errors.New("empty cookie")
+		}
+		return &fakePublisher{cookie: opts.Cookie}, nil
+	})
+	assert.Panics(t, func() {
+		Register("fake", func(opts *Options) (Publisher, error) { return nil, nil })
+	})
+	assert.Contains(t, Names(), "fake")
+
+	_, err := New("fake", nil)
+	assert.NotNil(t, err)
+	opts := &Options{Cookie: "cookie"}
+	_, err = New("unknown", opts)
+	assert.True(t, errors.IsNotFound(err))
+
+	p, err := New("fake", opts)
+	assert.Nil(t, err)
+	if !assert.NotNil(t, p) {
+		return
+	}
+	assert.Equal(t, "cookie", p.(*fakePublisher).cookie)
+
+	mark := &markdown.Mark{}
+	assert.False(t, HasMeta(mark, "fake"))
+	result, err := p.Publish(context.Background(), mark)
+	assert.Nil(t, err)
+	assert.Nil(t, p.WriteBack(mark, result))
+	assert.True(t, HasMeta(mark, "fake"))
+	assert.Equal(t, "1", mark.Meta.GetString("fake.article_id"))
+	assert.Contains(t, Targets(mark, nil), "fake")
+	assert.Empty(t, Targets(mark, []string{"juejin"}))
+	mark.Draft = true
+	assert.Empty(t, Targets(mark, nil))
+	mark.Draft = false
+
+	assert.Equal(t, "", Profile(mark, "fake"))
+	pinned := &markdown.Mark{Meta: markdown.Meta{}.Set("fake", markdown.Meta{}.Set("profile", "company"))}
+	assert.Equal(t, "company", Profile(pinned, "fake"))
+	pinned = &markdown.Mark{Meta: markdown.Meta{}.Set("profile", "company")}
+	assert.Equal(t, "company", Profile(pinned, "fake"))
+}
+
+func TestNotSent(t *testing.T) {
+	assert.Nil(t, NotSent(nil))
+	assert.False(t, IsNotSent(errors.New("timeout")))
+
+	err := errors.Annotate(NotSent(errors.NotValidf("title")), "parse failed")
+	assert.True(t, IsNotSent(err))
+	assert.Contains(t, err.Error(), "title not valid")
+}
@@ -0,0 +1,243 @@
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/internal/config"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/utils"
+)
+
+const (
+	queueFileName = "schedule.json"
+
+	// PublishAtKey is the front matter key of the publish time,
+	// it can be set in the common meta or in the platform meta.
+	PublishAtKey = "publish_at"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // waiting for drafting or publishing
+	StatusDrafted   Status = "drafted"   // the draft is saved, waiting for publishing
+	StatusPublished Status = "published" // published
+	StatusFailed    Status = "failed"    // failed too many times
+	StatusCanceled  Status = "canceled"  // canceled by user
+)
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Job publishes a markdown file to a platform at PublishAt
+type Job struct {
+	ID         string    `json:"id"`
+	File       string    `json:"file"`
+	Platform   string    `json:"platform"`
+	PublishAt  time.Time `json:"publish_at"`
+	Status     Status    `json:"status"`
+	URL        string    `json:"url,omitempty"`
+	Attempts   int       `json:"attempts,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// Done reports whether the job will not be run any more
+func (j *Job) Done() bool {
+	return j.Status == StatusPublished || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// Queue is the list of scheduled jobs persisted in a json file,
+// the file is locked and reloaded before every change so that the queue can be shared by processes.
+type Queue struct {
+	file string
+	jobs []*Job
+}
+
+// DefaultQueueFile returns the path of the schedule queue in the config dir
+func DefaultQueueFile() string {
+	return filepath.Join(config.GetConfigDir(), queueFileName)
+}
+
+// LoadQueue loads the queue from file, an empty queue is returned if file does not exist
+func LoadQueue(file string) (*Queue, error) {
+	q := &Queue{file: file}
+	if err := q.load(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return q, nil
+}
+
+// Jobs returns the jobs sorted by publish time
+func (q *Queue) Jobs() []*Job {
+	jobs := make([]*Job, len(q.jobs))
+	copy(jobs, q.jobs)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].PublishAt.Before(jobs[j].PublishAt)
+	})
+	return jobs
+}
+
+// Add adds a pending job publishing file to the platform at publishAt,
+// the pending job of the same file and platform is replaced.
+func (q *Queue) Add(file, platform string, publishAt time.Time) (*Job, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := time.Now()
+	job := &Job{
+		ID:         newID(),
+		File:       abs,
+		Platform:   platform,
+		PublishAt:  publishAt,
+		Status:     StatusPending,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	err = q.modify(func() error {
+		jobs := make([]*Job, 0, len(q.jobs)+1)
+		for _, j := range q.jobs {
+			if j.File == abs && j.Platform == platform && !j.Done() {
+				job.Status = j.Status
+				continue
+			}
+			jobs = append(jobs, j)
+		}
+		q.jobs = append(jobs, job)
+		return nil
+	})
+	return job, errors.Trace(err)
+}
+
+// Cancel cancels the job which is not done yet
+func (q *Queue) Cancel(id string) error {
+	return q.Update(id, func(job *Job) error {
+		if job.Done() {
+			return errors.Errorf("job %s is %s already", id, job.Status)
+		}
+		job.Status = StatusCanceled
+		return nil
+	})
+}
+
+// Update applies f to the job and saves the queue
+func (q *Queue) Update(id string, f func(job *Job) error) error {
+	return q.modify(func() error {
+		for _, job := range q.jobs {
+			if job.ID == id {
+				if err := f(job); err != nil {
+					return errors.Trace(err)
+				}
+				job.UpdateTime = time.Now()
+				return nil
+			}
+		}
+		return errors.NotFoundf("job %s", id)
+	})
+}
+
+// Reload reads the jobs from the queue file again
+func (q *Queue) Reload() error {
+	return errors.Trace(q.load())
+}
+
+func (q *Queue) modify(f func() error) error {
+	unlock, err := utils.LockFile(q.file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer unlock()
+	if err := q.load(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := f(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(q.save())
+}
+
+func (q *Queue) load() error {
+	b, err := ioutil.ReadFile(q.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			q.jobs = nil
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	var jobs []*Job
+	if err = json.Unmarshal(b, &jobs); err != nil {
+		return errors.Errorf("invalid schedule data: %s", q.file)
+	}
+	q.jobs = jobs
+	return nil
+}
+
+// save writes the queue atomically, so that the queue is never half written
+func (q *Queue) save() error {
+	b, err := json.MarshalIndent(q.jobs, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.WriteFileAtomic(q.file, b, 0644))
+}
+
+func newID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ParseTime parses the publish time in local time zone
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.NotValidf("time %q, e.g. 2006-01-02 15:04", s)
+}
+
+// GetPublishAt returns the publish time of the platform in mark,
+// the platform meta takes precedence over the common meta.
+// A zero time is returned if it is not set.
+func GetPublishAt(mark *markdown.Mark, platform string) (time.Time, error) {
+	v := mark.Meta.Get(platform + "." + PublishAtKey)
+	if v == nil {
+		v = mark.Meta.Get(PublishAtKey)
+	}
+	switch t := v.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return t, nil
+	case string:
+		if t == "" {
+			return time.Time{}, nil
+		}
+		return ParseTime(t)
+	default:
+		return time.Time{}, errors.NotValidf("%s %v", PublishAtKey, v)
+	}
+}
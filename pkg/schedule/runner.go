@@ -0,0 +1,206 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/k8scat/articli/pkg/journal"
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+const (
+	DefaultDraftAhead  = time.Hour
+	DefaultMaxAttempts = 3
+)
+
+// Rewriter rewrites the markdown before it is sent to the platform, e.g. uploads the local images
+type Rewriter interface {
+	RewriteMark(ctx context.Context, p platform.Publisher, mark *markdown.Mark) (*markdown.Mark, error)
+}
+
+// Runner runs the due jobs in the queue
+type Runner struct {
+	Queue *Queue
+	// NewPublisher creates the publisher of the named platform with the account pinned in mark
+	NewPublisher func(name string, mark *markdown.Mark) (platform.Publisher, error)
+	// Rewriter is optional
+	Rewriter Rewriter
+	// Journal is optional, the saves are journaled and the ids left by the interrupted saves are recovered
+	Journal *journal.Journal
+	// AllowDuplicate creates the articles even if the articles with the same titles exist
+	AllowDuplicate bool
+	// DraftAhead is how long before the publish time the draft of a new article is saved
+	DraftAhead time.Duration
+	// MaxAttempts is how many times a job is tried before it is marked as failed
+	MaxAttempts int
+	// Now returns the current time, time.Now is used if it is nil
+	Now func() time.Time
+	// Logf logs the progress, it is optional
+	Logf func(format string, args ...interface{})
+}
+
+// RunOnce drafts and publishes the jobs which are due, every job is saved right after it is run,
+// so that a restarted runner continues from where it stopped.
+// It stops when ctx is done, and the interrupted job is not counted as a failed attempt.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	if err := r.Queue.Reload(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.Journal != nil {
+		recoveries, err := r.Journal.Recover(ctx, r.NewPublisher)
+		for _, rec := range recoveries {
+			r.logf("%s", rec)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+	for _, job := range r.Queue.Jobs() {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		if job.Done() {
+			continue
+		}
+		publish := !now.Before(job.PublishAt)
+		draft := job.Status == StatusPending && !now.Before(job.PublishAt.Add(-r.draftAhead()))
+		if !publish && !draft {
+			continue
+		}
+
+		url, runErr := r.run(ctx, job, publish)
+		if runErr != nil && ctx.Err() != nil {
+			r.logf("%s %s interrupted: %s", job.Platform, job.File, runErr)
+			return errors.Trace(ctx.Err())
+		}
+		err := r.Queue.Update(job.ID, func(j *Job) error {
+			if j.Done() {
+				// Canceled while running
+				return nil
+			}
+			if runErr != nil {
+				j.Attempts++
+				j.Error = runErr.Error()
+				if j.Attempts >= r.maxAttempts() {
+					j.Status = StatusFailed
+				}
+				return nil
+			}
+			j.Attempts = 0
+			j.Error = ""
+			j.URL = url
+			if publish {
+				j.Status = StatusPublished
+			} else {
+				j.Status = StatusDrafted
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if runErr != nil {
+			r.logf("%s %s failed: %s", job.Platform, job.File, runErr)
+		} else if publish {
+			r.logf("%s %s published: %s", job.Platform, job.File, url)
+		} else {
+			r.logf("%s %s drafted: %s", job.Platform, job.File, url)
+		}
+	}
+	return nil
+}
+
+// run publishes the job or saves the draft of it, the ids are written back to the markdown file
+func (r *Runner) run(ctx context.Context, job *Job, publish bool) (string, error) {
+	mark, err := markdown.Parse(job.File)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !platform.HasMeta(mark, job.Platform) {
+		return "", errors.Errorf("%s meta not found", job.Platform)
+	}
+	if mark.Draft {
+		return "", errors.New("the article is a draft")
+	}
+	if !publish && mark.Meta.GetString(job.Platform+".article_id") != "" {
+		// The article exists already, it is updated when due
+		return "", nil
+	}
+
+	p, err := r.NewPublisher(job.Platform, mark)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !r.AllowDuplicate {
+		if err = platform.CheckDuplicate(ctx, p, mark); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	m := mark
+	if r.Rewriter != nil {
+		if m, err = r.Rewriter.RewriteMark(ctx, p, mark); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+
+	saveType := platform.SaveTypeDraft
+	if publish {
+		saveType = platform.SaveTypeArticle
+	}
+	save := func() (*platform.Result, error) {
+		if publish {
+			return p.Publish(ctx, m)
+		}
+		return p.SaveDraft(ctx, m)
+	}
+	var result *platform.Result
+	var entry *journal.Entry
+	if r.Journal != nil {
+		result, entry, err = r.Journal.Save(mark.File, job.Platform, saveType, platform.Title(mark, job.Platform), save)
+	} else {
+		result, err = save()
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err = p.WriteBack(mark, result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err = mark.WriteFile(mark.File); err != nil {
+		return "", errors.Trace(err)
+	}
+	if entry != nil {
+		err = r.Journal.Finish(entry.ID)
+	}
+	return result.URL, errors.Trace(err)
+}
+
+func (r *Runner) draftAhead() time.Duration {
+	if r.DraftAhead > 0 {
+		return r.DraftAhead
+	}
+	return DefaultDraftAhead
+}
+
+func (r *Runner) maxAttempts() int {
+	if r.MaxAttempts > 0 {
+		return r.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+func (r *Runner) logf(format string, args ...interface{}) {
+	if r.Logf != nil {
+		r.Logf(format, args...)
+		return
+	}
+	fmt.Printf(format+"\n", args...)
+}
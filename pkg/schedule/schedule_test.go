@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k8scat/articli/pkg/markdown"
+	"github.com/k8scat/articli/pkg/platform"
+)
+
+type fakePublisher struct {
+	drafts    int
+	published int
+	fail      bool
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	if p.fail {
+		return nil, errors.New("publish failed")
+	}
+	p.published++
+	return &platform.Result{Platform: "fake", SaveType: platform.SaveTypeArticle, ArticleID: "1", URL: "https://fake.com/1"}, nil
+}
+
+func (p *fakePublisher) SaveDraft(ctx context.Context, mark *markdown.Mark) (*platform.Result, error) {
+	p.drafts++
+	return &platform.Result{Platform: "fake", SaveType: platform.SaveTypeDraft, DraftID: "2", URL: "https://fake.com/draft/2"}, nil
+}
+
+func (p *fakePublisher) DeleteArticle(ctx context.Context, id string) error { return nil }
+
+func (p *fakePublisher) WriteBack(mark *markdown.Mark, result *platform.Result) error {
+	meta, _ := mark.Meta.Get("fake").(markdown.Meta)
+	if result.ArticleID != "" {
+		meta = meta.Set("article_id", result.ArticleID)
+	}
+	if result.DraftID != "" {
+		meta = meta.Set("draft_id", result.DraftID)
+	}
+	mark.Meta = mark.Meta.Set("fake", meta)
+	return nil
+}
+
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2022, 3, 1, 9, 30, 0, 0, time.Local)
+	for _, s := range []string{"2022-03-01 09:30", "2022-03-01 09:30:00", "2022-03-01T09:30"} {
+		v, err := ParseTime(s)
+		assert.Nil(t, err)
+		assert.True(t, expected.Equal(v), s)
+	}
+	_, err := ParseTime("tomorrow")
+	assert.NotNil(t, err)
+}
+
+func TestGetPublishAt(t *testing.T) {
+	mark := &markdown.Mark{}
+	v, err := GetPublishAt(mark, "fake")
+	assert.Nil(t, err)
+	assert.True(t, v.IsZero())
+
+	mark.Meta = mark.Meta.Set(PublishAtKey, "2022-03-01 09:30")
+	mark.Meta = mark.Meta.Set("fake", markdown.Meta{}.Set(PublishAtKey, "2022-03-02 09:30"))
+	v, err = GetPublishAt(mark, "fake")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v.Day())
+	v, err = GetPublishAt(mark, "other")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v.Day())
+}
+
+func TestRunner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "articli-schedule")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "article.md")
+	err = ioutil.WriteFile(file, []byte("---\ntitle: Title\nfake:\n  tags: [Go]\n---\nhello\n"), 0644)
+	assert.Nil(t, err)
+
+	queueFile := filepath.Join(dir, queueFileName)
+	queue, err := LoadQueue(queueFile)
+	assert.Nil(t, err)
+	publishAt := time.Date(2022, 3, 1, 9, 30, 0, 0, time.Local)
+	job, err := queue.Add(file, "fake", publishAt)
+	assert.Nil(t, err)
+
+	p := new(fakePublisher)
+	now := publishAt.Add(-2 * time.Hour)
+	r := &Runner{
+		Queue:        queue,
+		NewPublisher: func(name string, mark *markdown.Mark) (platform.Publisher, error) { return p, nil },
+		MaxAttempts:  2,
+		Now:          func() time.Time { return now },
+		Logf:         func(format string, args ...interface{}) {},
+	}
+
+	// Not due yet
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, 0, p.drafts)
+
+	// The draft is saved one hour ahead
+	now = publishAt.Add(-30 * time.Minute)
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, 1, p.drafts)
+	mark, err := markdown.Parse(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", mark.Meta.GetString("fake.draft_id"))
+
+	// A restarted runner loads the state from the queue file
+	queue, err = LoadQueue(queueFile)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusDrafted, queue.Jobs()[0].Status)
+	r.Queue = queue
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, 1, p.drafts)
+
+	// Nothing is run after the runner is interrupted
+	now = publishAt
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, r.RunOnce(ctx))
+	assert.Equal(t, 0, p.published)
+	assert.Equal(t, 0, queue.Jobs()[0].Attempts)
+
+	// Failed jobs are retried until MaxAttempts
+	p.fail = true
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, StatusDrafted, queue.Jobs()[0].Status)
+	assert.Equal(t, 1, queue.Jobs()[0].Attempts)
+	p.fail = false
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, 1, p.published)
+	published := queue.Jobs()[0]
+	assert.Equal(t, StatusPublished, published.Status)
+	assert.Equal(t, "https://fake.com/1", published.URL)
+	assert.Equal(t, "", published.Error)
+
+	// Done jobs are not run again, and cannot be canceled
+	assert.Nil(t, r.RunOnce(context.Background()))
+	assert.Equal(t, 1, p.published)
+	assert.NotNil(t, queue.Cancel(job.ID))
+}
+
+func TestQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "articli-schedule")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	queue, err := LoadQueue(filepath.Join(dir, queueFileName))
+	assert.Nil(t, err)
+	publishAt := time.Now().Add(time.Hour)
+	first, err := queue.Add("a.md", "fake", publishAt)
+	assert.Nil(t, err)
+	// The pending job of the same file and platform is replaced
+	second, err := queue.Add("a.md", "fake", publishAt.Add(time.Hour))
+	assert.Nil(t, err)
+	_, err = queue.Add("b.md", "fake", publishAt)
+	assert.Nil(t, err)
+
+	jobs := queue.Jobs()
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, second.ID, jobs[1].ID)
+
+	assert.True(t, errors.IsNotFound(queue.Cancel(first.ID)))
+	assert.Nil(t, queue.Cancel(second.ID))
+	assert.Equal(t, StatusCanceled, queue.Jobs()[1].Status)
+}
+
+func TestConcurrentQueues(t *testing.T) {
+	file := filepath.Join(t.TempDir(), queueFileName)
+	// The queues of two processes add the jobs at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		queue, err := LoadQueue(file)
+		if !assert.Nil(t, err) {
+			return
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for k := 0; k < 10; k++ {
+				_, err := queue.Add(fmt.Sprintf("%d-%d.md", i, k), "fake", time.Now())
+				assert.Nil(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	queue, err := LoadQueue(file)
+	assert.Nil(t, err)
+	assert.Len(t, queue.Jobs(), 20)
+}
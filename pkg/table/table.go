@@ -1,13 +1,99 @@
 package table
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
-	"github.com/olekukonko/tablewriter"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/juju/errors"
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v2"
+)
+
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTemplate Format = "template"
+)
+
+var (
+	output Format = FormatTable
+	tmpl   *template.Template
+
+	// Output is where the tables are written to
+	Output io.Writer = os.Stdout
 )
 
-func Print(header []string, data [][]string) {
-	table := tablewriter.NewWriter(os.Stdout)
+// SetOutput sets the format of Print, text is the go template executed for each row,
+// whose fields are the keys of the columns, e.g. {{.publish_at}}.
+// The template format is used if text is not empty.
+func SetOutput(format, text string) error {
+	if text != "" {
+		t, err := template.New("output").Parse(text)
+		if err != nil {
+			return errors.Annotate(err, "invalid template")
+		}
+		output, tmpl = FormatTemplate, t
+		return nil
+	}
+
+	switch f := Format(strings.ToLower(format)); f {
+	case "", FormatTable:
+		output = FormatTable
+	case FormatJSON, FormatYAML, FormatCSV:
+		output = f
+	default:
+		return errors.NotSupportedf("output format %q", format)
+	}
+	return nil
+}
+
+// IsStructured reports whether the output is meant to be read by programs,
+// in which case messages other than the tables should not be printed to stdout.
+func IsStructured() bool {
+	return output != FormatTable
+}
+
+// Print prints data in the output format, the header is only shown in the table format,
+// and keys are the stable snake_case field names of the columns in the structured formats, e.g. publish_at.
+// The values are strings in the json, yaml and template formats unless the key ends with ",number",
+// e.g. view_count,number, whose values are numbers if they are valid.
+func Print(keys, header []string, data [][]string) {
+	if err := Fprint(Output, keys, header, data); err != nil {
+		fmt.Fprintf(os.Stderr, "print failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// Fprint writes data to w in the output format
+func Fprint(w io.Writer, keys, header []string, data [][]string) error {
+	switch output {
+	case FormatJSON:
+		return errors.Trace(printJSON(w, keys, data))
+	case FormatYAML:
+		return errors.Trace(printYAML(w, keys, data))
+	case FormatCSV:
+		return errors.Trace(printCSV(w, keys, data))
+	case FormatTemplate:
+		return errors.Trace(printTemplate(w, keys, data))
+	default:
+		printTable(w, header, data)
+		return nil
+	}
+}
+
+func printTable(w io.Writer, header []string, data [][]string) {
+	table := tablewriter.NewWriter(w)
 	table.SetHeader(header)
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
@@ -22,5 +108,113 @@ func Print(header []string, data [][]string) {
 	table.SetNoWhiteSpace(true)
 	table.AppendBulk(data) // Add Bulk Data
 	table.Render()
-	fmt.Printf("Count: %d\n", len(data))
+	fmt.Fprintf(w, "Count: %d\n", len(data))
+}
+
+// printJSON keeps the order of the keys in objects, which is lost with maps
+func printJSON(w io.Writer, keys []string, data [][]string) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("[")
+	for i, row := range data {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, key := range keys {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			name, numeric := parseKey(key)
+			k, _ := json.Marshal(name)
+			v, _ := json.Marshal(value(row, j, numeric))
+			fmt.Fprintf(buf, "\n    %s: %s", k, v)
+		}
+		buf.WriteString("\n  }")
+	}
+	if len(data) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	_, err := w.Write(buf.Bytes())
+	return errors.Trace(err)
+}
+
+func printYAML(w io.Writer, keys []string, data [][]string) error {
+	records := make([]yaml.MapSlice, 0, len(data))
+	for _, row := range data {
+		record := make(yaml.MapSlice, 0, len(keys))
+		for j, key := range keys {
+			name, numeric := parseKey(key)
+			record = append(record, yaml.MapItem{Key: name, Value: value(row, j, numeric)})
+		}
+		records = append(records, record)
+	}
+	b, err := yaml.Marshal(records)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = w.Write(b)
+	return errors.Trace(err)
+}
+
+func printCSV(w io.Writer, keys []string, data [][]string) error {
+	cw := csv.NewWriter(w)
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i], _ = parseKey(key)
+	}
+	if err := cw.Write(names); err != nil {
+		return errors.Trace(err)
+	}
+	if err := cw.WriteAll(data); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(cw.Error())
+}
+
+func printTemplate(w io.Writer, keys []string, data [][]string) error {
+	for _, row := range data {
+		record := make(map[string]interface{}, len(keys))
+		for j, key := range keys {
+			name, numeric := parseKey(key)
+			record[name] = value(row, j, numeric)
+		}
+		if err := tmpl.Execute(w, record); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// parseKey returns the name of key and whether its values are numbers, e.g. view_count,number
+func parseKey(key string) (name string, numeric bool) {
+	if i := strings.Index(key, ","); i != -1 {
+		return key[:i], key[i+1:] == "number"
+	}
+	return key, false
+}
+
+// value returns the cell as a number if the column is numeric and the cell is a valid number, otherwise a string
+func value(row []string, i int, numeric bool) interface{} {
+	s := cell(row, i)
+	if !numeric {
+		return s
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
 }
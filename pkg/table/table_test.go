@@ -0,0 +1,98 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	keys   = []string{"id", "publish_at"}
+	header = []string{"ID", "发布时间"}
+	data   = [][]string{{"1", "2022-03-01"}, {"2", "a,\"b\""}}
+)
+
+func render(t *testing.T, format, text string) string {
+	assert.Nil(t, SetOutput(format, text))
+	defer SetOutput("", "")
+	buf := new(bytes.Buffer)
+	assert.Nil(t, Fprint(buf, keys, header, data))
+	return buf.String()
+}
+
+func TestFprint(t *testing.T) {
+	out := render(t, "table", "")
+	assert.Contains(t, out, "发布时间")
+	assert.NotContains(t, out, "publish_at")
+	assert.Contains(t, out, "Count: 2\n")
+
+	assert.Equal(t, `[
+  {
+    "id": "1",
+    "publish_at": "2022-03-01"
+  },
+  {
+    "id": "2",
+    "publish_at": "a,\"b\""
+  }
+]
+`, render(t, "json", ""))
+
+	assert.Equal(t, `- id: "1"
+  publish_at: "2022-03-01"
+- id: "2"
+  publish_at: a,"b"
+`, render(t, "YAML", ""))
+
+	assert.Equal(t, "id,publish_at\n1,2022-03-01\n2,\"a,\"\"b\"\"\"\n", render(t, "csv", ""))
+
+	assert.Equal(t, "1 2022-03-01\n2 a,\"b\"\n", render(t, "json", "{{.id}} {{.publish_at}}"))
+}
+
+func TestFprintNumber(t *testing.T) {
+	assert.Nil(t, SetOutput("json", ""))
+	defer SetOutput("", "")
+	keys := []string{"id", "view_count,number", "avg_latency,number"}
+	data := [][]string{{"1", "10", "1.5"}, {"2", "", "-"}}
+	buf := new(bytes.Buffer)
+	assert.Nil(t, Fprint(buf, keys, nil, data))
+	assert.Equal(t, `[
+  {
+    "id": "1",
+    "view_count": 10,
+    "avg_latency": 1.5
+  },
+  {
+    "id": "2",
+    "view_count": "",
+    "avg_latency": "-"
+  }
+]
+`, buf.String())
+
+	assert.Nil(t, SetOutput("yaml", ""))
+	buf.Reset()
+	assert.Nil(t, Fprint(buf, keys, nil, data[:1]))
+	assert.Equal(t, "- id: \"1\"\n  view_count: 10\n  avg_latency: 1.5\n", buf.String())
+
+	assert.Nil(t, SetOutput("csv", ""))
+	buf.Reset()
+	assert.Nil(t, Fprint(buf, keys, nil, data[:1]))
+	assert.Equal(t, "id,view_count,avg_latency\n1,10,1.5\n", buf.String())
+}
+
+func TestFprintEmpty(t *testing.T) {
+	assert.Nil(t, SetOutput("json", ""))
+	defer SetOutput("", "")
+	buf := new(bytes.Buffer)
+	assert.Nil(t, Fprint(buf, keys, header, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestSetOutput(t *testing.T) {
+	assert.NotNil(t, SetOutput("xml", ""))
+	assert.NotNil(t, SetOutput("", "{{.id"))
+	assert.Nil(t, SetOutput("", ""))
+	assert.False(t, IsStructured())
+}
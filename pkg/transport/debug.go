@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxDebugBody is the maximum length of the bodies printed in the debug logs
+const MaxDebugBody = 2048
+
+const redacted = "REDACTED"
+
+// DebugOutput is where the requests and the responses are logged to if not nil, e.g. os.Stderr.
+// It must be set before the clients are created.
+var DebugOutput io.Writer
+
+// sensitiveHeaders are redacted in the debug logs and the recorded fixtures
+var sensitiveHeaders = []string{"Cookie", "Set-Cookie", "Authorization", "Private-Token", "X-Ca-Signature"}
+
+// sensitiveParams are redacted in the urls
+var sensitiveParams = []string{"private_token", "access_token", "token"}
+
+// debugTransport logs every request and response
+type debugTransport struct {
+	name string
+	base http.RoundTripper
+	w    io.Writer
+}
+
+// debugMu keeps the logs of the concurrent requests from interleaving
+var debugMu sync.Mutex
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	var buf bytes.Buffer
+	prefix := fmt.Sprintf("[%s] >", t.name)
+	fmt.Fprintf(&buf, "%s %s %s\n", prefix, req.Method, redactURL(req.URL))
+	writeHeader(&buf, prefix, req.Header)
+	writeBody(&buf, prefix, reqBody)
+
+	prefix = fmt.Sprintf("[%s] <", t.name)
+	if err == nil {
+		var respBody []byte
+		if respBody, err = readResponseBody(resp); err == nil {
+			fmt.Fprintf(&buf, "%s %s in %s\n", prefix, resp.Status, elapsed)
+			writeHeader(&buf, prefix, resp.Header)
+			writeBody(&buf, prefix, respBody)
+		}
+	}
+	if err != nil {
+		resp = nil
+		fmt.Fprintf(&buf, "%s error in %s: %s\n", prefix, elapsed, err)
+	}
+
+	debugMu.Lock()
+	_, _ = t.w.Write(buf.Bytes())
+	debugMu.Unlock()
+	return resp, err
+}
+
+func writeHeader(w io.Writer, prefix string, header http.Header) {
+	header = redactHeader(header)
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s %s: %s\n", prefix, k, strings.Join(header[k], ", "))
+	}
+}
+
+func writeBody(w io.Writer, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if !utf8.Valid(body) {
+		fmt.Fprintf(w, "%s <binary body of %d bytes>\n", prefix, len(body))
+		return
+	}
+	s := string(body)
+	if len(s) > MaxDebugBody {
+		s = fmt.Sprintf("%s... (%d bytes truncated)", s[:MaxDebugBody], len(s)-MaxDebugBody)
+	}
+	fmt.Fprintf(w, "%s %s\n", prefix, s)
+}
+
+// redactHeader returns a copy of header with the values of the sensitive headers replaced
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, k := range sensitiveHeaders {
+		if _, ok := h[http.CanonicalHeaderKey(k)]; ok {
+			h.Set(k, redacted)
+		}
+	}
+	return h
+}
+
+// redactURL returns the url with the values of the sensitive query params replaced
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, k := range sensitiveParams {
+		if _, ok := query[k]; ok {
+			query.Set(k, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = query.Encode()
+	return c.String()
+}
+
+// readRequestBody reads the body of req, and returns a copy of req whose body can be read again
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return r, b, nil
+}
+
+// readResponseBody reads the body of resp and replaces it with the bytes read
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, err
+}
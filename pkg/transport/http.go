@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	browser "github.com/EDDYCJY/fake-useragent"
+)
+
+// HTTP is the http settings of a platform client, which is embedded in the clients of the platforms
+type HTTP struct {
+	// HTTPClient sends all the requests of the client
+	HTTPClient *http.Client
+	// UserAgent is sent in the requests if not empty
+	UserAgent string
+	// Limiter limits the request rate of the client, the limiter shared by the clients of the platform is used if nil
+	Limiter *Limiter
+}
+
+// HTTPOption configures the http settings of a client
+type HTTPOption func(h *HTTP)
+
+// WithHTTPClient sets the http client which sends the requests
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(h *HTTP) {
+		h.HTTPClient = client
+	}
+}
+
+// WithTransport sets the transport of the http client, e.g. a transport with a proxy
+func WithTransport(rt http.RoundTripper) HTTPOption {
+	return func(h *HTTP) {
+		h.HTTPClient = cloneHTTPClient(h.HTTPClient)
+		h.HTTPClient.Transport = rt
+	}
+}
+
+// WithProxy sends the requests through the http proxy instead of the proxy in the environment variables
+func WithProxy(proxy *url.URL) HTTPOption {
+	return func(h *HTTP) {
+		h.HTTPClient = cloneHTTPClient(h.HTTPClient)
+		base, ok := h.HTTPClient.Transport.(*http.Transport)
+		if !ok || base == nil {
+			base = http.DefaultTransport.(*http.Transport)
+		}
+		base = base.Clone()
+		base.Proxy = http.ProxyURL(proxy)
+		h.HTTPClient.Transport = base
+	}
+}
+
+// WithTimeout sets the timeout of the requests
+func WithTimeout(timeout time.Duration) HTTPOption {
+	return func(h *HTTP) {
+		h.HTTPClient = cloneHTTPClient(h.HTTPClient)
+		h.HTTPClient.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the user agent of the requests
+func WithUserAgent(ua string) HTTPOption {
+	return func(h *HTTP) {
+		h.UserAgent = ua
+	}
+}
+
+// WithRateLimit limits the requests per second of the client, the requests are not limited if rate < 0,
+// and the limit shared by the clients of the platform is used if rate is 0
+func WithRateLimit(rate float64) HTTPOption {
+	return func(h *HTTP) {
+		h.Limiter = nil
+		if rate != 0 {
+			h.Limiter = NewRateLimiter(rate)
+		}
+	}
+}
+
+// Apply applies opts to h in order
+func (h *HTTP) Apply(opts ...HTTPOption) {
+	for _, opt := range opts {
+		opt(h)
+	}
+}
+
+// Wrap wraps the http client by a Transport of the named platform, which is called after the options are applied
+func (h *HTTP) Wrap(name string) {
+	if h.Limiter != nil {
+		h.HTTPClient = Wrap(name, h.HTTPClient, WithLimiter(h.Limiter))
+		return
+	}
+	h.HTTPClient = Wrap(name, h.HTTPClient)
+}
+
+// BrowserUserAgent returns the user agent, or a random user agent of browsers if it is empty
+func (h *HTTP) BrowserUserAgent() string {
+	if h.UserAgent != "" {
+		return h.UserAgent
+	}
+	return browser.Computer()
+}
+
+// cloneHTTPClient returns a copy of client, so that the client passed by WithHTTPClient is not modified
+func cloneHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		return &http.Client{}
+	}
+	c := *client
+	return &c
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DefaultLimits is the default requests per second of the platforms
+var DefaultLimits = map[string]float64{
+	"juejin":  5,
+	"oschina": 2,
+	"csdn":    5,
+	"github":  10,
+	"gitlab":  10,
+}
+
+// DefaultLimit is the requests per second of the platforms not in DefaultLimits
+const DefaultLimit = 10
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*Limiter)
+)
+
+// LimiterFor returns the limiter shared by all the clients of the named platform
+func LimiterFor(name string) *Limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[name]
+	if !ok {
+		rate, ok := DefaultLimits[name]
+		if !ok {
+			rate = DefaultLimit
+		}
+		l = NewRateLimiter(rate)
+		limiters[name] = l
+	}
+	return l
+}
+
+// NewRateLimiter returns a limiter which allows rate requests per second, it allows all the requests if rate <= 0
+func NewRateLimiter(rate float64) *Limiter {
+	return NewLimiter(rate, burstOf(rate))
+}
+
+func burstOf(rate float64) int {
+	if rate < 1 {
+		return 1
+	}
+	return int(math.Ceil(rate))
+}
+
+// Limiter is a token bucket which allows rate requests per second with bursts of at most burst requests
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a full token bucket, it allows all the requests if rate <= 0
+func NewLimiter(rate float64, burst int) *Limiter {
+	l := &Limiter{}
+	l.SetRate(rate, burst)
+	return l
+}
+
+// SetRate changes the rate and the burst and fills the bucket
+func (l *Limiter) SetRate(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if burst < 1 {
+		burst = 1
+	}
+	l.rate = rate
+	l.burst = float64(burst)
+	l.tokens = l.burst
+	l.last = time.Now()
+}
+
+// reserve takes a token and returns how long to wait until the token is available
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return 0
+	}
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token which is not used
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+1)
+	}
+}
+
+// Wait blocks until a request is allowed or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	wait := l.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return errors.Trace(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stat is the metrics of the requests of a platform
+type Stat struct {
+	Name string
+	// Requests is the number of the requests sent by the clients
+	Requests int
+	// Attempts is the number of the requests sent to the server, including the retries
+	Attempts int
+	Retries  int
+	// Failures is the number of the requests which failed after all the attempts
+	Failures int
+	// Latency is the total latency of the requests including the retries
+	Latency    time.Duration
+	MaxLatency time.Duration
+}
+
+// AvgLatency returns the average latency of the requests
+func (s Stat) AvgLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Requests)
+}
+
+// Metrics collects the stats of the requests by platform
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[string]*Stat
+}
+
+// DefaultMetrics is used by the transports created by New
+var DefaultMetrics = NewMetrics()
+
+func NewMetrics() *Metrics {
+	return &Metrics{stats: make(map[string]*Stat)}
+}
+
+func (m *Metrics) add(s Stat) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stat, ok := m.stats[s.Name]
+	if !ok {
+		stat = &Stat{Name: s.Name}
+		m.stats[s.Name] = stat
+	}
+	stat.Requests += s.Requests
+	stat.Attempts += s.Attempts
+	stat.Retries += s.Retries
+	stat.Failures += s.Failures
+	stat.Latency += s.Latency
+	if s.MaxLatency > stat.MaxLatency {
+		stat.MaxLatency = s.MaxLatency
+	}
+}
+
+// Snapshot returns the stats sorted by the platform name
+func (m *Metrics) Snapshot() []Stat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make([]Stat, 0, len(m.stats))
+	for _, s := range m.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// Reset clears all the stats
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = make(map[string]*Stat)
+}
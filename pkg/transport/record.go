@@ -0,0 +1,231 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// RecordDir is where the requests and the responses are saved to as fixtures if not empty.
+// It must be set before the clients are created.
+var RecordDir string
+
+// Exchange is a recorded request and its response, which is saved as a json file
+type Exchange struct {
+	Platform string           `json:"platform"`
+	Request  ExchangeRequest  `json:"request"`
+	Response ExchangeResponse `json:"response"`
+}
+
+type ExchangeRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+type ExchangeResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is saved as a string if it is valid utf-8, otherwise as a base64 encoded string with a "base64:" prefix
+type Body []byte
+
+const base64Prefix = "base64:"
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) && !strings.HasPrefix(string(b), base64Prefix) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(base64Prefix + base64.StdEncoding.EncodeToString(b))
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Trace(err)
+	}
+	if strings.HasPrefix(s, base64Prefix) {
+		v, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, base64Prefix))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		*b = v
+		return nil
+	}
+	*b = Body(s)
+	return nil
+}
+
+var (
+	recordSeq    int64
+	recordPrefix = time.Now().Format("20060102150405")
+)
+
+// recordTransport saves every request and response in dir
+type recordTransport struct {
+	name string
+	base http.RoundTripper
+	dir  string
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := &Exchange{
+		Platform: t.name,
+		Request: ExchangeRequest{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   reqBody,
+		},
+		Response: ExchangeResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       respBody,
+		},
+	}
+	seq := atomic.AddInt64(&recordSeq, 1)
+	file := filepath.Join(t.dir, fmt.Sprintf("%s-%04d-%s.json", recordPrefix, seq, t.name))
+	if err := SaveExchange(file, ex); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SaveExchange writes ex to file as indented json
+func SaveExchange(file string, ex *Exchange) error {
+	b, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = ioutil.WriteFile(file, b, 0644)
+	return errors.Trace(err)
+}
+
+// LoadFixtures reads all the exchanges in dir in the order of the file names
+func LoadFixtures(dir string) ([]*Exchange, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(files)
+	exchanges := make([]*Exchange, 0, len(files))
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ex := new(Exchange)
+		if err := json.Unmarshal(b, ex); err != nil {
+			return nil, errors.Annotate(err, file)
+		}
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, nil
+}
+
+// Replayer is a http.RoundTripper which responds with the recorded exchanges instead of sending the requests.
+// A request matches an exchange with the same method, path and query, the host is ignored, so that
+// the clients can replay the exchanges recorded from any server.
+// The matched exchanges are replayed in order, and the last one is repeated.
+type Replayer struct {
+	mu        sync.Mutex
+	exchanges map[string][]*Exchange
+	replayed  map[string]int
+}
+
+var _ http.RoundTripper = (*Replayer)(nil)
+
+// NewReplayer returns a Replayer of the exchanges loaded from the fixtures in dir
+func NewReplayer(dir string) (*Replayer, error) {
+	exchanges, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(exchanges) == 0 {
+		return nil, errors.NotFoundf("fixtures in %s", dir)
+	}
+	r := &Replayer{
+		exchanges: make(map[string][]*Exchange),
+		replayed:  make(map[string]int),
+	}
+	for _, ex := range exchanges {
+		key, err := exchangeKey(ex.Request.Method, ex.Request.URL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r.exchanges[key] = append(r.exchanges[key], ex)
+	}
+	return r, nil
+}
+
+func exchangeKey(method, rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return method + " " + u.RequestURI(), nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key, err := exchangeKey(req.Method, redactURL(req.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	exchanges := r.exchanges[key]
+	i := r.replayed[key]
+	if i < len(exchanges)-1 {
+		r.replayed[key]++
+	}
+	r.mu.Unlock()
+	if len(exchanges) == 0 {
+		return nil, errors.NotFoundf("recorded exchange of %s", key)
+	}
+
+	ex := exchanges[i]
+	return &http.Response{
+		Status:        strconv.Itoa(ex.Response.StatusCode) + " " + http.StatusText(ex.Response.StatusCode),
+		StatusCode:    ex.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        ex.Response.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(ex.Response.Body)),
+		ContentLength: int64(len(ex.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "sessionid=secret")
+		w.Header().Set("X-Path", r.URL.Path)
+		if r.URL.Path == "/binary" {
+			_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+			return
+		}
+		_, _ = w.Write(append([]byte(r.Method+" "), body...))
+	}))
+}
+
+func TestDebug(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	DebugOutput = &buf
+	defer func() { DebugOutput = nil }()
+
+	client := &http.Client{Transport: New("test", nil)}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/echo?private_token=secret&page=1", strings.NewReader("hello"))
+	req.Header.Set("Cookie", "sessionid=secret")
+	req.Header.Set("Authorization", "token secret")
+	resp, err := client.Do(req)
+	if !assert.Nil(t, err) {
+		return
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "POST hello", string(b))
+
+	log := buf.String()
+	assert.NotContains(t, log, "secret")
+	assert.Contains(t, log, "[test] > POST "+server.URL+"/echo?page=1&private_token=REDACTED")
+	assert.Contains(t, log, "[test] > Cookie: REDACTED")
+	assert.Contains(t, log, "[test] > hello")
+	assert.Contains(t, log, "[test] < 200 OK in ")
+	assert.Contains(t, log, "[test] < Set-Cookie: REDACTED")
+	assert.Contains(t, log, "[test] < POST hello")
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	RecordDir = dir
+	defer func() { RecordDir = "" }()
+
+	client := &http.Client{Transport: New("test", nil)}
+	for _, body := range []string{"first", "second"} {
+		resp, err := client.Post(server.URL+"/echo", "text/plain", strings.NewReader(body))
+		if !assert.Nil(t, err) {
+			return
+		}
+		resp.Body.Close()
+	}
+	resp, err := client.Get(server.URL + "/binary")
+	if !assert.Nil(t, err) {
+		return
+	}
+	resp.Body.Close()
+
+	exchanges, err := LoadFixtures(dir)
+	assert.Nil(t, err)
+	if assert.Len(t, exchanges, 3) {
+		assert.Equal(t, "test", exchanges[0].Platform)
+		assert.Equal(t, "first", string(exchanges[0].Request.Body))
+		assert.Equal(t, "POST second", string(exchanges[1].Response.Body))
+		assert.Equal(t, []byte{0xff, 0xfe, 0x00}, []byte(exchanges[2].Response.Body))
+		assert.Equal(t, "REDACTED", exchanges[0].Response.Header.Get("Set-Cookie"))
+	}
+
+	// Replay without the server
+	replayer, err := NewReplayer(dir)
+	if !assert.Nil(t, err) {
+		return
+	}
+	client = &http.Client{Transport: replayer}
+	for _, want := range []string{"POST first", "POST second", "POST second"} {
+		resp, err := client.Post("https://example.com/echo", "text/plain", nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, want, string(b))
+		assert.Equal(t, "/echo", resp.Header.Get("X-Path"))
+	}
+	_, err = client.Get("https://example.com/unknown")
+	assert.NotNil(t, err)
+
+	_, err = NewReplayer(t.TempDir())
+	assert.NotNil(t, err)
+}
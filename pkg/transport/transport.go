@@ -0,0 +1,296 @@
+// Package transport provides the http transport shared by the platform clients,
+// which retries the transient failures, limits the request rate of each platform
+// and records the metrics of the requests.
+package transport
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultMaxWait    = time.Minute
+)
+
+// Transport is a http.RoundTripper which retries the idempotent requests with exponential backoff and jitter,
+// waits for the rate limit of the platform and records the metrics of the requests
+type Transport struct {
+	// Name is the platform name, which is the key of the limiter and the metrics
+	Name string
+	// Base sends the requests, http.DefaultTransport is used if nil
+	Base http.RoundTripper
+
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxWait is the longest wait asked by Retry-After or the rate limit reset,
+	// the response is returned without retrying if the server asks for a longer wait
+	MaxWait time.Duration
+
+	Limiter *Limiter
+	Metrics *Metrics
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// Option configures a Transport
+type Option func(t *Transport)
+
+// WithLimiter sets the limiter of the transport instead of the limiter shared by the clients of the platform
+func WithLimiter(l *Limiter) Option {
+	return func(t *Transport) {
+		t.Limiter = l
+	}
+}
+
+// New returns a Transport of the named platform with the default retry policy,
+// the shared limiter of the platform and DefaultMetrics, which are overridden by opts.
+// Every attempt is logged to DebugOutput and recorded in RecordDir if they are set.
+func New(name string, base http.RoundTripper, opts ...Option) *Transport {
+	if RecordDir != "" {
+		base = &recordTransport{name: name, base: orDefault(base), dir: RecordDir}
+	}
+	if DebugOutput != nil {
+		base = &debugTransport{name: name, base: orDefault(base), w: DebugOutput}
+	}
+	t := &Transport{
+		Name:       name,
+		Base:       base,
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		MaxWait:    DefaultMaxWait,
+		Limiter:    LimiterFor(name),
+		Metrics:    DefaultMetrics,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Wrap returns a copy of client whose transport is wrapped by a Transport of the named platform,
+// the client is returned as is if its transport is already a Transport and there is no option
+func Wrap(name string, client *http.Client, opts ...Option) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := *client
+	if t, ok := client.Transport.(*Transport); ok {
+		if len(opts) == 0 {
+			return client
+		}
+		wrapped := *t
+		for _, opt := range opts {
+			opt(&wrapped)
+		}
+		c.Transport = &wrapped
+		return &c
+	}
+	c.Transport = New(name, client.Transport, opts...)
+	return &c
+}
+
+func (t *Transport) base() http.RoundTripper {
+	return orDefault(t.Base)
+}
+
+func orDefault(rt http.RoundTripper) http.RoundTripper {
+	if rt != nil {
+		return rt
+	}
+	return http.DefaultTransport
+}
+
+// retryableKey is the context key which marks a non-idempotent request as safe to retry
+type retryableKey struct{}
+
+// WithRetryable returns a copy of ctx which marks the request sent with it as safe to retry,
+// e.g. a POST request which only reads the data on the server
+func WithRetryable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableKey{}, true)
+}
+
+// isRetryable reports whether the request is idempotent or marked by WithRetryable
+func isRetryable(req *http.Request) bool {
+	if marked, _ := req.Context().Value(retryableKey{}).(bool); !marked && !isIdempotent(req.Method) {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// prepareKey is the context key of the function which prepares every attempt of a request
+type prepareKey struct{}
+
+// WithPrepare returns a copy of ctx which passes a copy of the request sent with it to prepare before every attempt,
+// e.g. to sign the request with a timestamp and a nonce which the server refuses to see twice
+func WithPrepare(ctx context.Context, prepare func(req *http.Request) error) context.Context {
+	return context.WithValue(ctx, prepareKey{}, prepare)
+}
+
+// RoundTrip sends the request, and sends it again after a while if it is idempotent or marked by WithRetryable
+// and the response is a network error, a 5xx response, 429 Too Many Requests, or an exceeded rate limit of GitHub
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := isRetryable(req)
+	prepare, _ := ctx.Value(prepareKey{}).(func(req *http.Request) error)
+
+	start := time.Now()
+	stat := Stat{Name: t.Name, Requests: 1}
+	defer func() {
+		stat.Latency = time.Since(start)
+		stat.MaxLatency = stat.Latency
+		if t.Metrics != nil {
+			t.Metrics.add(stat)
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				stat.Failures++
+				return nil, errors.Trace(err)
+			}
+		}
+
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				stat.Failures++
+				return nil, errors.Trace(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+		if prepare != nil {
+			if r == req {
+				r = req.Clone(ctx)
+			}
+			if err := prepare(r); err != nil {
+				stat.Failures++
+				return nil, errors.Trace(err)
+			}
+		}
+		stat.Attempts++
+		resp, err := t.base().RoundTrip(r)
+
+		if !retryable || attempt >= t.MaxRetries || ctx.Err() != nil {
+			if err != nil || isTransient(resp) {
+				stat.Failures++
+			}
+			return resp, err
+		}
+		wait, ok := t.retryWait(resp, err, attempt)
+		if !ok {
+			if err != nil || isTransient(resp) {
+				stat.Failures++
+			}
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		stat.Retries++
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			stat.Failures++
+			return nil, errors.Trace(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// retryWait returns how long to wait before retrying, ok is false if the request should not be retried
+func (t *Transport) retryWait(resp *http.Response, err error, attempt int) (wait time.Duration, ok bool) {
+	if err == nil && !isTransient(resp) && !isRateLimited(resp) {
+		return 0, false
+	}
+	if err == nil {
+		if d, found := serverWait(resp, time.Now()); found {
+			return d, d <= t.MaxWait
+		}
+	}
+	return t.backoff(attempt), true
+}
+
+// backoff returns the exponential backoff of the attempt with equal jitter
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.MinBackoff
+	for i := 0; i < attempt && d < t.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > t.MaxBackoff {
+		d = t.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransient reports whether the response is a failure which may succeed later
+func isTransient(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRateLimited reports whether the response is 403 Forbidden caused by the exceeded rate limit of GitHub
+// https://docs.github.com/en/rest/overview/resources-in-the-rest-api#rate-limiting
+func isRateLimited(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// serverWait returns the wait asked by the Retry-After header or the rate limit reset of GitHub
+func serverWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return nonNegative(time.Duration(seconds) * time.Second), true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return nonNegative(at.Sub(now)), true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(now)), true
+		}
+	}
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,294 @@
+package transport
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTransport returns a transport without the rate limit and with short backoffs
+func newTestTransport(name string) *Transport {
+	t := New(name, nil)
+	t.MinBackoff = time.Millisecond
+	t.MaxBackoff = 10 * time.Millisecond
+	t.MaxWait = 2 * time.Second
+	t.Limiter = nil
+	t.Metrics = NewMetrics()
+	return t
+}
+
+// flakyServer fails the first n requests with status and the headers
+func flakyServer(n int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var count int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if atomic.AddInt32(&count, 1) <= n {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write(append([]byte("ok"), body...))
+	}))
+	return s, &count
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	server, count := flakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	client := &http.Client{Transport: tr}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(" body"))
+	resp, err := client.Do(req)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok body", string(b))
+	assert.Equal(t, int32(3), atomic.LoadInt32(count))
+
+	stats := tr.Metrics.Snapshot()
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, "test", stats[0].Name)
+		assert.Equal(t, 1, stats[0].Requests)
+		assert.Equal(t, 3, stats[0].Attempts)
+		assert.Equal(t, 2, stats[0].Retries)
+		assert.Equal(t, 0, stats[0].Failures)
+		assert.True(t, stats[0].AvgLatency() > 0)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	server, count := flakyServer(1, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	client := &http.Client{Transport: tr}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("body"))
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+
+	server, count = flakyServer(1, http.StatusNotFound, nil)
+	defer server.Close()
+	resp, err = client.Get(server.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+	assert.Equal(t, 1, tr.Metrics.Snapshot()[0].Failures)
+}
+
+func TestRetryable(t *testing.T) {
+	server, count := flakyServer(1, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	req, _ := http.NewRequestWithContext(WithRetryable(context.Background()), http.MethodPost, server.URL, strings.NewReader(" body"))
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok body", string(b))
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+}
+
+func TestPrepare(t *testing.T) {
+	var nonces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.Header.Get("X-Nonce"))
+		if len(nonces) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var n int
+	ctx := WithPrepare(context.Background(), func(req *http.Request) error {
+		n++
+		req.Header.Set("X-Nonce", strconv.Itoa(n))
+		return nil
+	})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := (&http.Client{Transport: newTestTransport("test")}).Do(req)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, []string{"1", "2"}, nonces)
+	assert.Empty(t, req.Header.Get("X-Nonce"))
+}
+
+func TestMaxRetries(t *testing.T) {
+	server, count := flakyServer(10, http.StatusInternalServerError, nil)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	tr.MaxRetries = 2
+	resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(count))
+	assert.Equal(t, 1, tr.Metrics.Snapshot()[0].Failures)
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	server, count := flakyServer(1, http.StatusTooManyRequests, header)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	start := time.Now()
+	resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+	assert.True(t, time.Since(start) >= time.Second)
+
+	// The wait is longer than MaxWait
+	header = http.Header{"Retry-After": []string{"3600"}}
+	server, count = flakyServer(1, http.StatusTooManyRequests, header)
+	defer server.Close()
+	resp, err = (&http.Client{Transport: tr}).Get(server.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestServerWait(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+	resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+	_, ok := serverWait(resp, now)
+	assert.False(t, ok)
+	assert.False(t, isRateLimited(resp))
+
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(now.Unix()+30, 10))
+	assert.True(t, isRateLimited(resp))
+	d, ok := serverWait(resp, now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	resp.Header.Set("Retry-After", now.Add(10*time.Second).UTC().Format(http.TimeFormat))
+	d, ok = serverWait(resp, now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+}
+
+func TestBackoff(t *testing.T) {
+	tr := New("test", nil)
+	for attempt := 0; attempt < 10; attempt++ {
+		d := tr.backoff(attempt)
+		max := DefaultMinBackoff << uint(attempt)
+		if max > DefaultMaxBackoff {
+			max = DefaultMaxBackoff
+		}
+		assert.True(t, d >= max/2 && d <= max, "attempt %d: %s", attempt, d)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	server, count := flakyServer(10, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	tr := newTestTransport("test")
+	tr.MinBackoff, tr.MaxBackoff = time.Hour, time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := (&http.Client{Transport: tr}).Do(req)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestWrap(t *testing.T) {
+	client := Wrap("test", nil)
+	tr, ok := client.Transport.(*Transport)
+	if assert.True(t, ok) {
+		assert.Equal(t, "test", tr.Name)
+		assert.Equal(t, LimiterFor("test"), tr.Limiter)
+	}
+	assert.Nil(t, http.DefaultClient.Transport)
+	assert.Equal(t, client, Wrap("test", client))
+
+	// The limiter of a client is not shared
+	l := NewRateLimiter(1)
+	limited := Wrap("test", client, WithLimiter(l))
+	if tr, ok := limited.Transport.(*Transport); assert.True(t, ok) {
+		assert.Equal(t, l, tr.Limiter)
+	}
+	assert.Equal(t, LimiterFor("test"), client.Transport.(*Transport).Limiter)
+}
+
+func TestHTTPOptions(t *testing.T) {
+	hc := &http.Client{}
+	h := &HTTP{}
+	h.Apply(WithHTTPClient(hc), WithTimeout(time.Second), WithUserAgent("articli"))
+	// The client passed by WithHTTPClient is not modified
+	assert.Equal(t, time.Duration(0), hc.Timeout)
+	assert.Equal(t, time.Second, h.HTTPClient.Timeout)
+	assert.Equal(t, "articli", h.BrowserUserAgent())
+
+	proxy, _ := url.Parse("http://127.0.0.1:7890")
+	h.Apply(WithProxy(proxy))
+	if tr, ok := h.HTTPClient.Transport.(*http.Transport); assert.True(t, ok) {
+		u, err := tr.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "juejin.cn"}})
+		assert.Nil(t, err)
+		assert.Equal(t, proxy, u)
+	}
+	assert.Nil(t, hc.Transport)
+
+	h.Apply(WithRateLimit(-1))
+	h.Wrap("test")
+	if tr, ok := h.HTTPClient.Transport.(*Transport); assert.True(t, ok) {
+		assert.Equal(t, h.Limiter, tr.Limiter)
+		assert.NotEqual(t, LimiterFor("test"), tr.Limiter)
+	}
+	assert.Equal(t, time.Second, h.HTTPClient.Timeout)
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(100, 2)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		assert.Nil(t, l.Wait(context.Background()))
+	}
+	// 2 requests of the burst and 4 requests at 100 per second
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = NewLimiter(0.001, 1)
+	assert.Nil(t, l.Wait(ctx))
+	assert.NotNil(t, l.Wait(ctx))
+
+	l = NewLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, l.Wait(context.Background()))
+	}
+}
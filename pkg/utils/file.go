@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// lockStale is the age after which a lock file is considered left by a killed process,
+	// the locked changes of the state files take milliseconds.
+	lockStale = 10 * time.Second
+	// lockTimeout is how long LockFile waits for the lock held by another process
+	lockTimeout = 30 * time.Second
+	lockRetry   = 20 * time.Millisecond
+)
+
+// LockFile takes an exclusive lock of file shared by processes, which is the file.lock created with O_EXCL,
+// the returned unlock must be called to release it. A lock older than lockStale is taken over.
+func LockFile(file string) (unlock func(), err error) {
+	lock := file + ".lock"
+	if err = os.MkdirAll(filepath.Dir(lock), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Trace(err)
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Timeoutf("waiting for the lock %s", lock)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// WriteFileAtomic writes data to a unique temp file in the dir of file, syncs it to the disk and renames it to file,
+// so that file is never half written even if the process or the machine crashes.
+func WriteFileAtomic(file string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return errors.Trace(err)
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return errors.Trace(err)
+	}
+	if err = f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err = os.Chmod(tmp, perm); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp, file))
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	unlock, err := LockFile(file)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := LockFile(file)
+		assert.Nil(t, err)
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("the lock is taken twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+
+	// The lock left by a killed process is taken over
+	assert.Nil(t, ioutil.WriteFile(file+".lock", nil, 0644))
+	old := time.Now().Add(-2 * lockStale)
+	assert.Nil(t, os.Chtimes(file+".lock", old, old))
+	unlock, err = LockFile(file)
+	assert.Nil(t, err)
+	unlock()
+	_, err = os.Stat(file + ".lock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sub", "state.json")
+	assert.Nil(t, WriteFileAtomic(file, []byte("{}"), 0600))
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", string(b))
+
+	// No temp file is left
+	files, _ := filepath.Glob(filepath.Join(dir, "sub", "*"))
+	assert.Equal(t, []string{file}, files)
+}